@@ -2,6 +2,7 @@ package main
 
 import (
 	"api_gateway/proto/auth_service"
+	"api_gateway/proto/notification_service"
 	"api_gateway/proto/order_service"
 	"api_gateway/router" // Import routers to initialize them
 	"log"
@@ -23,6 +24,12 @@ func main() {
 	go func() {
 		log.Println("Starting server...")
 
+		// Shared with the notification service, which checks the stream tokens
+		streamSecret := os.Getenv("STREAM_SECRET")
+		if streamSecret == "" {
+			log.Fatalln("STREAM_SECRET is required")
+		}
+
 		AuthConn, err := grpc.NewClient("auth_service:9000", grpc.WithTransportCredentials(insecure.NewCredentials()))
 		if err != nil {
 			log.Fatalln(err)
@@ -49,10 +56,24 @@ func main() {
 			}
 		}()
 
+		NotificationConn, err := grpc.NewClient("notification:9000", grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			log.Fatalln(err)
+		}
+
+		log.Println("Connected to notification service")
+
+		defer func() {
+			if err := NotificationConn.Close(); err != nil {
+				log.Println(err)
+			}
+		}()
+
 		AuthClient := auth_service.NewAuthServiceClient(AuthConn)
 		OrderClient := order_service.NewOrderServiceClient(OrderConn)
+		NotificationClient := notification_service.NewNotificationServiceClient(NotificationConn)
 
-		router.InitRoutes(AuthClient, OrderClient)
+		router.InitRoutes(AuthClient, OrderClient, NotificationClient, streamSecret)
 
 		web.Run()
 	}()
@@ -2,8 +2,13 @@ package controllers
 
 import (
 	"api_gateway/proto/order_service"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/beego/beego/v2/server/web"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type AgentController struct {
@@ -15,7 +20,53 @@ func (c *AgentController) GetOrdersPage() {
 	c.TplName = "agent_orders.tpl"
 }
 
+// StartSearch lists the orders waiting for an agent. An agent calling it
+// starts a search session and receives order offers while it lasts. The body
+// may carry the agent's position and preferences:
+// {"point": {...}, "radius_km": 5, "available_from": "...", "available_until": "..."}
+// with the times in RFC 3339.
 func (c *AgentController) StartSearch() {
+	type SearchRequest struct {
+		Point          *order_service.GeoPoint `json:"point"`
+		RadiusKm       *float64                `json:"radius_km"`
+		AvailableFrom  string                  `json:"available_from"`
+		AvailableUntil string                  `json:"available_until"`
+	}
+
+	var jsonReq SearchRequest
+	if len(c.Ctx.Input.RequestBody) > 0 {
+		if err := json.Unmarshal(c.Ctx.Input.RequestBody, &jsonReq); err != nil {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Data["json"] = map[string]string{"error": "Invalid JSON request"}
+			c.ServeJSON()
+			return
+		}
+	}
+
+	var session *order_service.AgentSession
+	if role, _ := c.Ctx.Input.GetData("role").(string); role == "agent" {
+		req := &order_service.StartSearchRequest{
+			AgentId:  c.Ctx.Input.GetData("user_id").(string),
+			Point:    jsonReq.Point,
+			RadiusKm: jsonReq.RadiusKm,
+		}
+
+		fields := map[string]string{}
+		req.AvailableFrom = parseTimestamp(jsonReq.AvailableFrom, "available_from", fields)
+		req.AvailableUntil = parseTimestamp(jsonReq.AvailableUntil, "available_until", fields)
+		if len(fields) > 0 {
+			serveFieldErrors(&c.Controller, fields)
+			return
+		}
+
+		resp, err := c.OrderClient.StartSearch(c.Ctx.Request.Context(), req)
+		if err != nil {
+			serveQueueError(&c.Controller, err)
+			return
+		}
+		session = resp.Session
+	}
+
 	orders, err := c.OrderClient.GetAvailableOrders(c.Ctx.Request.Context(), &order_service.GetAvailableOrdersRequest{})
 	if err != nil {
 		c.Data["json"] = map[string]string{"error": err.Error()}
@@ -23,22 +74,279 @@ func (c *AgentController) StartSearch() {
 		return
 	}
 
-	c.Data["json"] = orders
+	c.Data["json"] = map[string]any{
+		"orders":  orders.Orders,
+		"session": session,
+	}
 	c.ServeJSON()
 }
 
+// StopSearch ends the agent's search session: no more orders are offered to
+// them. The agent stays online while the page sends heartbeats.
 func (c *AgentController) StopSearch() {
-	//TODO: implement
+	if !c.requireAgent() {
+		return
+	}
+
+	resp, err := c.OrderClient.StopSearch(c.Ctx.Request.Context(), &order_service.StopSearchRequest{
+		AgentId: c.Ctx.Input.GetData("user_id").(string),
+	})
+	if err != nil {
+		serveQueueError(&c.Controller, err)
+		return
+	}
+
+	c.Data["json"] = resp.Session
+	c.ServeJSON()
 }
 
+// Heartbeat keeps the agent online and returns their session. The body may
+// carry the agent's position as {"point": {"latitude": ..., "longitude": ...}}.
+func (c *AgentController) Heartbeat() {
+	if !c.requireAgent() {
+		return
+	}
+
+	type HeartbeatRequest struct {
+		Point *order_service.GeoPoint `json:"point"`
+	}
+
+	var jsonReq HeartbeatRequest
+	if len(c.Ctx.Input.RequestBody) > 0 {
+		if err := json.Unmarshal(c.Ctx.Input.RequestBody, &jsonReq); err != nil {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Data["json"] = map[string]string{"error": "Invalid JSON request"}
+			c.ServeJSON()
+			return
+		}
+	}
+
+	resp, err := c.OrderClient.Heartbeat(c.Ctx.Request.Context(), &order_service.HeartbeatRequest{
+		AgentId: c.Ctx.Input.GetData("user_id").(string),
+		Point:   jsonReq.Point,
+	})
+	if err != nil {
+		serveQueueError(&c.Controller, err)
+		return
+	}
+
+	c.Data["json"] = resp.Session
+	c.ServeJSON()
+}
+
+// maxLocationPoints caps the points accepted in one ReportLocation request.
+const maxLocationPoints = 100
+
+// ReportLocation forwards the agent's positions to the order service. The page
+// buffers them and sends them in batches:
+// {"points": [{"latitude": ..., "longitude": ..., "accuracy_m": ..., "speed_kmh": ..., "recorded_at": "..."}]}
+// with recorded_at in RFC 3339. The response counts accepted and rejected points.
+func (c *AgentController) ReportLocation() {
+	if !c.requireAgent() {
+		return
+	}
+
+	type LocationPoint struct {
+		Latitude   float64  `json:"latitude"`
+		Longitude  float64  `json:"longitude"`
+		AccuracyM  *float64 `json:"accuracy_m"`
+		SpeedKmh   *float64 `json:"speed_kmh"`
+		RecordedAt string   `json:"recorded_at"`
+	}
+	type LocationRequest struct {
+		Points []LocationPoint `json:"points"`
+	}
+
+	var jsonReq LocationRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &jsonReq); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Data["json"] = map[string]string{"error": "Invalid JSON request"}
+		c.ServeJSON()
+		return
+	}
+	if len(jsonReq.Points) == 0 || len(jsonReq.Points) > maxLocationPoints {
+		serveFieldErrors(&c.Controller, map[string]string{
+			"points": fmt.Sprintf("Send from 1 to %d points", maxLocationPoints),
+		})
+		return
+	}
+
+	agentID := c.Ctx.Input.GetData("user_id").(string)
+	fields := map[string]string{}
+	reqs := make([]*order_service.ReportLocationRequest, len(jsonReq.Points))
+	for i, p := range jsonReq.Points {
+		reqs[i] = &order_service.ReportLocationRequest{
+			AgentId:    agentID,
+			Point:      &order_service.GeoPoint{Latitude: p.Latitude, Longitude: p.Longitude},
+			AccuracyM:  p.AccuracyM,
+			SpeedKmh:   p.SpeedKmh,
+			RecordedAt: parseTimestamp(p.RecordedAt, fmt.Sprintf("points[%d].recorded_at", i), fields),
+		}
+	}
+	if len(fields) > 0 {
+		serveFieldErrors(&c.Controller, fields)
+		return
+	}
+
+	stream, err := c.OrderClient.ReportLocation(c.Ctx.Request.Context())
+	if err != nil {
+		serveQueueError(&c.Controller, err)
+		return
+	}
+	for _, req := range reqs {
+		// On io.EOF the server has ended the stream; CloseAndRecv returns its error
+		if err := stream.Send(req); err != nil {
+			break
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		serveQueueError(&c.Controller, err)
+		return
+	}
+
+	c.Data["json"] = map[string]int32{
+		"accepted": resp.Accepted,
+		"rejected": resp.Rejected,
+	}
+	c.ServeJSON()
+}
+
+// CurrentOffer returns the order currently offered to the agent, or 404.
+func (c *AgentController) CurrentOffer() {
+	if !c.requireAgent() {
+		return
+	}
+
+	resp, err := c.OrderClient.GetCurrentOffer(c.Ctx.Request.Context(), &order_service.GetCurrentOfferRequest{
+		AgentId: c.Ctx.Input.GetData("user_id").(string),
+	})
+	if err != nil {
+		serveQueueError(&c.Controller, err)
+		return
+	}
+
+	c.Data["json"] = resp.Offer
+	c.ServeJSON()
+}
+
+// AcceptOrder accepts the order offered to the agent; the order is assigned to them.
+// The agent never sees the order version, so there is no If-Match: the order
+// service locks the order and checks that the offer is still open and the
+// order still waits for an agent.
 func (c *AgentController) AcceptOrder() {
-	//TODO: implement
+	if !c.requireAgent() {
+		return
+	}
+
+	resp, err := c.OrderClient.AcceptOffer(c.Ctx.Request.Context(), &order_service.AcceptOfferRequest{
+		OrderId: c.Ctx.Input.Param(":id"),
+		AgentId: c.Ctx.Input.GetData("user_id").(string),
+	})
+	if err != nil {
+		serveQueueError(&c.Controller, err)
+		return
+	}
+
+	c.Data["json"] = resp.Order
+	c.ServeJSON()
 }
 
+// DeclineOrder declines the order offered to the agent; it is offered to the next one.
 func (c *AgentController) DeclineOrder() {
-	//TODO: implement
+	if !c.requireAgent() {
+		return
+	}
+
+	resp, err := c.OrderClient.DeclineOffer(c.Ctx.Request.Context(), &order_service.DeclineOfferRequest{
+		OrderId: c.Ctx.Input.Param(":id"),
+		AgentId: c.Ctx.Input.GetData("user_id").(string),
+	})
+	if err != nil {
+		serveQueueError(&c.Controller, err)
+		return
+	}
+
+	c.Data["json"] = resp
+	c.ServeJSON()
 }
 
+// JoinOrderQueue puts the agent in the queue for an order. The client of the
+// order then picks one of the agents in the queue. Like AcceptOrder, it takes
+// no If-Match: the order service locks the order and checks its status.
 func (c *AgentController) JoinOrderQueue() {
-	//TODO: implement
+	if !c.requireAgent() {
+		return
+	}
+
+	type JoinRequest struct {
+		Comment string `json:"comment"`
+	}
+
+	var jsonReq JoinRequest
+	if len(c.Ctx.Input.RequestBody) > 0 {
+		if err := json.Unmarshal(c.Ctx.Input.RequestBody, &jsonReq); err != nil {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Data["json"] = map[string]string{"error": "Invalid JSON request"}
+			c.ServeJSON()
+			return
+		}
+	}
+
+	resp, err := c.OrderClient.JoinOrderQueue(c.Ctx.Request.Context(), &order_service.JoinOrderQueueRequest{
+		OrderId: c.Ctx.Input.Param(":id"),
+		AgentId: c.Ctx.Input.GetData("user_id").(string),
+		Comment: jsonReq.Comment,
+	})
+	if err != nil {
+		serveQueueError(&c.Controller, err)
+		return
+	}
+
+	c.Data["json"] = resp
+	c.ServeJSON()
+}
+
+// LeaveOrderQueue takes the agent out of the queue for an order.
+func (c *AgentController) LeaveOrderQueue() {
+	if !c.requireAgent() {
+		return
+	}
+
+	resp, err := c.OrderClient.LeaveOrderQueue(c.Ctx.Request.Context(), &order_service.LeaveOrderQueueRequest{
+		OrderId: c.Ctx.Input.Param(":id"),
+		AgentId: c.Ctx.Input.GetData("user_id").(string),
+	})
+	if err != nil {
+		serveQueueError(&c.Controller, err)
+		return
+	}
+
+	c.Data["json"] = resp
+	c.ServeJSON()
+}
+
+// requireAgent responds with 403 unless the user is an agent.
+func (c *AgentController) requireAgent() bool {
+	if role, _ := c.Ctx.Input.GetData("role").(string); role != "agent" {
+		c.Ctx.Output.SetStatus(http.StatusForbidden)
+		c.Data["json"] = map[string]string{"error": "Only agents can do this"}
+		c.ServeJSON()
+		return false
+	}
+	return true
+}
+
+// parseTimestamp parses an optional RFC 3339 time. A bad value is reported in
+// fields under field.
+func parseTimestamp(value, field string, fields map[string]string) *timestamppb.Timestamp {
+	if value == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		fields[field] = "Invalid date format: " + err.Error()
+		return nil
+	}
+	return timestamppb.New(t)
 }
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/beego/beego/v2/server/web"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serveFieldErrors responds with 400 and the errors keyed by request field,
+// so the form can show each one next to its input.
+func serveFieldErrors(c *web.Controller, fields map[string]string) {
+	c.Ctx.Output.SetStatus(http.StatusBadRequest)
+	c.Data["json"] = map[string]any{
+		"error":  "Please correct the highlighted fields",
+		"fields": fields,
+	}
+	c.ServeJSON()
+}
+
+// serveQueueError maps an error of the agent queue, offer and session calls
+// to the HTTP status the order and agent pages expect.
+func serveQueueError(c *web.Controller, err error) {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		if fields := fieldViolations(err); len(fields) > 0 {
+			serveFieldErrors(c, fields)
+			return
+		}
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+	case codes.NotFound:
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+	case codes.PermissionDenied:
+		c.Ctx.Output.SetStatus(http.StatusForbidden)
+	case codes.AlreadyExists, codes.FailedPrecondition:
+		// Already queued, the offer ran out, the agent took another order,
+		// or the order no longer waits for an agent
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+	case codes.Aborted:
+		// The order changed since the version in If-Match
+		c.Ctx.Output.SetStatus(http.StatusPreconditionFailed)
+		c.Data["json"] = map[string]string{"error": staleOrderMessage}
+		c.ServeJSON()
+		return
+	default:
+		c.Data["json"] = map[string]string{"error": err.Error()}
+		c.ServeJSON()
+		return
+	}
+	c.Data["json"] = map[string]string{"error": status.Convert(err).Message()}
+	c.ServeJSON()
+}
+
+// fieldViolations extracts the field errors the order service attaches to
+// InvalidArgument as errdetails.BadRequest.
+func fieldViolations(err error) map[string]string {
+	fields := map[string]string{}
+	for _, detail := range status.Convert(err).Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				fields[v.GetField()] = v.GetDescription()
+			}
+		}
+	}
+	return fields
+}
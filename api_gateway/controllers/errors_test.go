@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/beego/beego/v2/server/web"
+	beecontext "github.com/beego/beego/v2/server/web/context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServeQueueError(t *testing.T) {
+	invalidField, _ := status.New(codes.InvalidArgument, "invalid session").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "radius_km", Description: "must be positive"}},
+	})
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		fields map[string]string
+	}{
+		{"field errors", invalidField.Err(), http.StatusBadRequest, map[string]string{"radius_km": "must be positive"}},
+		{"invalid argument", status.Error(codes.InvalidArgument, "bad id"), http.StatusBadRequest, nil},
+		{"not found", status.Error(codes.NotFound, "order not found"), http.StatusNotFound, nil},
+		{"not the owner", status.Error(codes.PermissionDenied, "not your order"), http.StatusForbidden, nil},
+		{"already queued", status.Error(codes.AlreadyExists, "already in queue"), http.StatusConflict, nil},
+		{"agent busy", status.Error(codes.FailedPrecondition, "agent busy"), http.StatusConflict, nil},
+		{"stale version", status.Error(codes.Aborted, "version conflict"), http.StatusPreconditionFailed, nil},
+		{"not a status", errors.New("connection refused"), http.StatusOK, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			ctx := beecontext.NewContext()
+			ctx.Reset(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+			c := &web.Controller{}
+			c.Init(ctx, "AgentController", "Join", c)
+
+			serveQueueError(c, tt.err)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+			var body struct {
+				Error  string            `json:"error"`
+				Fields map[string]string `json:"fields"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body %s: %v", rec.Body, err)
+			}
+			if body.Error == "" {
+				t.Errorf("body %s has no error", rec.Body)
+			}
+			if len(body.Fields) != len(tt.fields) || body.Fields["radius_km"] != tt.fields["radius_km"] {
+				t.Errorf("fields = %v, want %v", body.Fields, tt.fields)
+			}
+		})
+	}
+}
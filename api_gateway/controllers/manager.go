@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"api_gateway/proto/order_service"
+	"net/http"
+
+	"github.com/beego/beego/v2/server/web"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ManagerController struct {
+	web.Controller
+	OrderClient order_service.OrderServiceClient
+}
+
+func (c *ManagerController) GetAgentsPage() {
+	if !c.requireManager() {
+		return
+	}
+	c.TplName = "manager_agents.tpl"
+}
+
+// ListAgentSessions lists the agents with their state: offline, online,
+// searching or busy. The optional ?status= query keeps only one state.
+func (c *ManagerController) ListAgentSessions() {
+	if !c.requireManager() {
+		return
+	}
+
+	resp, err := c.OrderClient.ListAgentSessions(c.Ctx.Request.Context(), &order_service.ListAgentSessionsRequest{
+		Status: c.GetString("status"),
+	})
+	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Data["json"] = map[string]string{"error": status.Convert(err).Message()}
+		} else {
+			c.Data["json"] = map[string]string{"error": err.Error()}
+		}
+		c.ServeJSON()
+		return
+	}
+
+	c.Data["json"] = resp
+	c.ServeJSON()
+}
+
+// requireManager responds with 403 unless the user is a manager.
+func (c *ManagerController) requireManager() bool {
+	if role, _ := c.Ctx.Input.GetData("role").(string); role != "manager" {
+		c.Ctx.Output.SetStatus(http.StatusForbidden)
+		c.Data["json"] = map[string]string{"error": "Only managers can do this"}
+		c.ServeJSON()
+		return false
+	}
+	return true
+}
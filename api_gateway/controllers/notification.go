@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"time"
+
+	"github.com/beego/beego/v2/server/web"
+)
+
+// streamTokenTTL only has to cover opening the stream: the browser asks for a
+// fresh token before every reconnect.
+const streamTokenTTL = time.Minute
+
+type NotificationController struct {
+	web.Controller
+	StreamSecret []byte
+}
+
+// StreamToken issues the token the notification service requires on /ws and
+// /events. The stream belongs to the user of the JWT, so nobody can listen to
+// another user's events by changing an id in the URL.
+func (c *NotificationController) StreamToken() {
+	userID := c.Ctx.Input.GetData("user_id").(string)
+	expiresAt := time.Now().Add(streamTokenTTL)
+
+	c.Data["json"] = map[string]string{
+		"token":      signStreamToken(c.StreamSecret, userID, expiresAt),
+		"expires_at": expiresAt.UTC().Format(time.RFC3339),
+	}
+	c.ServeJSON()
+}
+
+// signStreamToken builds "<user_id>.<expiry unix>.<signature>", where the
+// signature is HMAC-SHA256 of "<user_id>.<expiry unix>" with the secret shared
+// with the notification service.
+func signStreamToken(secret []byte, userID string, expiresAt time.Time) string {
+	payload := userID + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	beecontext "github.com/beego/beego/v2/server/web/context"
+)
+
+// The notification service verifies the same vector in its hub tests.
+func TestSignStreamToken(t *testing.T) {
+	got := signStreamToken([]byte("test-stream-secret"), "3f1c2a9e-5b7d-4e21-9c0a-6d8f4b2e1a77", time.Unix(1800000060, 0))
+	want := "3f1c2a9e-5b7d-4e21-9c0a-6d8f4b2e1a77.1800000060.xrQbkUtk7xQ4293DAEQEhiuIEExPnNs5fHS1dEi2R3g"
+	if got != want {
+		t.Errorf("token = %s, want %s", got, want)
+	}
+}
+
+func TestStreamToken(t *testing.T) {
+	userID := "3f1c2a9e-5b7d-4e21-9c0a-6d8f4b2e1a77"
+	rec := httptest.NewRecorder()
+	ctx := beecontext.NewContext()
+	ctx.Reset(rec, httptest.NewRequest(http.MethodGet, "/api/notifications/token?user_id=someone-else", nil))
+	ctx.Input.SetData("user_id", userID)
+	c := &NotificationController{StreamSecret: []byte("test-stream-secret")}
+	c.Init(ctx, "NotificationController", "StreamToken", c)
+
+	before := time.Now()
+	c.StreamToken()
+
+	var body struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body %s: %v", rec.Body, err)
+	}
+	if !strings.HasPrefix(body.Token, userID+".") {
+		t.Errorf("token %s is not for %s", body.Token, userID)
+	}
+	if body.Token != signStreamToken(c.StreamSecret, userID, body.ExpiresAt) {
+		t.Errorf("token %s does not match expires_at %s", body.Token, body.ExpiresAt)
+	}
+	if ttl := body.ExpiresAt.Sub(before); ttl <= 0 || ttl > streamTokenTTL {
+		t.Errorf("token lives %s, want at most %s", ttl, streamTokenTTL)
+	}
+}
@@ -3,10 +3,17 @@ package controllers
 import (
 	"api_gateway/proto/order_service"
 	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/beego/beego/v2/server/web"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -37,6 +44,29 @@ func (c *OrderController) GetOrdersList() {
 	c.ServeJSON()
 }
 
+// GetCurrentOrder returns the user's active order, or 404 if there is none.
+func (c *OrderController) GetCurrentOrder() {
+	userID := c.Ctx.Input.GetData("user_id").(string)
+
+	resp, err := c.OrderClient.GetCurrentOrder(c.Ctx.Request.Context(), &order_service.GetCurrentOrderRequest{
+		UserId: userID,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			c.Ctx.Output.SetStatus(http.StatusNotFound)
+			c.Data["json"] = map[string]string{"error": "No active order"}
+		} else {
+			c.Data["json"] = map[string]string{"error": err.Error()}
+		}
+		c.ServeJSON()
+		return
+	}
+
+	c.setETag(resp.Order)
+	c.Data["json"] = resp.Order
+	c.ServeJSON()
+}
+
 func (c *OrderController) CreateOrder() {
 	// Define a struct to unmarshal the JSON data with string timestamps
 	type OrderRequest struct {
@@ -45,6 +75,9 @@ func (c *OrderController) CreateOrder() {
 		OrderLocation string `json:"order_location"`
 		OrderDate     string `json:"order_date"`
 		OrderTimeGap  string `json:"order_time_gap"`
+		Notes         string `json:"notes"`
+		// Optional coordinates of the place; the order is offered to the nearest agents
+		Point *order_service.GeoPoint `json:"point"`
 	}
 
 	var jsonReq OrderRequest
@@ -56,19 +89,22 @@ func (c *OrderController) CreateOrder() {
 		return
 	}
 
-	// Create the protobuf request
+	// Create the protobuf request. Retries with the same Idempotency-Key
+	// return the order created by the first request.
 	req := &order_service.CreateOrderRequest{
-		UserId:        c.Ctx.Input.GetData("user_id").(string),
-		OrderAddress:  jsonReq.OrderAddress,
-		OrderLocation: jsonReq.OrderLocation,
+		UserId:         c.Ctx.Input.GetData("user_id").(string),
+		OrderAddress:   jsonReq.OrderAddress,
+		OrderLocation:  jsonReq.OrderLocation,
+		IdempotencyKey: c.Ctx.Input.Header("Idempotency-Key"),
+		Notes:          jsonReq.Notes,
+		Point:          jsonReq.Point,
 	}
 
 	// Parse and convert the order_date string to a timestamppb.Timestamp
 	if jsonReq.OrderDate != "" {
 		orderDate, err := time.Parse(time.RFC3339, jsonReq.OrderDate)
 		if err != nil {
-			c.Data["json"] = map[string]string{"error": "Invalid order_date format: " + err.Error()}
-			c.ServeJSON()
+			serveFieldErrors(&c.Controller, map[string]string{"order_date": "Invalid date format: " + err.Error()})
 			return
 		}
 		req.OrderDate = timestamppb.New(orderDate)
@@ -78,21 +114,98 @@ func (c *OrderController) CreateOrder() {
 	if jsonReq.OrderTimeGap != "" {
 		orderTimeGap, err := time.ParseDuration(jsonReq.OrderTimeGap)
 		if err != nil {
-			c.Data["json"] = map[string]string{"error": "Invalid order_time_gap format: " + err.Error()}
-			c.ServeJSON()
+			serveFieldErrors(&c.Controller, map[string]string{"order_time_gap": "Invalid duration format: " + err.Error()})
 			return
 		}
 		req.OrderTimeGap = durationpb.New(orderTimeGap)
 	}
 
-	_, err = c.OrderClient.CreateOrder(c.Ctx.Request.Context(), req)
+	resp, err := c.OrderClient.CreateOrder(c.Ctx.Request.Context(), req)
 	if err != nil {
-		c.Data["json"] = map[string]string{"error": err.Error()}
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+			if fields := fieldViolations(err); len(fields) > 0 {
+				serveFieldErrors(&c.Controller, fields)
+				return
+			}
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Data["json"] = map[string]string{"error": status.Convert(err).Message()}
+		case codes.AlreadyExists:
+			// Clients may have only one active order at a time
+			c.Ctx.Output.SetStatus(http.StatusConflict)
+			c.Data["json"] = map[string]string{"error": "You already have an active order"}
+		case codes.FailedPrecondition:
+			// The Idempotency-Key was already used for an order with different data
+			c.Ctx.Output.SetStatus(http.StatusUnprocessableEntity)
+			c.Data["json"] = map[string]string{"error": status.Convert(err).Message()}
+		default:
+			c.Data["json"] = map[string]string{"error": err.Error()}
+		}
 		c.ServeJSON()
 		return
 	}
 
-	c.Data["json"] = map[string]string{"success": "Order created successfully"}
+	// Respond with the created order so the client doesn't have to reload the list
+	c.Ctx.Output.Header("Location", "/api/orders/"+resp.Order.GetOrderId())
+	c.setETag(resp.Order)
+	c.Ctx.Output.SetStatus(http.StatusCreated)
+	c.Data["json"] = resp.Order
+	c.ServeJSON()
+}
+
+// staleOrderMessage is shown when a write was based on an old version of the order.
+const staleOrderMessage = "The order has been changed in the meantime. Reload it and try again."
+
+// setETag sends the order version as the ETag, so the client can make its
+// next write conditional with If-Match.
+func (c *OrderController) setETag(order *order_service.Order) {
+	if order.GetEtag() != "" {
+		c.Ctx.Output.Header("ETag", order.GetEtag())
+	}
+}
+
+// ifMatchVersion returns the order version from the If-Match header, or 0 if
+// the header is absent or "*" and the write doesn't depend on the version.
+func (c *OrderController) ifMatchVersion() (int64, error) {
+	tag := strings.TrimSpace(c.Ctx.Input.Header("If-Match"))
+	if tag == "" || tag == "*" {
+		return 0, nil
+	}
+	// Versions are compared exactly, so a weak tag means the same as a strong one
+	tag = strings.TrimPrefix(tag, "W/")
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		return 0, errors.New("If-Match must be a single ETag")
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errors.New("If-Match does not match any order version")
+	}
+	return version, nil
+}
+
+// requireIfMatchVersion is ifMatchVersion for writes that must be based on a
+// version the user has seen. Without a version in If-Match it responds with
+// 428 and returns false.
+func (c *OrderController) requireIfMatchVersion() (int64, bool) {
+	version, err := c.ifMatchVersion()
+	if err != nil {
+		c.serveBadIfMatch(err)
+		return 0, false
+	}
+	if version == 0 {
+		c.Ctx.Output.SetStatus(http.StatusPreconditionRequired)
+		c.Data["json"] = map[string]string{"error": "If-Match with the order's ETag is required"}
+		c.ServeJSON()
+		return 0, false
+	}
+	return version, true
+}
+
+// serveBadIfMatch responds with 400 to an If-Match header that can't be parsed.
+func (c *OrderController) serveBadIfMatch(err error) {
+	c.Ctx.Output.SetStatus(http.StatusBadRequest)
+	c.Data["json"] = map[string]string{"error": err.Error()}
 	c.ServeJSON()
 }
 
@@ -108,34 +221,258 @@ func (c *OrderController) GetOrderById() {
 		return
 	}
 
+	c.setETag(order.GetOrder())
 	c.Data["json"] = order
 	c.ServeJSON()
 }
 
+// UpdateOrder changes the fields present in the JSON body. The version of the
+// order the user edited comes in If-Match or in the body's "version"; if the
+// order has changed since, nothing is updated and 412 (If-Match) or 409 is
+// returned.
+func (c *OrderController) UpdateOrder() {
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &body); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Data["json"] = map[string]string{"error": "Invalid JSON request"}
+		c.ServeJSON()
+		return
+	}
+
+	version, err := c.ifMatchVersion()
+	if err != nil {
+		c.serveBadIfMatch(err)
+		return
+	}
+	conditional := version != 0
+	if raw, ok := body["version"]; ok {
+		if !conditional {
+			if err := json.Unmarshal(raw, &version); err != nil {
+				serveFieldErrors(&c.Controller, map[string]string{"version": "Order version must be a number"})
+				return
+			}
+		}
+		delete(body, "version")
+	}
+	if version == 0 {
+		serveFieldErrors(&c.Controller, map[string]string{"version": "Order version is required"})
+		return
+	}
+
+	order := &order_service.Order{}
+	fields := map[string]string{}
+	var paths []string
+	for field, raw := range body {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			fields[field] = "Must be a string"
+			continue
+		}
+
+		switch field {
+		case "order_address":
+			order.OrderAddress = value
+		case "order_location":
+			order.OrderLocation = value
+		case "notes":
+			order.Notes = value
+		case "order_date":
+			orderDate, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				fields[field] = "Invalid date format: " + err.Error()
+				continue
+			}
+			order.OrderDate = timestamppb.New(orderDate)
+		case "order_time_gap":
+			orderTimeGap, err := time.ParseDuration(value)
+			if err != nil {
+				fields[field] = "Invalid duration format: " + err.Error()
+				continue
+			}
+			order.OrderTimeGap = durationpb.New(orderTimeGap)
+		default:
+			fields[field] = "This field can't be changed"
+			continue
+		}
+		paths = append(paths, field)
+	}
+	if len(fields) > 0 {
+		serveFieldErrors(&c.Controller, fields)
+		return
+	}
+
+	resp, err := c.OrderClient.UpdateOrder(c.Ctx.Request.Context(), &order_service.UpdateOrderRequest{
+		OrderId:    c.Ctx.Input.Param(":id"),
+		UserId:     c.Ctx.Input.GetData("user_id").(string),
+		Order:      order,
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: paths},
+		Version:    version,
+	})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.InvalidArgument:
+			if fields := fieldViolations(err); len(fields) > 0 {
+				serveFieldErrors(&c.Controller, fields)
+				return
+			}
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		case codes.NotFound:
+			c.Ctx.Output.SetStatus(http.StatusNotFound)
+		case codes.PermissionDenied:
+			c.Ctx.Output.SetStatus(http.StatusForbidden)
+		case codes.FailedPrecondition:
+			c.Ctx.Output.SetStatus(http.StatusUnprocessableEntity)
+		case codes.Aborted:
+			// Someone else changed the order after the user loaded it
+			if conditional {
+				c.Ctx.Output.SetStatus(http.StatusPreconditionFailed)
+			} else {
+				c.Ctx.Output.SetStatus(http.StatusConflict)
+			}
+			c.Data["json"] = map[string]string{"error": staleOrderMessage}
+			c.ServeJSON()
+			return
+		default:
+			c.Data["json"] = map[string]string{"error": err.Error()}
+			c.ServeJSON()
+			return
+		}
+		c.Data["json"] = map[string]string{"error": status.Convert(err).Message()}
+		c.ServeJSON()
+		return
+	}
+
+	c.setETag(resp.Order)
+	c.Data["json"] = resp.Order
+	c.ServeJSON()
+}
+
+// GetCancellationTerms tells the user what cancelling the order would cost
+// them, so the page can show it before they confirm.
+func (c *OrderController) GetCancellationTerms() {
+	orderID := c.Ctx.Input.Param(":id")
+
+	resp, err := c.OrderClient.GetCancellationTerms(c.Ctx.Request.Context(), &order_service.GetCancellationTermsRequest{
+		OrderId:     orderID,
+		CancelledBy: c.Ctx.Input.GetData("role").(string),
+		ActorId:     c.Ctx.Input.GetData("user_id").(string),
+	})
+	if err != nil {
+		c.serveCancelError(err)
+		return
+	}
+
+	c.Data["json"] = resp
+	c.ServeJSON()
+}
+
 func (c *OrderController) CancelOrder() {
+	type CancelRequest struct {
+		Reason          string `json:"reason"`
+		Comment         string `json:"comment"`
+		AcceptedPenalty string `json:"accepted_penalty"`
+	}
+
+	var jsonReq CancelRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &jsonReq); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Data["json"] = map[string]string{"error": "Invalid JSON request"}
+		c.ServeJSON()
+		return
+	}
+
 	orderID := c.Ctx.Input.Param(":id")
 
-	_, err := c.OrderClient.CancelOrder(c.Ctx.Request.Context(), &order_service.CancelOrderRequest{
-		OrderId: orderID,
+	version, err := c.ifMatchVersion()
+	if err != nil {
+		c.serveBadIfMatch(err)
+		return
+	}
+
+	// The role decides who cancelled the order and which policy applies
+	resp, err := c.OrderClient.CancelOrder(c.Ctx.Request.Context(), &order_service.CancelOrderRequest{
+		OrderId:         orderID,
+		Reason:          jsonReq.Reason,
+		Comment:         jsonReq.Comment,
+		CancelledBy:     c.Ctx.Input.GetData("role").(string),
+		ActorId:         c.Ctx.Input.GetData("user_id").(string),
+		AcceptedPenalty: jsonReq.AcceptedPenalty,
+		Version:         version,
 	})
 	if err != nil {
+		c.serveCancelError(err)
+		return
+	}
+
+	c.Data["json"] = map[string]any{
+		"success":      "Order cancelled successfully",
+		"cancellation": resp.Cancellation,
+	}
+	c.ServeJSON()
+}
+
+func (c *OrderController) serveCancelError(err error) {
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		if fields := fieldViolations(err); len(fields) > 0 {
+			serveFieldErrors(&c.Controller, fields)
+			return
+		}
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+	case codes.NotFound:
+		c.Ctx.Output.SetStatus(http.StatusNotFound)
+	case codes.PermissionDenied:
+		c.Ctx.Output.SetStatus(http.StatusForbidden)
+	case codes.FailedPrecondition:
+		// The order is no longer active, or the terms changed since they were shown
+		c.Ctx.Output.SetStatus(http.StatusConflict)
+	case codes.Aborted:
+		// The order changed since the version in If-Match
+		c.Ctx.Output.SetStatus(http.StatusPreconditionFailed)
+		c.Data["json"] = map[string]string{"error": staleOrderMessage}
+		c.ServeJSON()
+		return
+	default:
 		c.Data["json"] = map[string]string{"error": err.Error()}
 		c.ServeJSON()
 		return
 	}
-
-	c.Data["json"] = map[string]string{"success": "Order cancelled successfully"}
+	c.Data["json"] = map[string]string{"error": status.Convert(err).Message()}
 	c.ServeJSON()
 }
 
 func (c *OrderController) CompleteOrder() {
 	orderID := c.Ctx.Input.Param(":id")
 
-	_, err := c.OrderClient.CompleteOrder(c.Ctx.Request.Context(), &order_service.CompleteOrderRequest{
+	version, err := c.ifMatchVersion()
+	if err != nil {
+		c.serveBadIfMatch(err)
+		return
+	}
+
+	_, err = c.OrderClient.CompleteOrder(c.Ctx.Request.Context(), &order_service.CompleteOrderRequest{
 		OrderId: orderID,
+		Version: version,
 	})
 	if err != nil {
-		c.Data["json"] = map[string]string{"error": err.Error()}
+		switch status.Code(err) {
+		case codes.NotFound:
+			c.Ctx.Output.SetStatus(http.StatusNotFound)
+		case codes.FailedPrecondition:
+			// The order was already completed, cancelled or expired
+			c.Ctx.Output.SetStatus(http.StatusConflict)
+		case codes.Aborted:
+			// The order changed since the version in If-Match
+			c.Ctx.Output.SetStatus(http.StatusPreconditionFailed)
+			c.Data["json"] = map[string]string{"error": staleOrderMessage}
+			c.ServeJSON()
+			return
+		default:
+			c.Data["json"] = map[string]string{"error": err.Error()}
+			c.ServeJSON()
+			return
+		}
+		c.Data["json"] = map[string]string{"error": status.Convert(err).Message()}
 		c.ServeJSON()
 		return
 	}
@@ -143,3 +480,89 @@ func (c *OrderController) CompleteOrder() {
 	c.Data["json"] = map[string]string{"success": "Order completed successfully"}
 	c.ServeJSON()
 }
+
+// ListCandidates returns the agents queued for the user's order.
+func (c *OrderController) ListCandidates() {
+	resp, err := c.OrderClient.ListCandidates(c.Ctx.Request.Context(), &order_service.ListCandidatesRequest{
+		OrderId: c.Ctx.Input.Param(":id"),
+		UserId:  c.Ctx.Input.GetData("user_id").(string),
+	})
+	if err != nil {
+		serveQueueError(&c.Controller, err)
+		return
+	}
+
+	c.setETag(resp.Order)
+	c.Data["json"] = resp
+	c.ServeJSON()
+}
+
+// GetAgentLocation returns where the agent of the user's signed order is and
+// when they arrive. The fields are those of the order.agent_location event, so
+// the page renders both the same way.
+func (c *OrderController) GetAgentLocation() {
+	resp, err := c.OrderClient.GetAgentLocation(c.Ctx.Request.Context(), &order_service.GetAgentLocationRequest{
+		OrderId: c.Ctx.Input.Param(":id"),
+		UserId:  c.Ctx.Input.GetData("user_id").(string),
+	})
+	if err != nil {
+		serveQueueError(&c.Controller, err)
+		return
+	}
+
+	location := map[string]any{
+		"agent_id":    resp.Location.GetAgentId(),
+		"latitude":    resp.Location.GetPoint().GetLatitude(),
+		"longitude":   resp.Location.GetPoint().GetLongitude(),
+		"recorded_at": resp.Location.GetRecordedAt().AsTime().Format(time.RFC3339),
+	}
+	if resp.Location.AccuracyM != nil {
+		location["accuracy_m"] = resp.Location.GetAccuracyM()
+	}
+	if resp.DistanceKm != nil {
+		location["distance_km"] = resp.GetDistanceKm()
+	}
+	if resp.Eta != nil {
+		location["eta"] = strconv.FormatInt(int64(resp.Eta.AsDuration().Seconds()), 10) + "s"
+	}
+
+	c.Data["json"] = location
+	c.ServeJSON()
+}
+
+// SelectAgent assigns one of the queued agents to the user's order. The other
+// agents are released. The user picks from the queue they have seen, so
+// If-Match is required: 428 without it, 412 if the order has changed since.
+func (c *OrderController) SelectAgent() {
+	type SelectRequest struct {
+		AgentID string `json:"agent_id"`
+	}
+
+	var jsonReq SelectRequest
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &jsonReq); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Data["json"] = map[string]string{"error": "Invalid JSON request"}
+		c.ServeJSON()
+		return
+	}
+
+	version, ok := c.requireIfMatchVersion()
+	if !ok {
+		return
+	}
+
+	resp, err := c.OrderClient.SelectAgent(c.Ctx.Request.Context(), &order_service.SelectAgentRequest{
+		OrderId: c.Ctx.Input.Param(":id"),
+		UserId:  c.Ctx.Input.GetData("user_id").(string),
+		AgentId: jsonReq.AgentID,
+		Version: version,
+	})
+	if err != nil {
+		serveQueueError(&c.Controller, err)
+		return
+	}
+
+	c.setETag(resp.Order)
+	c.Data["json"] = resp.Order
+	c.ServeJSON()
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"api_gateway/proto/order_service"
+
+	beecontext "github.com/beego/beego/v2/server/web/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// versionedOrders answers SelectAgent like the order service: the write goes
+// through only if the request's version is the current one.
+type versionedOrders struct {
+	order_service.OrderServiceClient
+	version int64
+	calls   int
+}
+
+func (f *versionedOrders) SelectAgent(ctx context.Context, req *order_service.SelectAgentRequest, opts ...grpc.CallOption) (*order_service.SelectAgentResponse, error) {
+	f.calls++
+	if req.Version != f.version {
+		return nil, status.Error(codes.Aborted, "order version conflict")
+	}
+	f.version++
+	return &order_service.SelectAgentResponse{Order: &order_service.Order{OrderId: req.OrderId, AgentId: req.AgentId}}, nil
+}
+
+// serveSelectAgent runs SelectAgent for a request with the given If-Match.
+func serveSelectAgent(client order_service.OrderServiceClient, ifMatch string) *httptest.ResponseRecorder {
+	body := `{"agent_id": "agent-1"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/orders/order-1/select", strings.NewReader(body))
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	rec := httptest.NewRecorder()
+
+	ctx := beecontext.NewContext()
+	ctx.Reset(rec, req)
+	ctx.Input.RequestBody = []byte(body)
+	ctx.Input.SetParam(":id", "order-1")
+	ctx.Input.SetData("user_id", "user-1")
+
+	c := &OrderController{OrderClient: client}
+	c.Init(ctx, "OrderController", "SelectAgent", c)
+	c.SelectAgent()
+	return rec
+}
+
+func TestSelectAgentIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		status  int
+		called  bool
+	}{
+		{"current version", `"3"`, http.StatusOK, true},
+		{"weak current version", `W/"3"`, http.StatusOK, true},
+		{"stale version", `"2"`, http.StatusPreconditionFailed, true},
+		{"missing", "", http.StatusPreconditionRequired, false},
+		{"any version", "*", http.StatusPreconditionRequired, false},
+		{"malformed", "3", http.StatusBadRequest, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &versionedOrders{version: 3}
+			rec := serveSelectAgent(client, tt.ifMatch)
+
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d; body %s", rec.Code, tt.status, rec.Body)
+			}
+			if called := client.calls > 0; called != tt.called {
+				t.Errorf("order service called = %v, want %v", called, tt.called)
+			}
+		})
+	}
+}
+
+func TestSelectAgentStaleAfterChange(t *testing.T) {
+	client := &versionedOrders{version: 3}
+
+	if rec := serveSelectAgent(client, `"3"`); rec.Code != http.StatusOK {
+		t.Fatalf("first select: status = %d, body %s", rec.Code, rec.Body)
+	}
+	// The second request carries the same ETag, but the first one changed the order
+	rec := serveSelectAgent(client, `"3"`)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("second select: status = %d, want 412", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), staleOrderMessage) {
+		t.Errorf("second select: body %s doesn't explain the conflict", rec.Body)
+	}
+}
@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"api_gateway/proto/notification_service"
+
+	"github.com/beego/beego/v2/server/web"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type SettingsController struct {
+	web.Controller
+	NotificationClient notification_service.NotificationServiceClient
+}
+
+type PreferencesRequest struct {
+	Email      string `json:"email"`
+	Phone      string `json:"phone"`
+	WebhookUrl string `json:"webhook_url"`
+	Channels   []struct {
+		EventType string `json:"event_type"`
+		Channel   string `json:"channel"`
+		Enabled   bool   `json:"enabled"`
+	} `json:"channels"`
+	QuietHours struct {
+		Enabled  bool   `json:"enabled"`
+		Start    string `json:"start"`
+		End      string `json:"end"`
+		Timezone string `json:"timezone"`
+	} `json:"quiet_hours"`
+	DigestMode string `json:"digest_mode"`
+}
+
+func (c *SettingsController) GetSettingsPage() {
+	userID := c.Ctx.Input.GetData("user_id").(string)
+	c.Data["user_id"] = userID
+	c.TplName = "settings.tpl"
+}
+
+func (c *SettingsController) GetPreferences() {
+	userID := c.Ctx.Input.GetData("user_id").(string)
+
+	resp, err := c.NotificationClient.GetPreferences(c.Ctx.Request.Context(), &notification_service.GetPreferencesRequest{
+		UserId: userID,
+	})
+	if err != nil {
+		c.Data["json"] = map[string]string{"error": err.Error()}
+		c.ServeJSON()
+		return
+	}
+
+	c.Data["json"] = resp
+	c.ServeJSON()
+}
+
+func (c *SettingsController) UpdatePreferences() {
+	var jsonReq PreferencesRequest
+
+	if err := json.Unmarshal(c.Ctx.Input.RequestBody, &jsonReq); err != nil {
+		c.Ctx.Output.SetStatus(http.StatusBadRequest)
+		c.Data["json"] = map[string]string{"error": "Invalid JSON request"}
+		c.ServeJSON()
+		return
+	}
+
+	prefs := &notification_service.Preferences{
+		UserId:     c.Ctx.Input.GetData("user_id").(string),
+		Email:      jsonReq.Email,
+		Phone:      jsonReq.Phone,
+		WebhookUrl: jsonReq.WebhookUrl,
+		QuietHours: &notification_service.QuietHours{
+			Enabled:  jsonReq.QuietHours.Enabled,
+			Start:    jsonReq.QuietHours.Start,
+			End:      jsonReq.QuietHours.End,
+			Timezone: jsonReq.QuietHours.Timezone,
+		},
+		DigestMode: jsonReq.DigestMode,
+	}
+	for _, ch := range jsonReq.Channels {
+		prefs.Channels = append(prefs.Channels, &notification_service.ChannelPreference{
+			EventType: ch.EventType,
+			Channel:   ch.Channel,
+			Enabled:   ch.Enabled,
+		})
+	}
+
+	resp, err := c.NotificationClient.UpdatePreferences(c.Ctx.Request.Context(), &notification_service.UpdatePreferencesRequest{
+		Preferences: prefs,
+	})
+	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			c.Ctx.Output.SetStatus(http.StatusBadRequest)
+			c.Data["json"] = map[string]string{"error": status.Convert(err).Message()}
+		} else {
+			c.Data["json"] = map[string]string{"error": err.Error()}
+		}
+		c.ServeJSON()
+		return
+	}
+
+	c.Data["json"] = resp
+	c.ServeJSON()
+}
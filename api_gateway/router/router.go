@@ -4,12 +4,13 @@ import (
 	"api_gateway/controllers"
 	"api_gateway/middleware"
 	"api_gateway/proto/auth_service"
+	"api_gateway/proto/notification_service"
 	"api_gateway/proto/order_service"
 
 	"github.com/beego/beego/v2/server/web"
 )
 
-func InitRoutes(authClient auth_service.AuthServiceClient, orderClient order_service.OrderServiceClient) {
+func InitRoutes(authClient auth_service.AuthServiceClient, orderClient order_service.OrderServiceClient, notificationClient notification_service.NotificationServiceClient, streamSecret string) {
 	// Root route
 	web.Router("/", &controllers.GatewayController{}, "get:GetIndex")
 
@@ -29,18 +30,45 @@ func InitRoutes(authClient auth_service.AuthServiceClient, orderClient order_ser
 	web.InsertFilter("/agent/orders", web.BeforeRouter, middleware.JWTAuthMiddleware(authClient))
 	web.Router("/agent/orders", &controllers.AgentController{OrderClient: orderClient}, "get:GetOrdersPage")
 
+	// Agent sessions for managers - protected with JWT authentication
+	web.InsertFilter("/manager/agents", web.BeforeRouter, middleware.JWTAuthMiddleware(authClient))
+	web.Router("/manager/agents", &controllers.ManagerController{OrderClient: orderClient}, "get:GetAgentsPage")
+
+	web.InsertFilter("/api/agents", web.BeforeRouter, middleware.JWTAuthMiddleware(authClient))
+	web.Router("/api/agents", &controllers.ManagerController{OrderClient: orderClient}, "get:ListAgentSessions")
+
+	// Notification settings - protected with JWT authentication
+	web.InsertFilter("/settings", web.BeforeRouter, middleware.JWTAuthMiddleware(authClient))
+	web.Router("/settings", &controllers.SettingsController{NotificationClient: notificationClient}, "get:GetSettingsPage")
+
+	web.InsertFilter("/api/settings/*", web.BeforeRouter, middleware.JWTAuthMiddleware(authClient))
+	web.Router("/api/settings/notifications", &controllers.SettingsController{NotificationClient: notificationClient}, "get:GetPreferences")
+	web.Router("/api/settings/notifications", &controllers.SettingsController{NotificationClient: notificationClient}, "post:UpdatePreferences")
+
+	// Tokens for the notification streams - protected with JWT authentication
+	web.InsertFilter("/api/notifications/*", web.BeforeRouter, middleware.JWTAuthMiddleware(authClient))
+	web.Router("/api/notifications/token", &controllers.NotificationController{StreamSecret: []byte(streamSecret)}, "get:StreamToken")
+
 	// Order API routes - protected with JWT authentication
 	web.InsertFilter("/api/orders/*", web.BeforeRouter, middleware.JWTAuthMiddleware(authClient))
 	web.Router("/api/orders/create", &controllers.OrderController{OrderClient: orderClient}, "post:CreateOrder")
 	web.Router("/api/orders/list", &controllers.OrderController{OrderClient: orderClient}, "get:GetOrdersList")
-	web.Router("/api/orders/:id", &controllers.OrderController{OrderClient: orderClient}, "get:GetOrderById")
-	web.Router("/api/orders/:id/cancel", &controllers.OrderController{OrderClient: orderClient}, "post:CancelOrder")
+	web.Router("/api/orders/current", &controllers.OrderController{OrderClient: orderClient}, "get:GetCurrentOrder")
+	web.Router("/api/orders/:id", &controllers.OrderController{OrderClient: orderClient}, "get:GetOrderById;patch:UpdateOrder")
+	web.Router("/api/orders/:id/cancel", &controllers.OrderController{OrderClient: orderClient}, "get:GetCancellationTerms;post:CancelOrder")
 	web.Router("/api/orders/:id/complete", &controllers.OrderController{OrderClient: orderClient}, "post:CompleteOrder")
+	web.Router("/api/orders/:id/candidates", &controllers.OrderController{OrderClient: orderClient}, "get:ListCandidates")
+	web.Router("/api/orders/:id/select", &controllers.OrderController{OrderClient: orderClient}, "post:SelectAgent")
+	web.Router("/api/orders/:id/agent_location", &controllers.OrderController{OrderClient: orderClient}, "get:GetAgentLocation")
 
 	web.InsertFilter("/api/agent/*", web.BeforeRouter, middleware.JWTAuthMiddleware(authClient))
+	web.Router("/api/agent/offer", &controllers.AgentController{OrderClient: orderClient}, "get:CurrentOffer")
+	web.Router("/api/agent/heartbeat", &controllers.AgentController{OrderClient: orderClient}, "post:Heartbeat")
+	web.Router("/api/agent/location", &controllers.AgentController{OrderClient: orderClient}, "post:ReportLocation")
 	web.Router("/api/orders/start_search", &controllers.AgentController{OrderClient: orderClient}, "post:StartSearch")
 	web.Router("/api/orders/stop_search", &controllers.AgentController{OrderClient: orderClient}, "post:StopSearch")
 	web.Router("/api/orders/:id/accept", &controllers.AgentController{OrderClient: orderClient}, "post:AcceptOrder")
 	web.Router("/api/orders/:id/decline", &controllers.AgentController{OrderClient: orderClient}, "post:DeclineOrder")
 	web.Router("/api/orders/:id/join", &controllers.AgentController{OrderClient: orderClient}, "post:JoinOrderQueue")
+	web.Router("/api/orders/:id/leave", &controllers.AgentController{OrderClient: orderClient}, "post:LeaveOrderQueue")
 }
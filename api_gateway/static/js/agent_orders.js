@@ -5,32 +5,219 @@ document.addEventListener('DOMContentLoaded', function() {
     const orderDetailsSection = document.getElementById('orderDetailsSection');
     const backToListBtn = document.getElementById('backToList');
     const acceptOrderBtn = document.getElementById('acceptOrderBtn');
+    const joinQueueBtn = document.getElementById('joinQueueBtn');
+    const leaveQueueBtn = document.getElementById('leaveQueueBtn');
+    const queueComment = document.getElementById('queueComment');
+    const queueCommentError = document.getElementById('queueCommentError');
+    const queueErrorAlert = document.getElementById('queueErrorAlert');
+    const queueSuccessAlert = document.getElementById('queueSuccessAlert');
+    const offerPanel = document.getElementById('offerPanel');
+    const offerCountdown = document.getElementById('offerCountdown');
+    const offerError = document.getElementById('offerError');
+    const acceptOfferBtn = document.getElementById('acceptOfferBtn');
+    const declineOfferBtn = document.getElementById('declineOfferBtn');
+    const stopSearchBtn = document.getElementById('stopSearch');
+    const agentStatus = document.getElementById('agentStatus');
+    const searchRadius = document.getElementById('searchRadius');
+    const searchUntil = document.getElementById('searchUntil');
+    const searchErrorAlert = document.getElementById('searchErrorAlert');
 
     let currentOrderId = null;
+    // While the agent is searching, orders are offered to them one at a time
+    let currentOffer = null;
+    let offerPollTimer = null;
+    let offerCountdownTimer = null;
+    const offerPollInterval = 5000;
+    // The agent goes offline when heartbeats stop for AGENT_SESSION_HEARTBEAT_TIMEOUT
+    const heartbeatInterval = 20000;
+    // While the agent has an order in progress, their position is sent to the
+    // client in batches
+    let locationWatchId = null;
+    let locationFlushTimer = null;
+    let locationBuffer = [];
+    const locationFlushInterval = 5000;
+    const maxLocationBuffer = 100;
+
+    const statusBadges = {
+        searching: 'success',
+        busy: 'primary',
+        online: 'info',
+        offline: 'secondary'
+    };
+
+    function showSession(session) {
+        const status = (session && session.status) || 'offline';
+        agentStatus.textContent = status;
+        agentStatus.className = `badge bg-${statusBadges[status] || 'secondary'}`;
+        stopSearchBtn.style.display = status === 'searching' ? 'inline-block' : 'none';
+        if (status === 'searching') {
+            startOfferPolling();
+        } else {
+            stopOfferPolling();
+        }
+        if (status === 'busy') {
+            startLocationTracking();
+        } else {
+            stopLocationTracking();
+        }
+    }
+
+    function startLocationTracking() {
+        if (locationWatchId !== null || !navigator.geolocation) return;
+        locationWatchId = navigator.geolocation.watchPosition(
+            position => {
+                const point = {
+                    latitude: position.coords.latitude,
+                    longitude: position.coords.longitude,
+                    recorded_at: new Date(position.timestamp).toISOString()
+                };
+                if (position.coords.accuracy != null) point.accuracy_m = position.coords.accuracy;
+                // The browser reports the speed in m/s
+                if (position.coords.speed != null) point.speed_kmh = position.coords.speed * 3.6;
+                locationBuffer.push(point);
+                // Keep the newest points if the server is unreachable for a while
+                if (locationBuffer.length > maxLocationBuffer) {
+                    locationBuffer = locationBuffer.slice(-maxLocationBuffer);
+                }
+            },
+            error => console.error('Error watching position:', error),
+            { enableHighAccuracy: true, maximumAge: 5000 }
+        );
+        locationFlushTimer = setInterval(flushLocations, locationFlushInterval);
+    }
+
+    function stopLocationTracking() {
+        if (locationWatchId === null) return;
+        navigator.geolocation.clearWatch(locationWatchId);
+        locationWatchId = null;
+        clearInterval(locationFlushTimer);
+        locationFlushTimer = null;
+        flushLocations();
+    }
+
+    async function flushLocations() {
+        if (locationBuffer.length === 0) return;
+        const points = locationBuffer;
+        locationBuffer = [];
+        try {
+            const response = await fetch('/api/agent/location', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json'
+                },
+                body: JSON.stringify({ points: points })
+            });
+            const data = await response.json();
+            if (data.error) {
+                console.error('Error sending location:', data.error);
+            }
+        } catch (error) {
+            // Send the points again with the next batch
+            locationBuffer = points.concat(locationBuffer).slice(-maxLocationBuffer);
+            console.error('Error sending location:', error);
+        }
+    }
+
+    function resetSearchForm() {
+        searchErrorAlert.style.display = 'none';
+        [searchRadius, searchUntil].forEach(input => input.classList.remove('is-invalid'));
+    }
+
+    function showSearchError(data) {
+        searchErrorAlert.textContent = data.error;
+        searchErrorAlert.style.display = 'block';
+        const fields = data.fields || {};
+        if (fields.radius_km) {
+            searchRadius.classList.add('is-invalid');
+            document.getElementById('searchRadiusError').textContent = fields.radius_km;
+        }
+        if (fields.available_until) {
+            searchUntil.classList.add('is-invalid');
+            document.getElementById('searchUntilError').textContent = fields.available_until;
+        }
+    }
 
     // Search for available orders
     searchOrdersBtn.addEventListener('click', async function() {
+        resetSearchForm();
         try {
+            // The position lets the dispatcher offer nearby orders first
+            const point = await currentPosition();
+            const body = {};
+            if (point) body.point = point;
+            if (searchRadius.value) body.radius_km = Number(searchRadius.value);
+            if (searchUntil.value) body.available_until = new Date(searchUntil.value).toISOString();
+
             const response = await fetch('/api/orders/start_search', {
                 method: 'POST',
                 headers: {
                     'Content-Type': 'application/json'
-                }
+                },
+                body: JSON.stringify(body)
             });
 
-            if (!response.ok) {
-                throw new Error('Failed to fetch available orders');
+            const data = await response.json();
+            if (data.error) {
+                showSearchError(data);
+                return;
             }
 
-            const data = await response.json();
             displayOrders(data.orders || []);
             availableOrdersList.style.display = 'block';
+            showSession(data.session);
         } catch (error) {
             console.error('Error:', error);
             alert('Failed to load available orders. Please try again.');
         }
     });
 
+    stopSearchBtn.addEventListener('click', async function() {
+        stopSearchBtn.disabled = true;
+        try {
+            const response = await fetch('/api/orders/stop_search', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json'
+                }
+            });
+            const data = await response.json();
+            if (data.error) {
+                showSearchError(data);
+                return;
+            }
+            showSession(data);
+        } catch (error) {
+            console.error('Error:', error);
+            showSearchError({ error: 'Failed to stop the search. Please try again.' });
+        } finally {
+            stopSearchBtn.disabled = false;
+        }
+    });
+
+    async function sendHeartbeat() {
+        try {
+            const point = await currentPosition();
+            const response = await fetch('/api/agent/heartbeat', {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json'
+                },
+                body: JSON.stringify(point ? { point: point } : {})
+            });
+            const data = await response.json();
+            if (data.error) {
+                console.error('Error sending heartbeat:', data.error);
+                return;
+            }
+            showSession(data);
+        } catch (error) {
+            console.error('Error sending heartbeat:', error);
+        }
+    }
+
+    sendHeartbeat();
+    setInterval(sendHeartbeat, heartbeatInterval);
+
     // Display orders in the list
     function displayOrders(orders) {
         ordersContainer.innerHTML = '';
@@ -98,6 +285,8 @@ document.addEventListener('DOMContentLoaded', function() {
                 return 'Cancelled';
             case 'finished':
                 return 'Completed';
+            case 'expired':
+                return 'Expired';
             default:
                 return status;
         }
@@ -118,6 +307,8 @@ document.addEventListener('DOMContentLoaded', function() {
                 return 'danger';
             case 'finished':
                 return 'success';
+            case 'expired':
+                return 'secondary';
             default:
                 return 'secondary';
         }
@@ -242,6 +433,7 @@ document.addEventListener('DOMContentLoaded', function() {
                 }
         document.getElementById('orderTimeGap').textContent = timeGap;
         document.getElementById('orderStatus').textContent = getStatusText(order.order_status);
+        resetQueueForm();
 
         availableOrdersList.style.display = 'none';
         orderDetailsSection.style.display = 'block';
@@ -266,8 +458,10 @@ document.addEventListener('DOMContentLoaded', function() {
                 }
             });
 
-            if (!response.ok) {
-                throw new Error('Failed to accept order');
+            const data = await response.json();
+            if (data.error) {
+                alert('Error: ' + data.error);
+                return;
             }
 
             alert('Order accepted successfully!');
@@ -280,4 +474,206 @@ document.addEventListener('DOMContentLoaded', function() {
             alert('Failed to accept order. Please try again.');
         }
     });
-}); 
\ No newline at end of file
+
+    function resetQueueForm() {
+        queueComment.value = '';
+        queueComment.classList.remove('is-invalid');
+        queueCommentError.style.display = 'none';
+        queueErrorAlert.style.display = 'none';
+        queueSuccessAlert.style.display = 'none';
+    }
+
+    function showQueueError(data) {
+        queueErrorAlert.textContent = data.error;
+        queueErrorAlert.style.display = 'block';
+        if (data.fields && data.fields.comment) {
+            queueComment.classList.add('is-invalid');
+            queueCommentError.textContent = data.fields.comment;
+            queueCommentError.style.display = 'block';
+        }
+    }
+
+    // Join the queue for the order: the client chooses one of the queued agents
+    joinQueueBtn.addEventListener('click', async function() {
+        if (!currentOrderId || joinQueueBtn.disabled) return;
+
+        const comment = queueComment.value;
+        resetQueueForm();
+        queueComment.value = comment;
+        joinQueueBtn.disabled = true;
+
+        try {
+            const response = await fetch(`/api/orders/${currentOrderId}/join`, {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json'
+                },
+                body: JSON.stringify({ comment: comment })
+            });
+            const data = await response.json();
+            if (data.error) {
+                showQueueError(data);
+                return;
+            }
+
+            const deadline = data.order && data.order.matching_deadline;
+            queueSuccessAlert.textContent = deadline
+                ? `You are in the queue. The client chooses an agent until ${formatDate(deadline)}.`
+                : 'You are in the queue.';
+            queueSuccessAlert.style.display = 'block';
+        } catch (error) {
+            console.error('Error:', error);
+            showQueueError({ error: 'Failed to join the queue. Please try again.' });
+        } finally {
+            joinQueueBtn.disabled = false;
+        }
+    });
+
+    leaveQueueBtn.addEventListener('click', async function() {
+        if (!currentOrderId || leaveQueueBtn.disabled) return;
+
+        resetQueueForm();
+        leaveQueueBtn.disabled = true;
+
+        try {
+            const response = await fetch(`/api/orders/${currentOrderId}/leave`, {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json'
+                }
+            });
+            const data = await response.json();
+            if (data.error) {
+                showQueueError(data);
+                return;
+            }
+
+            queueSuccessAlert.textContent = 'You left the queue.';
+            queueSuccessAlert.style.display = 'block';
+        } catch (error) {
+            console.error('Error:', error);
+            showQueueError({ error: 'Failed to leave the queue. Please try again.' });
+        } finally {
+            leaveQueueBtn.disabled = false;
+        }
+    });
+
+    // Current position of the agent, or null if the browser can't tell
+    function currentPosition() {
+        return new Promise(resolve => {
+            if (!navigator.geolocation) {
+                resolve(null);
+                return;
+            }
+            navigator.geolocation.getCurrentPosition(
+                position => resolve({
+                    latitude: position.coords.latitude,
+                    longitude: position.coords.longitude
+                }),
+                () => resolve(null),
+                { timeout: 5000, maximumAge: 60000 }
+            );
+        });
+    }
+
+    function startOfferPolling() {
+        if (offerPollTimer) return;
+        loadOffer();
+        offerPollTimer = setInterval(loadOffer, offerPollInterval);
+    }
+
+    function stopOfferPolling() {
+        clearInterval(offerPollTimer);
+        offerPollTimer = null;
+        showOffer(null);
+    }
+
+    async function loadOffer() {
+        try {
+            const response = await fetch('/api/agent/offer', {
+                method: 'GET',
+                headers: {
+                    'Content-Type': 'application/json'
+                }
+            });
+            if (response.status === 404) {
+                showOffer(null);
+                return;
+            }
+            const data = await response.json();
+            if (data.error) {
+                console.error('Error loading offer:', data.error);
+                return;
+            }
+            showOffer(data);
+        } catch (error) {
+            console.error('Error loading offer:', error);
+        }
+    }
+
+    function showOffer(offer) {
+        if (currentOffer && offer && currentOffer.order_id === offer.order_id) return;
+
+        currentOffer = offer;
+        clearInterval(offerCountdownTimer);
+        offerError.style.display = 'none';
+        if (!offer) {
+            offerPanel.style.display = 'none';
+            return;
+        }
+
+        const order = offer.order || {};
+        document.getElementById('offerLocation').textContent = order.order_location || 'No location';
+        document.getElementById('offerAddress').textContent = order.order_address || 'No address';
+        document.getElementById('offerDate').textContent = formatDate(order.order_date);
+
+        const expiresAt = parseInt(offer.expires_at.seconds) * 1000;
+        const tick = () => {
+            const left = Math.max(0, Math.round((expiresAt - Date.now()) / 1000));
+            offerCountdown.textContent = `${left} s`;
+            if (left === 0) {
+                showOffer(null);
+            }
+        };
+        tick();
+        offerCountdownTimer = setInterval(tick, 1000);
+        offerPanel.style.display = 'block';
+    }
+
+    async function respondToOffer(action) {
+        if (!currentOffer) return;
+
+        acceptOfferBtn.disabled = true;
+        declineOfferBtn.disabled = true;
+        try {
+            const response = await fetch(`/api/orders/${currentOffer.order_id}/${action}`, {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json'
+                }
+            });
+            const data = await response.json();
+            if (data.error) {
+                offerError.textContent = data.error;
+                offerError.style.display = 'block';
+                return;
+            }
+
+            showOffer(null);
+            if (action === 'accept') {
+                alert('Order accepted successfully!');
+            }
+            searchOrdersBtn.click();
+        } catch (error) {
+            console.error('Error:', error);
+            offerError.textContent = 'Failed to respond to the offer. Please try again.';
+            offerError.style.display = 'block';
+        } finally {
+            acceptOfferBtn.disabled = false;
+            declineOfferBtn.disabled = false;
+        }
+    }
+
+    acceptOfferBtn.addEventListener('click', () => respondToOffer('accept'));
+    declineOfferBtn.addEventListener('click', () => respondToOffer('decline'));
+}); 
@@ -0,0 +1,73 @@
+document.addEventListener('DOMContentLoaded', function() {
+    const statusFilter = document.getElementById('statusFilter');
+    const agentsTable = document.getElementById('agentsTable');
+    const errorAlert = document.getElementById('errorAlert');
+    const updatedAt = document.getElementById('updatedAt');
+    const refreshInterval = 10000;
+
+    const statusBadges = {
+        searching: 'success',
+        busy: 'primary',
+        online: 'info',
+        offline: 'secondary'
+    };
+
+    function formatTimestamp(ts) {
+        if (!ts || !ts.seconds) return '&mdash;';
+        return new Date(parseInt(ts.seconds) * 1000).toLocaleString();
+    }
+
+    function formatWindow(session) {
+        if (!session.available_from && !session.available_until) return 'Any time';
+        const from = session.available_from ? formatTimestamp(session.available_from) : 'now';
+        const until = session.available_until ? formatTimestamp(session.available_until) : 'any time';
+        return `${from} &ndash; ${until}`;
+    }
+
+    function renderSessions(sessions) {
+        agentsTable.innerHTML = '';
+        if (sessions.length === 0) {
+            agentsTable.innerHTML = '<tr><td colspan="7" class="text-center text-muted">No agents</td></tr>';
+            return;
+        }
+
+        sessions.forEach(session => {
+            const row = document.createElement('tr');
+            row.innerHTML = `
+                <td><code>${session.agent_id}</code></td>
+                <td><span class="badge bg-${statusBadges[session.status] || 'secondary'}">${session.status}</span></td>
+                <td>${session.current_order_id ? `<code>${session.current_order_id}</code>` : '&mdash;'}</td>
+                <td>${session.radius_km ? `${session.radius_km} km` : '&mdash;'}</td>
+                <td>${session.status === 'searching' ? formatWindow(session) : '&mdash;'}</td>
+                <td>${formatTimestamp(session.started_at)}</td>
+                <td>${formatTimestamp(session.last_seen_at)}</td>
+            `;
+            agentsTable.appendChild(row);
+        });
+    }
+
+    async function loadSessions() {
+        const status = statusFilter.value;
+        try {
+            const response = await fetch(`/api/agents${status ? `?status=${encodeURIComponent(status)}` : ''}`);
+            const data = await response.json();
+            if (data.error) {
+                errorAlert.textContent = data.error;
+                errorAlert.style.display = 'block';
+                return;
+            }
+
+            errorAlert.style.display = 'none';
+            renderSessions(data.sessions || []);
+            updatedAt.textContent = new Date().toLocaleTimeString();
+        } catch (error) {
+            console.error('Error:', error);
+            errorAlert.textContent = 'Failed to load agents. Please try again.';
+            errorAlert.style.display = 'block';
+        }
+    }
+
+    statusFilter.addEventListener('change', loadSessions);
+    loadSessions();
+    setInterval(loadSessions, refreshInterval);
+});
@@ -2,34 +2,128 @@ document.addEventListener('DOMContentLoaded', function() {
     // WebSocket connection
     const userId = document.getElementById('userId').value;
     if (userId) {
-        // Create WebSocket connection
-        const wsProtocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
-        const wsUrl = `${wsProtocol}//${window.location.host}/ws?user_id=${userId}`;
-        const ws = new WebSocket(wsUrl);
-
-        // Connection opened
-        ws.addEventListener('open', function (event) {
-            console.log('WebSocket connection established');
-        });
+        // The last delivered event id survives reconnects and page reloads so the
+        // notification service can replay whatever was published during the gap
+        const lastEventKey = `orderq:last_event_id:${userId}`;
+        let reconnectDelay = 1000;
+        let failedUpgrades = 0;
 
-        // Listen for messages
-        ws.addEventListener('message', function (event) {
-            console.log('Message from server:', event.data);
-        });
+        // The notification service only accepts a short-lived token from the
+        // gateway, so every connection attempt asks for a fresh one
+        async function notificationQuery() {
+            const response = await fetch('/api/notifications/token');
+            if (!response.ok) {
+                throw new Error(`stream token request failed: ${response.status}`);
+            }
+            const { token } = await response.json();
+            let query = `token=${encodeURIComponent(token)}`;
+            const lastEventId = sessionStorage.getItem(lastEventKey);
+            if (lastEventId) {
+                query += `&last_event_id=${lastEventId}`;
+            }
+            return query;
+        }
 
-        // // Connection closed
-        // ws.addEventListener('close', function (event) {
-        //     console.log('WebSocket connection closed');
-        //     // Attempt to reconnect after 5 seconds
-        //     setTimeout(() => {
-        //         window.location.reload();
-        //     }, 5000);
-        // });
-
-        // Connection error
-        ws.addEventListener('error', function (event) {
-            console.error('WebSocket error:', event);
-        });
+        function scheduleReconnect(connect) {
+            console.log('Notifications disconnected, reconnecting in', reconnectDelay, 'ms');
+            setTimeout(connect, reconnectDelay);
+            reconnectDelay = Math.min(reconnectDelay * 2, 30000);
+        }
+
+        async function connectNotifications() {
+            let query;
+            try {
+                query = await notificationQuery();
+            } catch (err) {
+                console.error('Notifications unavailable:', err);
+                scheduleReconnect(connectNotifications);
+                return;
+            }
+
+            const wsProtocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+            const wsUrl = `${wsProtocol}//${window.location.host}/ws?${query}`;
+            const ws = new WebSocket(wsUrl);
+            let opened = false;
+
+            // Connection opened
+            ws.addEventListener('open', function (event) {
+                console.log('WebSocket connection established');
+                opened = true;
+                failedUpgrades = 0;
+                reconnectDelay = 1000;
+            });
+
+            // Listen for messages
+            ws.addEventListener('message', function (event) {
+                console.log('Message from server:', event.data);
+                handleNotification(JSON.parse(event.data));
+            });
+
+            // Connection closed
+            ws.addEventListener('close', function (event) {
+                // A proxy that breaks the upgrade never lets the socket open; fall back to SSE
+                if (!opened && ++failedUpgrades >= 2 && window.EventSource) {
+                    console.log('WebSocket unavailable, falling back to Server-Sent Events');
+                    connectEventSource();
+                    return;
+                }
+                scheduleReconnect(connectNotifications);
+            });
+
+            // Connection error
+            ws.addEventListener('error', function (event) {
+                console.error('WebSocket error:', event);
+            });
+        }
+
+        async function connectEventSource() {
+            let query;
+            try {
+                query = await notificationQuery();
+            } catch (err) {
+                console.error('Notifications unavailable:', err);
+                scheduleReconnect(connectEventSource);
+                return;
+            }
+
+            // EventSource reconnects on its own and resends the Last-Event-ID header.
+            // Once its token has expired the server refuses it and the source
+            // closes; then it is reopened with a fresh token.
+            const source = new EventSource(`/events?${query}`);
+            source.addEventListener('open', function () {
+                reconnectDelay = 1000;
+            });
+            source.addEventListener('message', function (event) {
+                console.log('Event from server:', event.data);
+                handleNotification(JSON.parse(event.data));
+            });
+            source.addEventListener('error', function (event) {
+                console.error('EventSource error:', event);
+                if (source.readyState === EventSource.CLOSED) {
+                    scheduleReconnect(connectEventSource);
+                }
+            });
+        }
+
+        function handleNotification(notification) {
+            if (notification.id) {
+                sessionStorage.setItem(lastEventKey, notification.id);
+            }
+            // Agent positions only update the order on screen
+            if (notification.type === 'order.agent_location') {
+                const data = notification.data || {};
+                if (data.order && data.order.order_id === currentOrderId) {
+                    showAgentLocation(data);
+                }
+                return;
+            }
+            // Refresh the list on order events, or when the server could not replay the gap
+            if (ordersListContainer.style.display !== 'none') {
+                loadOrders();
+            }
+        }
+
+        connectNotifications();
     }
 
     // Get references to buttons and containers
@@ -50,11 +144,17 @@ document.addEventListener('DOMContentLoaded', function() {
     const timeGapHidden = document.getElementById('orderTimeGap');
     
     // Get order details section elements
+    const currentOrderBanner = document.getElementById('currentOrderBanner');
+    const currentOrderSummary = document.getElementById('currentOrderSummary');
+    const viewCurrentOrderBtn = document.getElementById('viewCurrentOrder');
     const orderDetailsSection = document.getElementById('orderDetailsSection');
     const backToListBtn = document.getElementById('backToList');
     const cancelOrderBtn = document.getElementById('cancelOrderBtn');
     const finishOrderBtn = document.getElementById('finishOrderBtn');
     let currentOrderId = null;
+    // ETag of the order shown in the details; writes are sent with If-Match so
+    // they fail instead of overwriting someone else's change
+    let currentOrderEtag = null;
     
     // Add event listeners to time inputs to update the hidden field
     [hoursInput, minutesInput, secondsInput].forEach(input => {
@@ -62,6 +162,39 @@ document.addEventListener('DOMContentLoaded', function() {
         input.addEventListener('input', updateTimeGapValue);
     });
     
+    // Coordinates of the order place, taken from the browser when the client is there
+    const orderLatitudeInput = document.getElementById('orderLatitude');
+    const orderLongitudeInput = document.getElementById('orderLongitude');
+    const usePositionBtn = document.getElementById('usePositionBtn');
+    const positionStatus = document.getElementById('positionStatus');
+
+    usePositionBtn.addEventListener('click', function() {
+        if (!navigator.geolocation) {
+            positionStatus.textContent = 'Your browser can\'t tell your position';
+            return;
+        }
+        positionStatus.textContent = 'Locating...';
+        navigator.geolocation.getCurrentPosition(
+            position => {
+                orderLatitudeInput.value = position.coords.latitude;
+                orderLongitudeInput.value = position.coords.longitude;
+                positionStatus.textContent = 'Position saved';
+            },
+            () => {
+                clearOrderPosition();
+                positionStatus.textContent = 'Could not get your position';
+            },
+            { timeout: 10000 }
+        );
+    });
+
+    // Hidden inputs keep their value on form reset
+    function clearOrderPosition() {
+        orderLatitudeInput.value = '';
+        orderLongitudeInput.value = '';
+        positionStatus.textContent = 'Helps us find an executor nearby (optional)';
+    }
+
     // Function to update the hidden time gap value
     function updateTimeGapValue() {
         // Ensure valid values
@@ -100,6 +233,13 @@ document.addEventListener('DOMContentLoaded', function() {
         loadOrders();
     });
 
+    // Escape user-supplied text before inserting it into HTML
+    function escapeHtml(text) {
+        const div = document.createElement('div');
+        div.textContent = text;
+        return div.innerHTML;
+    }
+
     // Format date safely
     function formatDate(dateString) {
         if (!dateString) return 'Not specified';
@@ -146,13 +286,143 @@ document.addEventListener('DOMContentLoaded', function() {
                 return 'Cancelled';
             case 'finished':
                 return 'Completed';
+            case 'expired':
+                return 'Expired';
             default:
                 return status;
         }
     }
 
+    // Build the card shown for an order in the list
+    function createOrderCard(order) {
+        const formattedDate = formatDate(order.order_date);
+        const statusText = getStatusText(order.order_status);
+        
+        // Convert time gap (duration) to hours, minutes and seconds
+        let timeGap = '';
+        if (order.order_time_gap) {
+            const seconds = parseInt(order.order_time_gap.seconds);
+            const hours = Math.floor(seconds / 3600);
+            const minutes = Math.floor((seconds % 3600) / 60);
+            const remainingSeconds = seconds % 60;
+            
+            if (hours > 0) {
+                timeGap += `${hours} hour${hours > 1 ? 's' : ''}`;
+            }
+            if (minutes > 0) {
+                timeGap += `${hours > 0 ? ' ' : ''}${minutes} minute${minutes > 1 ? 's' : ''}`;
+            }
+            if (remainingSeconds > 0) {
+                timeGap += `${(hours > 0 || minutes > 0) ? ' ' : ''}${remainingSeconds} second${remainingSeconds > 1 ? 's' : ''}`;
+            }
+            
+            if (timeGap === '') {
+                timeGap = '0 seconds';
+            }
+        } else {
+            timeGap = 'Not specified';
+        }
+
+        const orderCard = document.createElement('div');
+        orderCard.className = 'col-md-6 col-lg-4 mb-4';
+        orderCard.innerHTML = `
+            <div class="card h-100">
+                <div class="card-body">
+                    <h5 class="card-title">${order.order_location || 'No location'}</h5>
+                    <h6 class="card-subtitle mb-3 text-muted">${order.order_address || 'No address'}</h6>
+                    <div class="d-flex align-items-center mb-2">
+                        <i class="fas fa-calendar-alt text-success me-2"></i>
+                        <span>${formattedDate}</span>
+                    </div>
+                    <div class="d-flex align-items-center mb-2">
+                        <i class="fas fa-clock text-success me-2"></i>
+                        <span>${timeGap}</span>
+                    </div>
+                    <div class="d-flex align-items-center">
+                        <i class="fas fa-tag text-success me-2"></i>
+                        <span class="badge bg-${getStatusBadgeColor(order.order_status)}">${statusText}</span>
+                    </div>
+                </div>
+                <div class="card-footer bg-white">
+                    <div class="d-grid">
+                        <button class="btn btn-outline-success view-order-btn" data-order-id="${order.order_id}">
+                            <i class="fas fa-eye me-1"></i> View Details
+                        </button>
+                    </div>
+                </div>
+            </div>
+        `;
+        orderCard.querySelector('.view-order-btn').addEventListener('click', function() {
+            viewOrderDetails(order.order_id);
+        });
+        return orderCard;
+    }
+
+    // Add a newly created order to the top of the list without reloading it
+    function prependOrder(order) {
+        // Drop the "No Orders Found" placeholder, if any
+        if (!ordersContainer.querySelector('.view-order-btn')) {
+            ordersContainer.innerHTML = '';
+        }
+        ordersContainer.prepend(createOrderCard(order));
+    }
+
+    // A client may have only one active order, so creating another one is
+    // disabled while it exists
+    let activeOrderId = null;
+
+    function showCurrentOrder(order) {
+        if (order) {
+            activeOrderId = order.order_id;
+            currentOrderSummary.textContent = `${order.order_location || 'No location'} (${getStatusText(order.order_status)})`;
+            currentOrderBanner.style.setProperty('display', 'flex', 'important');
+            createFormBtn.disabled = true;
+        } else {
+            activeOrderId = null;
+            currentOrderBanner.style.setProperty('display', 'none', 'important');
+            createFormBtn.disabled = false;
+        }
+    }
+
+    function loadCurrentOrder() {
+        fetch('/api/orders/current', {
+            method: 'GET',
+            headers: {
+                'Content-Type': 'application/json'
+            }
+        })
+        .then(response => {
+            if (response.status === 404) {
+                return null;
+            }
+            if (!response.ok) {
+                throw new Error('Failed to load current order');
+            }
+            return response.json();
+        })
+        .then(data => {
+            if (data && data.error) {
+                throw new Error(data.error);
+            }
+            showCurrentOrder(data);
+        })
+        .catch(error => {
+            console.error('Error loading current order:', error);
+        });
+    }
+
+    viewCurrentOrderBtn.addEventListener('click', function() {
+        if (activeOrderId) {
+            createFormContainer.style.display = 'none';
+            ordersListContainer.style.display = 'none';
+            viewOrderDetails(activeOrderId);
+        }
+    });
+
     // Load orders function
     function loadOrders() {
+        loadCurrentOrder();
+
         // Show loading spinner
         loadingSpinner.style.display = 'flex';
         ordersContainer.innerHTML = '';
@@ -190,72 +460,7 @@ document.addEventListener('DOMContentLoaded', function() {
 
             // Render each order
             data.orders.forEach(order => {
-                const formattedDate = formatDate(order.order_date);
-                const statusText = getStatusText(order.order_status);
-                
-                // Convert time gap (duration) to hours, minutes and seconds
-                let timeGap = '';
-                if (order.order_time_gap) {
-                    const seconds = parseInt(order.order_time_gap.seconds);
-                    const hours = Math.floor(seconds / 3600);
-                    const minutes = Math.floor((seconds % 3600) / 60);
-                    const remainingSeconds = seconds % 60;
-                    
-                    if (hours > 0) {
-                        timeGap += `${hours} hour${hours > 1 ? 's' : ''}`;
-                    }
-                    if (minutes > 0) {
-                        timeGap += `${hours > 0 ? ' ' : ''}${minutes} minute${minutes > 1 ? 's' : ''}`;
-                    }
-                    if (remainingSeconds > 0) {
-                        timeGap += `${(hours > 0 || minutes > 0) ? ' ' : ''}${remainingSeconds} second${remainingSeconds > 1 ? 's' : ''}`;
-                    }
-                    
-                    if (timeGap === '') {
-                        timeGap = '0 seconds';
-                    }
-                } else {
-                    timeGap = 'Not specified';
-                }
-
-                const orderCard = document.createElement('div');
-                orderCard.className = 'col-md-6 col-lg-4 mb-4';
-                orderCard.innerHTML = `
-                    <div class="card h-100">
-                        <div class="card-body">
-                            <h5 class="card-title">${order.order_location || 'No location'}</h5>
-                            <h6 class="card-subtitle mb-3 text-muted">${order.order_address || 'No address'}</h6>
-                            <div class="d-flex align-items-center mb-2">
-                                <i class="fas fa-calendar-alt text-success me-2"></i>
-                                <span>${formattedDate}</span>
-                            </div>
-                            <div class="d-flex align-items-center mb-2">
-                                <i class="fas fa-clock text-success me-2"></i>
-                                <span>${timeGap}</span>
-                            </div>
-                            <div class="d-flex align-items-center">
-                                <i class="fas fa-tag text-success me-2"></i>
-                                <span class="badge bg-${getStatusBadgeColor(order.order_status)}">${statusText}</span>
-                            </div>
-                        </div>
-                        <div class="card-footer bg-white">
-                            <div class="d-grid">
-                                <button class="btn btn-outline-success view-order-btn" data-order-id="${order.order_id}">
-                                    <i class="fas fa-eye me-1"></i> View Details
-                                </button>
-                            </div>
-                        </div>
-                    </div>
-                `;
-                ordersContainer.appendChild(orderCard);
-            });
-
-            // Add event listeners to view buttons
-            document.querySelectorAll('.view-order-btn').forEach(btn => {
-                btn.addEventListener('click', function() {
-                    const orderId = this.getAttribute('data-order-id');
-                    viewOrderDetails(orderId);
-                });
+                ordersContainer.appendChild(createOrderCard(order));
             });
         })
         .catch(error => {
@@ -297,6 +502,7 @@ document.addEventListener('DOMContentLoaded', function() {
                 return;
             }
 
+            currentOrderEtag = order.etag || null;
             const formattedDate = formatDate(order.order_date);
             const statusText = getStatusText(order.order_status);
             
@@ -351,9 +557,43 @@ document.addEventListener('DOMContentLoaded', function() {
                                     <i class="fas fa-tag text-success me-2"></i>
                                     <span class="badge bg-${getStatusBadgeColor(order.order_status)}">${statusText}</span>
                                 </div>
+                                ${order.notes ? `
+                                <div class="d-flex align-items-start mt-2">
+                                    <i class="fas fa-sticky-note text-success me-2 mt-1"></i>
+                                    <span>${escapeHtml(order.notes)}</span>
+                                </div>
+                                ` : ''}
+                                ${order.cancellation ? `
+                                <div class="d-flex align-items-start mt-2">
+                                    <i class="fas fa-comment-slash text-danger me-2 mt-1"></i>
+                                    <span>
+                                        Cancelled by ${order.cancellation.cancelled_by}:
+                                        ${cancellationReasonLabels[order.cancellation.reason] || escapeHtml(order.cancellation.reason)}
+                                        ${order.cancellation.comment ? `<br><small class="text-muted">${escapeHtml(order.cancellation.comment)}</small>` : ''}
+                                    </span>
+                                </div>
+                                ` : ''}
+                                ${order.order_status === 'signed' ? `
+                                <div class="mt-3">
+                                    <h6 class="mb-1">Your agent</h6>
+                                    <div class="small text-muted" id="agentLocation">Waiting for the agent's position</div>
+                                </div>
+                                ` : ''}
+                                ${order.order_status === 'matching' ? `
+                                <div class="mt-3">
+                                    <h6 class="mb-1">Agents in the queue</h6>
+                                    <small class="text-muted">Choose until ${formatDate(order.matching_deadline)}</small>
+                                    <div class="list-group mt-2" id="candidatesList"></div>
+                                </div>
+                                ` : ''}
                             </div>
                             <div class="card-footer bg-white">
                                 <div class="d-grid gap-2">
+                                    ${editableStatuses.includes(order.order_status) ? `
+                                        <button type="button" class="btn btn-outline-success" id="editOrderBtn">
+                                            <i class="fas fa-edit me-2"></i>Edit Order
+                                        </button>
+                                    ` : ''}
                                     ${order.order_status !== 'cancelled' ? `
                                         <button type="button" class="btn btn-outline-danger" id="cancelOrderBtn">
                                             <i class="fas fa-times-circle me-2"></i>Cancel Order
@@ -380,12 +620,17 @@ document.addEventListener('DOMContentLoaded', function() {
                 ordersListContainer.style.display = 'block';
             });
 
+            const editOrderBtn = document.getElementById('editOrderBtn');
+            if (editOrderBtn) {
+                editOrderBtn.addEventListener('click', function() {
+                    openEditDialog(order);
+                });
+            }
+
             const cancelOrderBtn = document.getElementById('cancelOrderBtn');
             if (cancelOrderBtn) {
                 cancelOrderBtn.addEventListener('click', function() {
-                    if (confirm('Are you sure you want to cancel this order?')) {
-                        cancelOrder(currentOrderId);
-                    }
+                    openCancelDialog(currentOrderId);
                 });
             }
 
@@ -398,6 +643,13 @@ document.addEventListener('DOMContentLoaded', function() {
                 });
             }
 
+            if (order.order_status === 'matching') {
+                loadCandidates(currentOrderId);
+            }
+            if (order.order_status === 'signed') {
+                loadAgentLocation(currentOrderId);
+            }
+
             // Show order details section and hide orders list
             ordersListContainer.style.display = 'none';
             orderDetailsSection.style.display = 'block';
@@ -408,6 +660,116 @@ document.addEventListener('DOMContentLoaded', function() {
         });
     }
 
+    // Agents waiting in the queue for the order; the client picks one of them
+    function loadCandidates(orderId) {
+        const candidatesList = document.getElementById('candidatesList');
+        fetch(`/api/orders/${orderId}/candidates`, {
+            method: 'GET',
+            headers: {
+                'Content-Type': 'application/json'
+            }
+        })
+        .then(response => response.json())
+        .then(data => {
+            if (data.error) {
+                candidatesList.innerHTML = `<div class="text-danger">${escapeHtml(data.error)}</div>`;
+                return;
+            }
+            if (data.order && data.order.etag) {
+                currentOrderEtag = data.order.etag;
+            }
+
+            const candidates = (data.candidates || []).filter(c => c.status === 'waiting');
+            if (candidates.length === 0) {
+                candidatesList.innerHTML = '<div class="text-muted">No agents in the queue yet</div>';
+                return;
+            }
+
+            candidatesList.innerHTML = candidates.map(c => `
+                <div class="list-group-item d-flex justify-content-between align-items-start">
+                    <div>
+                        <div class="small text-muted">Agent ${escapeHtml(c.agent_id)}</div>
+                        ${c.comment ? `<div>${escapeHtml(c.comment)}</div>` : ''}
+                    </div>
+                    <button type="button" class="btn btn-sm btn-success" data-agent-id="${escapeHtml(c.agent_id)}">Choose</button>
+                </div>
+            `).join('');
+
+            candidatesList.querySelectorAll('button[data-agent-id]').forEach(button => {
+                button.addEventListener('click', function() {
+                    selectAgent(orderId, button.dataset.agentId);
+                });
+            });
+        })
+        .catch(error => {
+            console.error('Error loading candidates:', error);
+            candidatesList.innerHTML = '<div class="text-danger">Failed to load the queue</div>';
+        });
+    }
+
+    // Where the agent of a signed order is; later positions come as
+    // order.agent_location notifications
+    function loadAgentLocation(orderId) {
+        fetch(`/api/orders/${orderId}/agent_location`, {
+            method: 'GET',
+            headers: {
+                'Content-Type': 'application/json'
+            }
+        })
+        .then(response => response.json())
+        .then(data => {
+            // 404 means the agent has not shared a position yet
+            if (!data.error) {
+                showAgentLocation(data);
+            }
+        })
+        .catch(error => {
+            console.error('Error loading agent location:', error);
+        });
+    }
+
+    // location has the fields of the order.agent_location event; eta is a
+    // duration like "300s"
+    function showAgentLocation(location) {
+        const agentLocation = document.getElementById('agentLocation');
+        if (!agentLocation) return;
+
+        const parts = [];
+        if (location.eta) {
+            const minutes = Math.max(1, Math.round(parseFloat(location.eta) / 60));
+            parts.push(`Arrives in about ${minutes} min`);
+        }
+        if (location.distance_km != null) {
+            parts.push(`${Number(location.distance_km).toFixed(1)} km away`);
+        }
+        if (parts.length === 0) {
+            parts.push(`At ${Number(location.latitude).toFixed(5)}, ${Number(location.longitude).toFixed(5)}`);
+        }
+        const updated = location.recorded_at ? new Date(location.recorded_at).toLocaleTimeString() : '';
+        agentLocation.innerHTML = `
+            <i class="fas fa-location-arrow text-primary me-1"></i>${escapeHtml(parts.join(' · '))}
+            ${updated ? `<br><small>Updated at ${escapeHtml(updated)}</small>` : ''}
+        `;
+    }
+
+    function selectAgent(orderId, agentId) {
+        fetch(`/api/orders/${orderId}/select`, {
+            method: 'POST',
+            headers: conditionalHeaders(),
+            body: JSON.stringify({ agent_id: agentId })
+        })
+        .then(response => response.json())
+        .then(data => {
+            if (data.error) {
+                alert('Error: ' + data.error);
+            }
+            viewOrderDetails(orderId);
+        })
+        .catch(error => {
+            alert('Error: ' + error.message);
+        });
+    }
+
     // Function to get badge color based on order status
     function getStatusBadgeColor(status) {
         if (!status) return 'secondary';
@@ -424,6 +786,8 @@ document.addEventListener('DOMContentLoaded', function() {
                 return 'danger';
             case 'finished':
                 return 'info';
+            case 'expired':
+                return 'secondary';
             default:
                 return 'secondary';
         }
@@ -437,13 +801,50 @@ document.addEventListener('DOMContentLoaded', function() {
         return (hours * 3600) + (minutes * 60) + (seconds || 0);
     }
 
+    // Show per-field errors returned by the server next to the form inputs
+    function showFieldErrors(form, fields) {
+        Object.entries(fields).forEach(([field, message]) => {
+            form.querySelectorAll(`[data-field="${field}"]`).forEach(input => {
+                input.classList.add('is-invalid');
+            });
+            const feedback = form.querySelector(`[data-error-for="${field}"]`);
+            if (feedback) {
+                feedback.textContent = message;
+                feedback.style.display = 'block';
+            }
+        });
+    }
+
+    function clearFieldErrors(form) {
+        form.querySelectorAll('.is-invalid').forEach(input => {
+            input.classList.remove('is-invalid');
+        });
+        form.querySelectorAll('[data-error-for]').forEach(feedback => {
+            feedback.textContent = '';
+            feedback.style.display = 'none';
+        });
+    }
+
+    // Idempotency key of the order being submitted. It is kept when a request
+    // fails so that a retry can't create the same order twice, and dropped once
+    // the order is created or the form is edited.
+    let idempotencyKey = null;
+
+    createOrderForm.addEventListener('input', function() {
+        idempotencyKey = null;
+    });
+
     // Handle form submission
     createOrderForm.addEventListener('submit', function(e) {
         e.preventDefault();
+
+        const submitBtn = createOrderForm.querySelector('button[type="submit"]');
+        if (submitBtn.disabled) return;
         
         // Hide previous alerts
         errorAlert.style.display = 'none';
         successAlert.style.display = 'none';
+        clearFieldErrors(createOrderForm);
         
         // Update the time gap value one last time before submission
         updateTimeGapValue();
@@ -473,14 +874,27 @@ document.addEventListener('DOMContentLoaded', function() {
             order_location: formData.get('orderLocation'),
             order_address: formData.get('orderAddress'),
             order_date: dateObj.toISOString(),
-            order_time_gap: `${totalSeconds}s`
+            order_time_gap: `${totalSeconds}s`,
+            notes: formData.get('orderNotes') || ''
         };
+        if (orderLatitudeInput.value && orderLongitudeInput.value) {
+            orderData.point = {
+                latitude: Number(orderLatitudeInput.value),
+                longitude: Number(orderLongitudeInput.value)
+            };
+        }
         
+        if (!idempotencyKey) {
+            idempotencyKey = crypto.randomUUID();
+        }
+        submitBtn.disabled = true;
+
         // Send request to create order
         fetch('/api/orders/create', {
             method: 'POST',
             headers: {
-                'Content-Type': 'application/json'
+                'Content-Type': 'application/json',
+                'Idempotency-Key': idempotencyKey
             },
             body: JSON.stringify(orderData)
         })
@@ -489,10 +903,15 @@ document.addEventListener('DOMContentLoaded', function() {
             if (data.error) {
                 errorAlert.textContent = data.error;
                 errorAlert.style.display = 'block';
+                if (data.fields) {
+                    showFieldErrors(createOrderForm, data.fields);
+                }
             } else {
+                idempotencyKey = null;
                 successAlert.textContent = 'Order created successfully!';
                 successAlert.style.display = 'block';
                 createOrderForm.reset();
+                clearOrderPosition();
                 
                 // Reset the time inputs to default values
                 hoursInput.value = 0;
@@ -500,39 +919,274 @@ document.addEventListener('DOMContentLoaded', function() {
                 secondsInput.value = 0;
                 updateTimeGapValue();
                 
+                prependOrder(data);
+                showCurrentOrder(data);
+
                 // After a delay, hide the form and show the orders
                 setTimeout(() => {
                     createFormContainer.style.display = 'none';
                     ordersListContainer.style.display = 'block';
-                    loadOrders();
                 }, 2000);
             }
         })
         .catch(error => {
             errorAlert.textContent = error.message;
             errorAlert.style.display = 'block';
+        })
+        .finally(() => {
+            submitBtn.disabled = false;
         });
     });
 
-    // Function to cancel an order
-    function cancelOrder(orderId) {
+    // Edit dialog: only the fields the user changed are sent, together with
+    // the version of the order they saw
+    const editableStatuses = ['pending', 'matching', 'signed'];
+    const editOrderModal = new bootstrap.Modal(document.getElementById('editOrderModal'));
+    const editOrderForm = document.getElementById('editOrderForm');
+    const editErrorAlert = document.getElementById('editErrorAlert');
+    const editLocationInput = document.getElementById('editOrderLocation');
+    const editAddressInput = document.getElementById('editOrderAddress');
+    const editDateInput = document.getElementById('editOrderDate');
+    const editHoursInput = document.getElementById('editHours');
+    const editMinutesInput = document.getElementById('editMinutes');
+    const editSecondsInput = document.getElementById('editSeconds');
+    const editNotesInput = document.getElementById('editOrderNotes');
+    const saveOrderBtn = document.getElementById('saveOrderBtn');
+    let editingOrder = null;
+    let editingValues = null;
+
+    // Value for a datetime-local input in the user's time zone
+    function toLocalDateTimeValue(date) {
+        const pad = n => n.toString().padStart(2, '0');
+        return `${date.getFullYear()}-${pad(date.getMonth() + 1)}-${pad(date.getDate())}` +
+            `T${pad(date.getHours())}:${pad(date.getMinutes())}`;
+    }
+
+    function readEditForm() {
+        const hours = parseInt(editHoursInput.value) || 0;
+        const minutes = parseInt(editMinutesInput.value) || 0;
+        const seconds = parseInt(editSecondsInput.value) || 0;
+        return {
+            order_location: editLocationInput.value,
+            order_address: editAddressInput.value,
+            order_date: editDateInput.value,
+            order_time_gap: `${hours * 3600 + minutes * 60 + seconds}s`,
+            notes: editNotesInput.value
+        };
+    }
+
+    function openEditDialog(order) {
+        editingOrder = order;
+
+        const date = order.order_date && order.order_date.seconds
+            ? new Date(parseInt(order.order_date.seconds) * 1000)
+            : null;
+        const gap = order.order_time_gap ? parseInt(order.order_time_gap.seconds) || 0 : 0;
+
+        editLocationInput.value = order.order_location || '';
+        editAddressInput.value = order.order_address || '';
+        editDateInput.value = date ? toLocalDateTimeValue(date) : '';
+        editHoursInput.value = Math.floor(gap / 3600);
+        editMinutesInput.value = Math.floor((gap % 3600) / 60);
+        editSecondsInput.value = gap % 60;
+        editNotesInput.value = order.notes || '';
+
+        // Once an executor has signed the order, only the notes can change
+        const notesOnly = order.order_status === 'signed';
+        [editLocationInput, editAddressInput, editDateInput, editHoursInput, editMinutesInput, editSecondsInput]
+            .forEach(input => { input.disabled = notesOnly; });
+
+        editingValues = readEditForm();
+        editErrorAlert.style.display = 'none';
+        clearFieldErrors(editOrderForm);
+
+        editOrderModal.show();
+    }
+
+    editOrderForm.addEventListener('submit', function(e) {
+        e.preventDefault();
+        if (saveOrderBtn.disabled) return;
+
+        editErrorAlert.style.display = 'none';
+        clearFieldErrors(editOrderForm);
+
+        const values = readEditForm();
+        const changes = {};
+        Object.keys(values).forEach(field => {
+            if (values[field] !== editingValues[field]) {
+                changes[field] = values[field];
+            }
+        });
+        if (Object.keys(changes).length === 0) {
+            editOrderModal.hide();
+            return;
+        }
+        if (changes.order_date !== undefined) {
+            const dateObj = new Date(changes.order_date);
+            if (isNaN(dateObj.getTime())) {
+                showFieldErrors(editOrderForm, { order_date: 'Please enter a valid date and time' });
+                return;
+            }
+            changes.order_date = dateObj.toISOString();
+        }
+        saveOrderBtn.disabled = true;
+        const orderId = editingOrder.order_id;
+
+        fetch(`/api/orders/${orderId}`, {
+            method: 'PATCH',
+            headers: {
+                'Content-Type': 'application/json',
+                'If-Match': editingOrder.etag
+            },
+            body: JSON.stringify(changes)
+        })
+        .then(response => response.json())
+        .then(data => {
+            if (data.error) {
+                editErrorAlert.textContent = data.error;
+                editErrorAlert.style.display = 'block';
+                if (data.fields) {
+                    showFieldErrors(editOrderForm, data.fields);
+                }
+                return;
+            }
+            editOrderModal.hide();
+            viewOrderDetails(orderId);
+            loadOrders();
+        })
+        .catch(error => {
+            editErrorAlert.textContent = error.message;
+            editErrorAlert.style.display = 'block';
+        })
+        .finally(() => {
+            saveOrderBtn.disabled = false;
+        });
+    });
+
+    // Cancellation dialog: the user sees what cancelling will cost them and
+    // picks a reason before the order is cancelled
+    const cancelOrderModal = new bootstrap.Modal(document.getElementById('cancelOrderModal'));
+    const cancelOrderForm = document.getElementById('cancelOrderForm');
+    const cancellationTerms = document.getElementById('cancellationTerms');
+    const cancelErrorAlert = document.getElementById('cancelErrorAlert');
+    const cancelReasonSelect = document.getElementById('cancelReason');
+    const cancelCommentInput = document.getElementById('cancelComment');
+    const confirmCancelBtn = document.getElementById('confirmCancelBtn');
+    let cancellingOrderId = null;
+    let acceptedPenalty = null;
+
+    const cancellationReasonLabels = {
+        changed_plans: 'My plans changed',
+        found_alternative: 'I found another way',
+        agent_late: 'The executor is late',
+        agent_unresponsive: 'The executor does not respond',
+        client_unresponsive: 'The client does not respond',
+        wrong_details: 'The order details are wrong',
+        expired: 'The order is no longer relevant',
+        other: 'Other'
+    };
+
+    function describeCancellationTerms(terms) {
+        switch (terms.penalty) {
+            case 'fee': {
+                const fee = new Intl.NumberFormat(undefined, {
+                    style: 'currency',
+                    currency: terms.fee_currency
+                }).format((terms.fee_amount || 0) / 100);
+                return `An executor is already working on this order. Cancelling now incurs a cancellation fee of ${fee}.`;
+            }
+            case 'strike':
+                return 'Cancelling now will add a strike to your account.';
+            default:
+                return 'You can cancel this order free of charge.';
+        }
+    }
+
+    function openCancelDialog(orderId) {
         fetch(`/api/orders/${orderId}/cancel`, {
-            method: 'POST',
+            method: 'GET',
             headers: {
                 'Content-Type': 'application/json'
             }
         })
-        .then(response => {
-            if (!response.ok) {
-                throw new Error('Failed to cancel order');
+        .then(response => response.json())
+        .then(data => {
+            if (data.error) {
+                throw new Error(data.error);
             }
-            return response.json();
+
+            const terms = data.terms || {};
+            cancellingOrderId = orderId;
+            acceptedPenalty = terms.penalty || 'none';
+
+            cancellationTerms.textContent = describeCancellationTerms(terms);
+            cancellationTerms.className = `alert alert-${acceptedPenalty === 'none' ? 'success' : 'warning'}`;
+
+            cancelReasonSelect.innerHTML = '<option value="" selected disabled>Choose a reason</option>';
+            (data.reasons || []).forEach(reason => {
+                const option = document.createElement('option');
+                option.value = reason;
+                option.textContent = cancellationReasonLabels[reason] || reason;
+                cancelReasonSelect.appendChild(option);
+            });
+            cancelCommentInput.value = '';
+            cancelErrorAlert.style.display = 'none';
+            clearFieldErrors(cancelOrderForm);
+
+            cancelOrderModal.show();
         })
+        .catch(error => {
+            alert('Error: ' + error.message);
+        });
+    }
+
+    cancelOrderForm.addEventListener('submit', function(e) {
+        e.preventDefault();
+        if (confirmCancelBtn.disabled) return;
+
+        cancelErrorAlert.style.display = 'none';
+        clearFieldErrors(cancelOrderForm);
+        confirmCancelBtn.disabled = true;
+
+        cancelOrder(cancellingOrderId, {
+            reason: cancelReasonSelect.value,
+            comment: cancelCommentInput.value,
+            accepted_penalty: acceptedPenalty
+        })
+        .finally(() => {
+            confirmCancelBtn.disabled = false;
+        });
+    });
+
+    // Headers for a write to the order shown in the details
+    function conditionalHeaders() {
+        const headers = {
+            'Content-Type': 'application/json'
+        };
+        if (currentOrderEtag) {
+            headers['If-Match'] = currentOrderEtag;
+        }
+        return headers;
+    }
+
+    // Function to cancel an order
+    function cancelOrder(orderId, cancellation) {
+        return fetch(`/api/orders/${orderId}/cancel`, {
+            method: 'POST',
+            headers: conditionalHeaders(),
+            body: JSON.stringify(cancellation)
+        })
+        .then(response => response.json())
         .then(data => {
             if (data.error) {
-                alert('Error: ' + data.error);
+                cancelErrorAlert.textContent = data.error;
+                cancelErrorAlert.style.display = 'block';
+                if (data.fields) {
+                    showFieldErrors(cancelOrderForm, data.fields);
+                }
                 return;
             }
+            cancelOrderModal.hide();
             alert('Order cancelled successfully');
             // Go back to orders list and refresh
             orderDetailsSection.style.display = 'none';
@@ -540,7 +1194,8 @@ document.addEventListener('DOMContentLoaded', function() {
             loadOrders();
         })
         .catch(error => {
-            alert('Error: ' + error.message);
+            cancelErrorAlert.textContent = error.message;
+            cancelErrorAlert.style.display = 'block';
         });
     }
 
@@ -548,16 +1203,9 @@ document.addEventListener('DOMContentLoaded', function() {
     function finishOrder(orderId) {
         fetch(`/api/orders/${orderId}/complete`, {
             method: 'POST',
-            headers: {
-                'Content-Type': 'application/json'
-            }
-        })
-        .then(response => {
-            if (!response.ok) {
-                throw new Error('Failed to finish order');
-            }
-            return response.json();
+            headers: conditionalHeaders()
         })
+        .then(response => response.json())
         .then(data => {
             if (data.error) {
                 alert('Error: ' + data.error);
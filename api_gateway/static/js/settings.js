@@ -0,0 +1,136 @@
+document.addEventListener('DOMContentLoaded', function() {
+    const EVENTS = [
+        { type: 'order.created', label: 'Order created' },
+        { type: 'order.cancelled', label: 'Order cancelled' },
+        { type: 'order.completed', label: 'Order completed' }
+    ];
+    const CHANNELS = ['email', 'sms', 'webhook'];
+
+    const settingsForm = document.getElementById('settingsForm');
+    const channelsBody = document.querySelector('#channelsTable tbody');
+    const errorAlert = document.getElementById('errorAlert');
+    const successAlert = document.getElementById('successAlert');
+
+    const emailInput = document.getElementById('email');
+    const phoneInput = document.getElementById('phone');
+    const webhookInput = document.getElementById('webhookUrl');
+    const quietEnabledInput = document.getElementById('quietHoursEnabled');
+    const quietStartInput = document.getElementById('quietHoursStart');
+    const quietEndInput = document.getElementById('quietHoursEnd');
+    const timezoneInput = document.getElementById('timezone');
+    const digestModeSelect = document.getElementById('digestMode');
+
+    // Render the event x channel matrix
+    EVENTS.forEach(event => {
+        const row = document.createElement('tr');
+        row.innerHTML = `<td>${event.label}</td>` + CHANNELS.map(channel => `
+            <td class="text-center">
+                <input class="form-check-input" type="checkbox" data-event="${event.type}" data-channel="${channel}">
+            </td>`).join('');
+        channelsBody.appendChild(row);
+    });
+
+    function showError(message) {
+        successAlert.style.display = 'none';
+        errorAlert.textContent = message;
+        errorAlert.style.display = 'block';
+    }
+
+    function showSuccess(message) {
+        errorAlert.style.display = 'none';
+        successAlert.textContent = message;
+        successAlert.style.display = 'block';
+    }
+
+    function fillForm(prefs) {
+        emailInput.value = prefs.email || '';
+        phoneInput.value = prefs.phone || '';
+        webhookInput.value = prefs.webhook_url || '';
+
+        const quietHours = prefs.quiet_hours || {};
+        quietEnabledInput.checked = !!quietHours.enabled;
+        quietStartInput.value = quietHours.start || '22:00';
+        quietEndInput.value = quietHours.end || '07:00';
+        timezoneInput.value = quietHours.timezone && quietHours.timezone !== 'UTC'
+            ? quietHours.timezone
+            : Intl.DateTimeFormat().resolvedOptions().timeZone;
+        digestModeSelect.value = prefs.digest_mode || 'off';
+
+        (prefs.channels || []).forEach(cp => {
+            const checkbox = channelsBody.querySelector(`input[data-event="${cp.event_type}"][data-channel="${cp.channel}"]`);
+            if (checkbox) {
+                checkbox.checked = !!cp.enabled;
+            }
+        });
+    }
+
+    function loadPreferences() {
+        fetch('/api/settings/notifications', {
+            method: 'GET',
+            headers: {
+                'Content-Type': 'application/json'
+            }
+        })
+        .then(response => {
+            if (!response.ok) {
+                throw new Error('Failed to load settings');
+            }
+            return response.json();
+        })
+        .then(data => {
+            if (data.error) {
+                showError(data.error);
+                return;
+            }
+            fillForm(data.preferences || {});
+        })
+        .catch(error => showError(error.message));
+    }
+
+    settingsForm.addEventListener('submit', function(e) {
+        e.preventDefault();
+
+        const channels = [];
+        channelsBody.querySelectorAll('input[type="checkbox"]').forEach(checkbox => {
+            channels.push({
+                event_type: checkbox.dataset.event,
+                channel: checkbox.dataset.channel,
+                enabled: checkbox.checked
+            });
+        });
+
+        const prefs = {
+            email: emailInput.value.trim(),
+            phone: phoneInput.value.trim(),
+            webhook_url: webhookInput.value.trim(),
+            channels: channels,
+            quiet_hours: {
+                enabled: quietEnabledInput.checked,
+                start: quietStartInput.value,
+                end: quietEndInput.value,
+                timezone: timezoneInput.value.trim() || 'UTC'
+            },
+            digest_mode: digestModeSelect.value
+        };
+
+        fetch('/api/settings/notifications', {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json'
+            },
+            body: JSON.stringify(prefs)
+        })
+        .then(response => response.json())
+        .then(data => {
+            if (data.error) {
+                showError(data.error);
+                return;
+            }
+            fillForm(data.preferences || {});
+            showSuccess('Settings saved');
+        })
+        .catch(error => showError(error.message));
+    });
+
+    loadPreferences();
+});
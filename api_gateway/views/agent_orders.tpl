@@ -18,9 +18,40 @@
 
     <div class="container mb-5">
         <div class="action-buttons text-center">
+            <p class="mb-3">Status: <span class="badge bg-secondary" id="agentStatus">offline</span></p>
+            <!-- Preferences for the search session; orders outside them are not offered -->
+            <div class="row justify-content-center g-2 mb-3">
+                <div class="col-sm-3">
+                    <input type="number" class="form-control" id="searchRadius" min="1" max="200" step="1"
+                           placeholder="Radius, km (optional)">
+                    <div class="invalid-feedback" id="searchRadiusError"></div>
+                </div>
+                <div class="col-sm-4">
+                    <input type="datetime-local" class="form-control" id="searchUntil" title="Take orders until">
+                    <div class="invalid-feedback" id="searchUntilError"></div>
+                </div>
+            </div>
+            <div class="alert alert-danger" role="alert" id="searchErrorAlert" style="display: none;"></div>
             <button class="btn btn-primary btn-lg" id="searchOrders">
                 <i class="fas fa-search me-2"></i>Search Available Orders
             </button>
+            <button class="btn btn-outline-secondary btn-lg" id="stopSearch" style="display: none;">
+                <i class="fas fa-stop me-2"></i>Stop Search
+            </button>
+        </div>
+
+        <!-- Order offered to the agent by automatic dispatch -->
+        <div class="alert alert-success mt-4" role="alert" id="offerPanel" style="display: none;">
+            <h5 class="alert-heading"><i class="fas fa-bell me-2"></i>New order for you</h5>
+            <p class="mb-1"><strong id="offerLocation"></strong> &mdash; <span id="offerAddress"></span></p>
+            <p class="mb-2"><span id="offerDate"></span> &middot; <small>respond within <span id="offerCountdown"></span></small></p>
+            <div class="text-danger mb-2" id="offerError" style="display: none;"></div>
+            <button type="button" class="btn btn-success me-2" id="acceptOfferBtn">
+                <i class="fas fa-check me-1"></i>Accept
+            </button>
+            <button type="button" class="btn btn-outline-secondary" id="declineOfferBtn">
+                <i class="fas fa-times me-1"></i>Decline
+            </button>
         </div>
 
         <!-- Available Orders List -->
@@ -59,10 +90,22 @@
                             </div>
                         </div>
                         <div class="card-footer bg-white">
+                            <div class="alert alert-danger" role="alert" id="queueErrorAlert" style="display: none;"></div>
+                            <div class="alert alert-success" role="alert" id="queueSuccessAlert" style="display: none;"></div>
                             <div class="d-grid gap-2">
                                 <button type="button" class="btn btn-success" id="acceptOrderBtn">
                                     <i class="fas fa-check-circle me-2"></i>Accept Order
                                 </button>
+                                <!-- Several agents can queue for an order; the client picks one of them -->
+                                <textarea class="form-control" id="queueComment" rows="2" maxlength="500"
+                                          placeholder="Message to the client (optional)"></textarea>
+                                <div class="invalid-feedback" id="queueCommentError"></div>
+                                <button type="button" class="btn btn-outline-success" id="joinQueueBtn">
+                                    <i class="fas fa-user-plus me-2"></i>Join Queue
+                                </button>
+                                <button type="button" class="btn btn-outline-secondary" id="leaveQueueBtn">
+                                    <i class="fas fa-user-minus me-2"></i>Leave Queue
+                                </button>
                             </div>
                         </div>
                     </div>
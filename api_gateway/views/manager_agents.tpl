@@ -0,0 +1,62 @@
+<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Agents - OrderQ</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.1.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <link href="https://cdnjs.cloudflare.com/ajax/libs/font-awesome/6.0.0/css/all.min.css" rel="stylesheet">
+    <link href="/static/css/orders.css" rel="stylesheet">
+</head>
+<body>
+    <div class="hero-section text-center">
+        <div class="container">
+            <h1 class="display-4 mb-4">Agents</h1>
+            <p class="lead mb-4">Who is online, searching for orders or busy right now</p>
+        </div>
+    </div>
+
+    <div class="container mb-5">
+        <div class="d-flex justify-content-between align-items-center mb-3">
+            <select class="form-select w-auto" id="statusFilter">
+                <option value="">All agents</option>
+                <option value="searching">Searching</option>
+                <option value="busy">Busy</option>
+                <option value="online">Online</option>
+                <option value="offline">Offline</option>
+            </select>
+            <small class="text-muted">Updated <span id="updatedAt">never</span></small>
+        </div>
+
+        <div class="alert alert-danger" role="alert" id="errorAlert" style="display: none;"></div>
+
+        <div class="table-responsive">
+            <table class="table table-hover align-middle">
+                <thead>
+                    <tr>
+                        <th>Agent</th>
+                        <th>Status</th>
+                        <th>Order</th>
+                        <th>Radius</th>
+                        <th>Available</th>
+                        <th>Searching since</th>
+                        <th>Last seen</th>
+                    </tr>
+                </thead>
+                <tbody id="agentsTable">
+                    <!-- Agents will be dynamically loaded here -->
+                </tbody>
+            </table>
+        </div>
+    </div>
+
+    <footer class="bg-light py-4 mt-auto">
+        <div class="container text-center">
+            <p class="mb-0">© 2024 OrderQ. All rights reserved.</p>
+        </div>
+    </footer>
+
+    <script src="https://cdn.jsdelivr.net/npm/bootstrap@5.1.3/dist/js/bootstrap.bundle.min.js"></script>
+    <script src="/static/js/manager_agents.js"></script>
+</body>
+</html>
@@ -8,9 +8,10 @@
             <label for="orderLocation" class="form-label">Location Name</label>
             <div class="input-group">
                 <span class="input-group-text"><i class="fas fa-building"></i></span>
-                <input type="text" class="form-control" id="orderLocation" name="orderLocation" 
+                <input type="text" class="form-control" id="orderLocation" name="orderLocation" data-field="order_location" 
                        placeholder="Enter the location name (e.g., Restaurant, Bank, Government Office)" required>
             </div>
+            <div class="invalid-feedback" data-error-for="order_location"></div>
             <div class="form-text">Specify the name of the place where you need someone to stand in line</div>
         </div>
         
@@ -18,9 +19,10 @@
             <label for="orderAddress" class="form-label">Address</label>
             <div class="input-group">
                 <span class="input-group-text"><i class="fas fa-map-marker-alt"></i></span>
-                <input type="text" class="form-control" id="orderAddress" name="orderAddress" 
+                <input type="text" class="form-control" id="orderAddress" name="orderAddress" data-field="order_address" 
                        placeholder="Enter the full address" required>
             </div>
+            <div class="invalid-feedback" data-error-for="order_address"></div>
             <div class="form-text">Provide the complete address of the location</div>
         </div>
         
@@ -29,8 +31,9 @@
                 <label for="orderDate" class="form-label">Date and Time</label>
                 <div class="input-group">
                     <span class="input-group-text"><i class="fas fa-calendar-alt"></i></span>
-                    <input type="datetime-local" class="form-control" id="orderDate" name="orderDate" required>
+                    <input type="datetime-local" class="form-control" id="orderDate" name="orderDate" data-field="order_date" required>
                 </div>
+                <div class="invalid-feedback" data-error-for="order_date"></div>
                 <div class="form-text">When do you need someone to be in line</div>
             </div>
             
@@ -39,28 +42,47 @@
                 <div class="time-inputs row">
                     <div class="col-4">
                         <div class="input-group">
-                            <input type="number" class="form-control" id="hours" min="0" max="24" value="0" placeholder="Hours">
+                            <input type="number" class="form-control" id="hours" data-field="order_time_gap" min="0" max="24" value="0" placeholder="Hours">
                             <span class="input-group-text">hrs</span>
                         </div>
                     </div>
                     <div class="col-4">
                         <div class="input-group">
-                            <input type="number" class="form-control" id="minutes" min="0" max="59" value="30" placeholder="Minutes">
+                            <input type="number" class="form-control" id="minutes" data-field="order_time_gap" min="0" max="59" value="30" placeholder="Minutes">
                             <span class="input-group-text">min</span>
                         </div>
                     </div>
                     <div class="col-4">
                         <div class="input-group">
-                            <input type="number" class="form-control" id="seconds" min="0" max="59" value="0" placeholder="Seconds">
+                            <input type="number" class="form-control" id="seconds" data-field="order_time_gap" min="0" max="59" value="0" placeholder="Seconds">
                             <span class="input-group-text">sec</span>
                         </div>
                     </div>
                     <input type="hidden" id="orderTimeGap" name="orderTimeGap" value="00:30:00">
                 </div>
+                <div class="invalid-feedback" data-error-for="order_time_gap"></div>
                 <div class="form-text mt-2">Set how much time you need to be notified before the order is due</div>
             </div>
         </div>
         
+        <div class="mb-4">
+            <!-- Optional coordinates: the order is offered to the nearest agents first -->
+            <input type="hidden" id="orderLatitude" name="orderLatitude">
+            <input type="hidden" id="orderLongitude" name="orderLongitude">
+            <button type="button" class="btn btn-outline-secondary btn-sm" id="usePositionBtn" data-field="point">
+                <i class="fas fa-location-arrow me-1"></i>I am at this place now
+            </button>
+            <span class="form-text ms-2" id="positionStatus">Helps us find an executor nearby (optional)</span>
+            <div class="invalid-feedback" data-error-for="point"></div>
+        </div>
+
+        <div class="mb-4">
+            <label for="orderNotes" class="form-label">Notes</label>
+            <textarea class="form-control" id="orderNotes" name="orderNotes" data-field="notes" rows="3" maxlength="1000"
+                      placeholder="Anything the executor should know (optional)"></textarea>
+            <div class="invalid-feedback" data-error-for="notes"></div>
+        </div>
+
         <div class="d-grid gap-2">
             <button type="submit" class="btn btn-success btn-lg">
                 <i class="fas fa-plus-circle me-2"></i>Create Order
@@ -20,13 +20,22 @@
     </div>
 
     <div class="container mb-5">
+        <!-- Shown while the user has an active order: only one is allowed at a time -->
+        <div class="alert alert-info d-flex justify-content-between align-items-center" role="alert" id="currentOrderBanner" style="display: none !important;">
+            <span><i class="fas fa-hourglass-half me-2"></i>You have an active order: <strong id="currentOrderSummary"></strong></span>
+            <button class="btn btn-sm btn-outline-primary" id="viewCurrentOrder">View</button>
+        </div>
+
         <div class="action-buttons text-center">
             <button class="btn btn-success btn-lg me-2" id="showCreateForm">
                 <i class="fas fa-plus-circle me-2"></i>Create New Order
             </button>
-            <button class="btn btn-outline-primary btn-lg" id="showOrdersList">
+            <button class="btn btn-outline-primary btn-lg me-2" id="showOrdersList">
                 <i class="fas fa-list me-2"></i>View My Orders
             </button>
+            <a href="/settings" class="btn btn-outline-secondary btn-lg">
+                <i class="fas fa-bell me-2"></i>Notification Settings
+            </a>
         </div>
 
         <!-- Include form template -->
@@ -78,6 +87,109 @@
         </div>
     </div>
 
+    <!-- Edit Order Modal: place and time can be changed until an executor starts, notes at any time -->
+    <div class="modal fade" id="editOrderModal" tabindex="-1" aria-labelledby="editOrderModalLabel" aria-hidden="true">
+        <div class="modal-dialog">
+            <div class="modal-content">
+                <form id="editOrderForm">
+                    <div class="modal-header">
+                        <h5 class="modal-title" id="editOrderModalLabel">Edit Order</h5>
+                        <button type="button" class="btn-close" data-bs-dismiss="modal" aria-label="Close"></button>
+                    </div>
+                    <div class="modal-body">
+                        <div class="alert alert-danger" role="alert" id="editErrorAlert" style="display: none;"></div>
+                        <div class="mb-3">
+                            <label for="editOrderLocation" class="form-label">Location Name</label>
+                            <input type="text" class="form-control" id="editOrderLocation" data-field="order_location">
+                            <div class="invalid-feedback" data-error-for="order_location"></div>
+                        </div>
+                        <div class="mb-3">
+                            <label for="editOrderAddress" class="form-label">Address</label>
+                            <input type="text" class="form-control" id="editOrderAddress" data-field="order_address">
+                            <div class="invalid-feedback" data-error-for="order_address"></div>
+                        </div>
+                        <div class="mb-3">
+                            <label for="editOrderDate" class="form-label">Date and Time</label>
+                            <input type="datetime-local" class="form-control" id="editOrderDate" data-field="order_date">
+                            <div class="invalid-feedback" data-error-for="order_date"></div>
+                        </div>
+                        <div class="mb-3">
+                            <label class="form-label">Estimated Time Needed</label>
+                            <div class="row">
+                                <div class="col-4">
+                                    <div class="input-group">
+                                        <input type="number" class="form-control" id="editHours" data-field="order_time_gap" min="0" max="24">
+                                        <span class="input-group-text">hrs</span>
+                                    </div>
+                                </div>
+                                <div class="col-4">
+                                    <div class="input-group">
+                                        <input type="number" class="form-control" id="editMinutes" data-field="order_time_gap" min="0" max="59">
+                                        <span class="input-group-text">min</span>
+                                    </div>
+                                </div>
+                                <div class="col-4">
+                                    <div class="input-group">
+                                        <input type="number" class="form-control" id="editSeconds" data-field="order_time_gap" min="0" max="59">
+                                        <span class="input-group-text">sec</span>
+                                    </div>
+                                </div>
+                            </div>
+                            <div class="invalid-feedback" data-error-for="order_time_gap"></div>
+                        </div>
+                        <div class="mb-3">
+                            <label for="editOrderNotes" class="form-label">Notes</label>
+                            <textarea class="form-control" id="editOrderNotes" data-field="notes" rows="3" maxlength="1000"></textarea>
+                            <div class="invalid-feedback" data-error-for="notes"></div>
+                        </div>
+                    </div>
+                    <div class="modal-footer">
+                        <button type="button" class="btn btn-outline-secondary" data-bs-dismiss="modal">Close</button>
+                        <button type="submit" class="btn btn-success" id="saveOrderBtn">
+                            <i class="fas fa-save me-2"></i>Save Changes
+                        </button>
+                    </div>
+                </form>
+            </div>
+        </div>
+    </div>
+
+    <!-- Cancel Order Modal: shows the cancellation policy before the user confirms -->
+    <div class="modal fade" id="cancelOrderModal" tabindex="-1" aria-labelledby="cancelOrderModalLabel" aria-hidden="true">
+        <div class="modal-dialog">
+            <div class="modal-content">
+                <form id="cancelOrderForm">
+                    <div class="modal-header">
+                        <h5 class="modal-title" id="cancelOrderModalLabel">Cancel Order</h5>
+                        <button type="button" class="btn-close" data-bs-dismiss="modal" aria-label="Close"></button>
+                    </div>
+                    <div class="modal-body">
+                        <div class="alert" role="alert" id="cancellationTerms"></div>
+                        <div class="alert alert-danger" role="alert" id="cancelErrorAlert" style="display: none;"></div>
+                        <div class="mb-3">
+                            <label for="cancelReason" class="form-label">Reason</label>
+                            <select class="form-select" id="cancelReason" data-field="reason" required></select>
+                            <div class="invalid-feedback" data-error-for="reason"></div>
+                        </div>
+                        <div class="mb-3">
+                            <label for="cancelComment" class="form-label">Comment</label>
+                            <textarea class="form-control" id="cancelComment" data-field="comment" rows="3" maxlength="500"
+                                      placeholder="Tell us what happened"></textarea>
+                            <div class="invalid-feedback" data-error-for="comment"></div>
+                            <div class="form-text">Required if the reason is "Other"</div>
+                        </div>
+                    </div>
+                    <div class="modal-footer">
+                        <button type="button" class="btn btn-outline-secondary" data-bs-dismiss="modal">Keep Order</button>
+                        <button type="submit" class="btn btn-danger" id="confirmCancelBtn">
+                            <i class="fas fa-times-circle me-2"></i>Cancel Order
+                        </button>
+                    </div>
+                </form>
+            </div>
+        </div>
+    </div>
+
     <footer class="bg-light py-4 mt-auto">
         <div class="container text-center">
             <p class="mb-0">© 2024 OrderQ. All rights reserved.</p>
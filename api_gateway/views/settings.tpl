@@ -0,0 +1,115 @@
+<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Notification Settings - OrderQ</title>
+    <link href="https://cdn.jsdelivr.net/npm/bootstrap@5.1.3/dist/css/bootstrap.min.css" rel="stylesheet">
+    <link href="https://cdnjs.cloudflare.com/ajax/libs/font-awesome/6.0.0/css/all.min.css" rel="stylesheet">
+    <link href="/static/css/orders.css" rel="stylesheet">
+</head>
+<body>
+    <input type="hidden" id="userId" value="{{.user_id}}">
+
+    <div class="hero-section text-center">
+        <div class="container">
+            <h1 class="display-4 mb-4">Notification Settings</h1>
+            <p class="lead mb-4">Choose how and when we contact you about your orders</p>
+        </div>
+    </div>
+
+    <div class="container mb-5">
+        <div class="action-buttons text-center">
+            <a href="/orders" class="btn btn-outline-primary btn-lg">
+                <i class="fas fa-arrow-left me-2"></i>Back to Orders
+            </a>
+        </div>
+
+        <div class="form-container">
+            <div class="alert alert-danger" role="alert" id="errorAlert" style="display: none;"></div>
+            <div class="alert alert-success" role="alert" id="successAlert" style="display: none;"></div>
+
+            <form id="settingsForm">
+                <h4 class="mb-3">Contacts</h4>
+                <div class="row mb-4">
+                    <div class="col-md-4">
+                        <label for="email" class="form-label">Email</label>
+                        <input type="email" class="form-control" id="email" name="email" placeholder="you@example.com">
+                    </div>
+                    <div class="col-md-4">
+                        <label for="phone" class="form-label">Phone</label>
+                        <input type="tel" class="form-control" id="phone" name="phone" placeholder="+7 900 000 00 00">
+                    </div>
+                    <div class="col-md-4">
+                        <label for="webhookUrl" class="form-label">Webhook URL</label>
+                        <input type="url" class="form-control" id="webhookUrl" name="webhookUrl" placeholder="https://example.com/hook">
+                        <div class="form-text">A public https address</div>
+                    </div>
+                </div>
+
+                <h4 class="mb-3">Channels</h4>
+                <div class="table-responsive mb-4">
+                    <table class="table align-middle" id="channelsTable">
+                        <thead>
+                            <tr>
+                                <th>Event</th>
+                                <th class="text-center">Email</th>
+                                <th class="text-center">SMS</th>
+                                <th class="text-center">Webhook</th>
+                            </tr>
+                        </thead>
+                        <tbody>
+                            <!-- Rows are rendered by settings.js -->
+                        </tbody>
+                    </table>
+                </div>
+
+                <h4 class="mb-3">Quiet Hours</h4>
+                <div class="form-check form-switch mb-3">
+                    <input class="form-check-input" type="checkbox" id="quietHoursEnabled">
+                    <label class="form-check-label" for="quietHoursEnabled">Hold non-urgent notifications during quiet hours</label>
+                </div>
+                <div class="row mb-4">
+                    <div class="col-md-4">
+                        <label for="quietHoursStart" class="form-label">From</label>
+                        <input type="time" class="form-control" id="quietHoursStart" value="22:00">
+                    </div>
+                    <div class="col-md-4">
+                        <label for="quietHoursEnd" class="form-label">To</label>
+                        <input type="time" class="form-control" id="quietHoursEnd" value="07:00">
+                    </div>
+                    <div class="col-md-4">
+                        <label for="timezone" class="form-label">Timezone</label>
+                        <input type="text" class="form-control" id="timezone" placeholder="Europe/Moscow">
+                    </div>
+                </div>
+
+                <h4 class="mb-3">Digest</h4>
+                <div class="mb-4">
+                    <select class="form-select" id="digestMode">
+                        <option value="off">Send every notification right away</option>
+                        <option value="hourly">Hourly digest</option>
+                        <option value="daily">Daily digest (9:00)</option>
+                    </select>
+                    <div class="form-text">Cancellations are always delivered immediately</div>
+                </div>
+
+                <div class="d-grid gap-2">
+                    <button type="submit" class="btn btn-success btn-lg">
+                        <i class="fas fa-save me-2"></i>Save Settings
+                    </button>
+                </div>
+            </form>
+        </div>
+    </div>
+
+    <footer class="bg-light py-4 mt-auto">
+        <div class="container text-center">
+            <p class="mb-0">© 2024 OrderQ. All rights reserved.</p>
+        </div>
+    </footer>
+
+    <script src="https://cdn.jsdelivr.net/npm/bootstrap@5.1.3/dist/js/bootstrap.bundle.min.js"></script>
+    <script src="/static/js/settings.js"></script>
+</body>
+</html>
@@ -0,0 +1,47 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ChannelEmail   = "email"
+	ChannelSMS     = "sms"
+	ChannelWebhook = "webhook"
+	ChannelFile    = "file"
+)
+
+// Recipient — контакты пользователя, по которым внешние каналы доставляют уведомления.
+type Recipient struct {
+	UserID     uuid.UUID
+	Email      string
+	Phone      string
+	WebhookURL string
+}
+
+// Message — отрендеренное уведомление, готовое к отправке в канал.
+type Message struct {
+	Recipient Recipient
+	EventType string
+	Subject   string
+	Body      string
+	Data      json.RawMessage
+}
+
+// Channel — внешний канал доставки уведомлений (email, SMS, webhook и т.д.).
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Known сообщает, существует ли канал с таким именем.
+func Known(name string) bool {
+	switch name {
+	case ChannelEmail, ChannelSMS, ChannelWebhook, ChannelFile:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"notification_service/internal/config"
+)
+
+type emailChannel struct {
+	cfg *config.Email
+}
+
+func NewEmail(cfg *config.Email) Channel {
+	return &emailChannel{cfg: cfg}
+}
+
+func (c *emailChannel) Name() string {
+	return ChannelEmail
+}
+
+func (c *emailChannel) Send(ctx context.Context, msg *Message) error {
+	if msg.Recipient.Email == "" {
+		return errors.New("recipient has no email")
+	}
+
+	var auth smtp.Auth
+	if c.cfg.Username != "" {
+		auth = smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, c.cfg.Host)
+	}
+
+	body := strings.Join([]string{
+		"From: " + c.cfg.From,
+		"To: " + msg.Recipient.Email,
+		"Subject: " + msg.Subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		msg.Body,
+	}, "\r\n")
+
+	addr := net.JoinHostPort(c.cfg.Host, c.cfg.Port)
+	if err := smtp.SendMail(addr, auth, c.cfg.From, []string{msg.Recipient.Email}, []byte(body)); err != nil {
+		return fmt.Errorf("smtp.SendMail: %w", err)
+	}
+	return nil
+}
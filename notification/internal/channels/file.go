@@ -0,0 +1,66 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"notification_service/internal/config"
+)
+
+type fileRecord struct {
+	Time      time.Time       `json:"time"`
+	Channel   string          `json:"channel"`
+	UserID    string          `json:"user_id"`
+	EventType string          `json:"event_type"`
+	Subject   string          `json:"subject"`
+	Body      string          `json:"body"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// fileChannel дописывает уведомления в файл построчно в JSON. Нужен для
+// разработки: весь конвейер доставки можно проверить без внешних сервисов.
+type fileChannel struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFile(cfg *config.File) Channel {
+	return &fileChannel{path: cfg.Path}
+}
+
+func (c *fileChannel) Name() string {
+	return ChannelFile
+}
+
+func (c *fileChannel) Send(ctx context.Context, msg *Message) error {
+	line, err := json.Marshal(fileRecord{
+		Time:      time.Now().UTC(),
+		Channel:   ChannelFile,
+		UserID:    msg.Recipient.UserID.String(),
+		EventType: msg.EventType,
+		Subject:   msg.Subject,
+		Body:      msg.Body,
+		Data:      msg.Data,
+	})
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f, err := os.OpenFile(c.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("os.OpenFile: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("f.Write: %w", err)
+	}
+	return nil
+}
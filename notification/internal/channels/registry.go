@@ -0,0 +1,27 @@
+package channels
+
+import (
+	"fmt"
+
+	"notification_service/internal/config"
+)
+
+// FromConfig создаёт каналы, перечисленные в cfg.Enabled.
+func FromConfig(cfg *config.Channels, secret string) ([]Channel, error) {
+	chs := make([]Channel, 0, len(cfg.Enabled))
+	for _, name := range cfg.Enabled {
+		switch name {
+		case ChannelEmail:
+			chs = append(chs, NewEmail(&cfg.Email))
+		case ChannelSMS:
+			chs = append(chs, NewSMS(&cfg.SMS))
+		case ChannelWebhook:
+			chs = append(chs, NewWebhook(&cfg.Webhook, secret))
+		case ChannelFile:
+			chs = append(chs, NewFile(&cfg.File))
+		default:
+			return nil, fmt.Errorf("unknown channel %q", name)
+		}
+	}
+	return chs, nil
+}
@@ -0,0 +1,86 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"notification_service/internal/config"
+
+	"go.uber.org/zap"
+)
+
+type smsRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Text string `json:"text"`
+}
+
+type smsChannel struct {
+	cfg    *config.SMS
+	client *http.Client
+}
+
+// NewSMS создаёт канал, отправляющий SMS через HTTP API провайдера.
+func NewSMS(cfg *config.SMS) Channel {
+	return &smsChannel{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (c *smsChannel) Name() string {
+	return ChannelSMS
+}
+
+func (c *smsChannel) Send(ctx context.Context, msg *Message) error {
+	if msg.Recipient.Phone == "" {
+		return errors.New("recipient has no phone")
+	}
+
+	body, err := json.Marshal(smsRequest{From: c.cfg.Sender, To: msg.Recipient.Phone, Text: msg.Body})
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("http.NewRequest: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.APIKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sms provider responded %s", resp.Status)
+	}
+	return nil
+}
+
+// FakeSMSProvider — заглушка HTTP API SMS-провайдера для локальной разработки:
+// принимает запросы в том же формате и просто пишет их в лог.
+func FakeSMSProvider(logger *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req smsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON request", http.StatusBadRequest)
+			return
+		}
+
+		logger.Info("fake sms sent", zap.String("from", req.From), zap.String("to", req.To), zap.String("text", req.Text))
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
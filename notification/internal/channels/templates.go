@@ -0,0 +1,64 @@
+package channels
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// defaultTemplate используется для событий, у которых нет собственного шаблона.
+const defaultTemplate = "default"
+
+// Templates рендерит тему и текст уведомления по типу события. Шаблон события
+// лежит в templates/<тип события>.tmpl и определяет блоки "subject" и "body".
+type Templates struct {
+	byEvent map[string]*template.Template
+}
+
+func LoadTemplates() (*Templates, error) {
+	files, err := templateFS.ReadDir("templates")
+	if err != nil {
+		return nil, fmt.Errorf("templateFS.ReadDir: %w", err)
+	}
+
+	t := &Templates{byEvent: make(map[string]*template.Template)}
+	for _, f := range files {
+		name := strings.TrimSuffix(f.Name(), ".tmpl")
+		tmpl, err := template.ParseFS(templateFS, "templates/"+f.Name())
+		if err != nil {
+			return nil, fmt.Errorf("template.ParseFS %s: %w", f.Name(), err)
+		}
+		t.byEvent[name] = tmpl
+	}
+
+	if _, ok := t.byEvent[defaultTemplate]; !ok {
+		return nil, fmt.Errorf("template %q is missing", defaultTemplate)
+	}
+	return t, nil
+}
+
+func (t *Templates) Render(eventType string, data any) (subject string, body string, err error) {
+	tmpl, ok := t.byEvent[eventType]
+	if !ok {
+		tmpl = t.byEvent[defaultTemplate]
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", fmt.Errorf("render subject: %w", err)
+	}
+	subject = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	if err := tmpl.ExecuteTemplate(&buf, "body", data); err != nil {
+		return "", "", fmt.Errorf("render body: %w", err)
+	}
+	body = strings.TrimSpace(buf.String())
+
+	return subject, body, nil
+}
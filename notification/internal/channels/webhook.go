@@ -0,0 +1,158 @@
+package channels
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+
+	"notification_service/internal/config"
+)
+
+// SignatureHeader содержит HMAC-SHA256 тела запроса, подписанного секретом сервиса,
+// чтобы получатель webhook мог проверить его подлинность.
+const SignatureHeader = "X-OrderQ-Signature"
+
+type webhookPayload struct {
+	EventType string          `json:"event_type"`
+	UserID    string          `json:"user_id"`
+	Subject   string          `json:"subject"`
+	Body      string          `json:"body"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+type webhookChannel struct {
+	secret []byte
+	client *http.Client
+}
+
+// ErrForbiddenWebhook — адрес webhook не https или ведёт во внутреннюю сеть.
+var ErrForbiddenWebhook = errors.New("forbidden webhook url")
+
+// cgnat — разделяемые адреса провайдеров (RFC 6598), net.IP.IsPrivate их не включает.
+var cgnat = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func NewWebhook(cfg *config.Webhook, secret string) Channel {
+	// Адрес задаёт пользователь, поэтому куда подключаться, проверяется уже после
+	// разрешения имени: DNS может вернуть внутренний адрес и после проверки URL
+	dialer := &net.Dialer{Timeout: cfg.Timeout, Control: checkDialAddress}
+	transport := &http.Transport{
+		Proxy:               nil,
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: cfg.Timeout,
+	}
+	client := &http.Client{
+		Timeout:   cfg.Timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 3 {
+				return errors.New("too many redirects")
+			}
+			return ValidateWebhookURL(req.URL.String())
+		},
+	}
+	return &webhookChannel{secret: []byte(secret), client: client}
+}
+
+// ValidateWebhookURL проверяет адрес webhook из настроек пользователя: только
+// https и не адрес из внутренних сетей. Имена хостов проверяются при подключении.
+func ValidateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrForbiddenWebhook, err)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme must be https", ErrForbiddenWebhook)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("%w: no host", ErrForbiddenWebhook)
+	}
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return fmt.Errorf("%w: host %q", ErrForbiddenWebhook, host)
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+		return fmt.Errorf("%w: address %s", ErrForbiddenWebhook, ip)
+	}
+	return nil
+}
+
+// checkDialAddress не даёт подключиться к внутреннему адресу, в какой бы
+// адрес ни разрешилось имя хоста.
+func checkDialAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !publicIP(ip) {
+		return fmt.Errorf("%w: address %s", ErrForbiddenWebhook, host)
+	}
+	return nil
+}
+
+func publicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		cgnat.Contains(ip))
+}
+
+func (c *webhookChannel) Name() string {
+	return ChannelWebhook
+}
+
+func (c *webhookChannel) Send(ctx context.Context, msg *Message) error {
+	if msg.Recipient.WebhookURL == "" {
+		return errors.New("recipient has no webhook url")
+	}
+	// Адрес мог быть сохранён до появления проверки
+	if err := ValidateWebhookURL(msg.Recipient.WebhookURL); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(webhookPayload{
+		EventType: msg.EventType,
+		UserID:    msg.Recipient.UserID.String(),
+		Subject:   msg.Subject,
+		Body:      msg.Body,
+		Data:      msg.Data,
+	})
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.Recipient.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("http.NewRequest: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package channels
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url string
+		ok  bool
+	}{
+		{"https://hooks.example.com/orderq", true},
+		{"https://203.0.113.10/hook", true},
+		{"http://hooks.example.com/orderq", false},
+		{"ftp://hooks.example.com", false},
+		{"https://", false},
+		{"https://localhost/hook", false},
+		{"https://api.localhost/hook", false},
+		{"https://127.0.0.1/hook", false},
+		{"https://[::1]/hook", false},
+		{"https://10.0.0.5/hook", false},
+		{"https://172.16.1.1/hook", false},
+		{"https://192.168.1.1/hook", false},
+		{"https://169.254.169.254/latest/meta-data", false},
+		{"https://[fe80::1]/hook", false},
+		{"https://[fd00::1]/hook", false},
+		{"https://100.64.0.1/hook", false},
+		{"https://0.0.0.0/hook", false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateWebhookURL(tt.url)
+		if tt.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.url, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrForbiddenWebhook) {
+			t.Errorf("%s: expected ErrForbiddenWebhook, got %v", tt.url, err)
+		}
+	}
+}
+
+func TestCheckDialAddress(t *testing.T) {
+	if err := checkDialAddress("tcp", "203.0.113.10:443", nil); err != nil {
+		t.Errorf("public address rejected: %v", err)
+	}
+	for _, address := range []string{"127.0.0.1:443", "10.1.2.3:443", "169.254.169.254:80", "[::1]:443"} {
+		if err := checkDialAddress("tcp", address, nil); !errors.Is(err, ErrForbiddenWebhook) {
+			t.Errorf("%s: expected ErrForbiddenWebhook, got %v", address, err)
+		}
+	}
+}
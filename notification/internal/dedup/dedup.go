@@ -0,0 +1,111 @@
+package dedup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"notification_service/internal/infra/broker"
+	"notification_service/internal/metrics"
+	"orderq/pkg/events"
+
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+)
+
+// claimLease — через столько занятое, но не обработанное событие может занять
+// другой обработчик: реплика, занявшая его, скорее всего упала.
+const claimLease = 5 * time.Minute
+
+// Store помнит, какие события уже обработал каждый потребитель. ClaimEvent
+// должен занимать событие атомарно: из двух одновременных вызовов true
+// получает только один.
+type Store interface {
+	ClaimEvent(ctx context.Context, consumer, eventID string, lease time.Duration) (bool, error)
+	CompleteEvent(ctx context.Context, consumer, eventID string) error
+	ReleaseEvent(ctx context.Context, consumer, eventID string) error
+	DeleteProcessedEventsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Deduplicator делает обработчики очередей идемпотентными: событие, которое
+// потребитель уже обработал или обрабатывает прямо сейчас, подтверждается без
+// повторной обработки. Событие занимается до вызова обработчика; если тот
+// вернул ошибку, занятость снимается, и повтор обработается заново.
+type Deduplicator struct {
+	logger *zap.Logger
+	store  Store
+	ttl    time.Duration
+}
+
+func New(logger *zap.Logger, store Store, ttl time.Duration) *Deduplicator {
+	return &Deduplicator{logger: logger, store: store, ttl: ttl}
+}
+
+// Wrap оборачивает обработчик очереди queue.
+func (d *Deduplicator) Wrap(queue string, next broker.Handler) broker.Handler {
+	return func(ctx context.Context, msg amqp.Delivery) error {
+		eventID := EventID(msg)
+
+		claimed, err := d.store.ClaimEvent(ctx, queue, eventID, claimLease)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			metrics.DuplicateEvents.WithLabelValues(queue).Inc()
+			d.logger.Info("duplicate event dropped", zap.String("queue", queue), zap.String("eventID", eventID))
+			return nil
+		}
+
+		if err := next(ctx, msg); err != nil {
+			if err := d.store.ReleaseEvent(ctx, queue, eventID); err != nil {
+				// Повтор будет принят за дубль, пока не истечёт claimLease
+				d.logger.Error("failed to release event", zap.String("eventID", eventID), zap.Error(err))
+			}
+			return err
+		}
+		metrics.ProcessedEvents.WithLabelValues(queue).Inc()
+
+		if err := d.store.CompleteEvent(ctx, queue, eventID); err != nil {
+			d.logger.Error("failed to mark event processed", zap.String("eventID", eventID), zap.Error(err))
+		}
+		return nil
+	}
+}
+
+// Run периодически удаляет записи старше ttl, пока не отменён ctx.
+func (d *Deduplicator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := d.store.DeleteProcessedEventsBefore(ctx, time.Now().Add(-d.ttl))
+			if err != nil {
+				d.logger.Error("failed to clean up processed events", zap.Error(err))
+				continue
+			}
+			d.logger.Debug("processed events cleaned up", zap.Int64("deleted", deleted))
+		}
+	}
+}
+
+// EventID возвращает уникальный ID события: ce-id, затем message_id.
+// У событий старого формата ID нет — для них берётся хеш routing key и тела.
+func EventID(msg amqp.Delivery) string {
+	if ce, ok, err := events.ParseCloudEvent(msg.Headers); ok && err == nil {
+		return ce.ID
+	}
+	if msg.MessageId != "" {
+		return msg.MessageId
+	}
+
+	h := sha256.New()
+	h.Write([]byte(msg.RoutingKey))
+	h.Write([]byte{0})
+	h.Write(msg.Body)
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
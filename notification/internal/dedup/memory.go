@@ -0,0 +1,98 @@
+package dedup
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory — LRU в памяти реплики на случай, когда Postgres для дедупликации
+// не используется. Дубли, пришедшие на другую реплику или после рестарта,
+// он не заметит.
+type Memory struct {
+	mu      sync.Mutex
+	size    int
+	entries *list.List
+	index   map[string]*list.Element
+}
+
+type memoryEntry struct {
+	key         string
+	processedAt time.Time // когда событие занято или обработано
+	done        bool
+}
+
+func NewMemory(size int) *Memory {
+	return &Memory{
+		size:    size,
+		entries: list.New(),
+		index:   make(map[string]*list.Element),
+	}
+}
+
+func (m *Memory) ClaimEvent(ctx context.Context, consumer, eventID string, lease time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	key := consumer + "/" + eventID
+	if el, ok := m.index[key]; ok {
+		entry := el.Value.(*memoryEntry)
+		m.entries.MoveToFront(el)
+		if entry.done || now.Sub(entry.processedAt) < lease {
+			return false, nil
+		}
+		entry.processedAt = now
+		return true, nil
+	}
+
+	m.index[key] = m.entries.PushFront(&memoryEntry{key: key, processedAt: now})
+	for m.size > 0 && m.entries.Len() > m.size {
+		m.remove(m.entries.Back())
+	}
+	return true, nil
+}
+
+func (m *Memory) CompleteEvent(ctx context.Context, consumer, eventID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.index[consumer+"/"+eventID]; ok {
+		entry := el.Value.(*memoryEntry)
+		entry.done = true
+		entry.processedAt = time.Now()
+	}
+	return nil
+}
+
+func (m *Memory) ReleaseEvent(ctx context.Context, consumer, eventID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.index[consumer+"/"+eventID]; ok && !el.Value.(*memoryEntry).done {
+		m.remove(el)
+	}
+	return nil
+}
+
+func (m *Memory) DeleteProcessedEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var deleted int64
+	for el := m.entries.Back(); el != nil; {
+		prev := el.Prev()
+		if el.Value.(*memoryEntry).processedAt.Before(before) {
+			m.remove(el)
+			deleted++
+		}
+		el = prev
+	}
+	return deleted, nil
+}
+
+func (m *Memory) remove(el *list.Element) {
+	m.entries.Remove(el)
+	delete(m.index, el.Value.(*memoryEntry).key)
+}
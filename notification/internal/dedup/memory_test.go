@@ -0,0 +1,151 @@
+package dedup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+)
+
+func TestMemoryClaim(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(10)
+
+	if ok, _ := m.ClaimEvent(ctx, "q", "e1", time.Minute); !ok {
+		t.Fatal("expected first claim to succeed")
+	}
+	if ok, _ := m.ClaimEvent(ctx, "q", "e1", time.Minute); ok {
+		t.Fatal("expected claim of an event in progress to fail")
+	}
+	if ok, _ := m.ClaimEvent(ctx, "other", "e1", time.Minute); !ok {
+		t.Fatal("expected another consumer to claim the same event")
+	}
+
+	// Снятая занятость позволяет обработать повтор
+	m.ReleaseEvent(ctx, "q", "e1")
+	if ok, _ := m.ClaimEvent(ctx, "q", "e1", time.Minute); !ok {
+		t.Fatal("expected claim after release to succeed")
+	}
+
+	// Обработанное событие не занимается даже после lease и не снимается Release
+	m.CompleteEvent(ctx, "q", "e1")
+	m.ReleaseEvent(ctx, "q", "e1")
+	if ok, _ := m.ClaimEvent(ctx, "q", "e1", 0); ok {
+		t.Fatal("expected claim of a processed event to fail")
+	}
+
+	// Занятое и брошенное событие забирается после lease
+	m.ClaimEvent(ctx, "q", "e2", time.Minute)
+	if ok, _ := m.ClaimEvent(ctx, "q", "e2", 0); !ok {
+		t.Fatal("expected claim after lease to succeed")
+	}
+}
+
+func TestMemoryEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(2)
+
+	for _, id := range []string{"e1", "e2"} {
+		m.ClaimEvent(ctx, "q", id, time.Minute)
+		m.CompleteEvent(ctx, "q", id)
+	}
+	// e1 использовано недавно, поэтому вытесняется e2
+	m.ClaimEvent(ctx, "q", "e1", time.Minute)
+	m.ClaimEvent(ctx, "q", "e3", time.Minute)
+
+	if ok, _ := m.ClaimEvent(ctx, "q", "e1", time.Minute); ok {
+		t.Fatal("expected e1 to stay in the cache")
+	}
+	if ok, _ := m.ClaimEvent(ctx, "q", "e2", time.Minute); !ok {
+		t.Fatal("expected e2 to be evicted")
+	}
+}
+
+func TestMemoryDeleteProcessedEventsBefore(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory(10)
+
+	m.ClaimEvent(ctx, "q", "old", time.Minute)
+	m.CompleteEvent(ctx, "q", "old")
+	cutoff := time.Now()
+	time.Sleep(time.Millisecond)
+	m.ClaimEvent(ctx, "q", "new", time.Minute)
+	m.CompleteEvent(ctx, "q", "new")
+
+	deleted, err := m.DeleteProcessedEventsBefore(ctx, cutoff)
+	if err != nil || deleted != 1 {
+		t.Fatalf("expected 1 deleted, got %d (%v)", deleted, err)
+	}
+	if ok, _ := m.ClaimEvent(ctx, "q", "old", time.Minute); !ok {
+		t.Fatal("expected expired event to be forgotten")
+	}
+	if ok, _ := m.ClaimEvent(ctx, "q", "new", time.Minute); ok {
+		t.Fatal("expected recent event to be kept")
+	}
+}
+
+func TestWrapProcessesConcurrentDuplicatesOnce(t *testing.T) {
+	d := New(zap.NewNop(), NewMemory(100), time.Hour)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	handler := d.Wrap("q", func(ctx context.Context, msg amqp.Delivery) error {
+		calls.Add(1)
+		<-release
+		return nil
+	})
+
+	msg := amqp.Delivery{MessageId: "event-1"}
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := handler(context.Background(), msg); err != nil {
+				t.Errorf("handler: %v", err)
+			}
+		}()
+	}
+	// Копии, не занявшие событие, возвращаются сразу
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("expected 1 call, got %d", n)
+	}
+}
+
+func TestWrapReleasesEventOnFailure(t *testing.T) {
+	d := New(zap.NewNop(), NewMemory(100), time.Hour)
+
+	fail := true
+	var calls int
+	handler := d.Wrap("q", func(ctx context.Context, msg amqp.Delivery) error {
+		calls++
+		if fail {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	msg := amqp.Delivery{MessageId: "event-1"}
+	if err := handler(context.Background(), msg); err == nil {
+		t.Fatal("expected handler error")
+	}
+	fail = false
+	for i := 0; i < 2; i++ {
+		if err := handler(context.Background(), msg); err != nil {
+			t.Fatalf("handler: %v", err)
+		}
+	}
+
+	if calls != 2 {
+		t.Fatalf("expected retry to be processed once, got %d calls", calls)
+	}
+}
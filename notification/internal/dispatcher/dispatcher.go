@@ -0,0 +1,267 @@
+package dispatcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"notification_service/internal/channels"
+	"notification_service/internal/config"
+	"notification_service/internal/infra"
+	"notification_service/internal/infra/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	flushBatchSize = 500
+	retryDelay     = time.Minute
+	// claimLease — через столько забранные, но не отправленные уведомления
+	// снова становятся доступны, если реплика упала посреди отправки
+	claimLease = 5 * time.Minute
+)
+
+// Dispatcher рассылает уведомление по внешним каналам, выбранным пользователем
+// для типа события. Несрочные уведомления в тихие часы и в режиме дайджеста
+// откладываются и отправляются позже из FlushDeferred.
+type Dispatcher struct {
+	logger    *zap.Logger
+	templates *channels.Templates
+	db        *database.PostgresDB
+	defaults  []string
+	urgent    map[string]bool
+	channels  map[string]channels.Channel
+}
+
+func New(logger *zap.Logger, templates *channels.Templates, db *database.PostgresDB, cfg *config.Channels, chs ...channels.Channel) *Dispatcher {
+	byName := make(map[string]channels.Channel, len(chs))
+	for _, ch := range chs {
+		byName[ch.Name()] = ch
+	}
+
+	urgent := make(map[string]bool, len(cfg.Urgent))
+	for _, eventType := range cfg.Urgent {
+		urgent[eventType] = true
+	}
+
+	return &Dispatcher{
+		logger:    logger,
+		templates: templates,
+		db:        db,
+		defaults:  cfg.Default,
+		urgent:    urgent,
+		channels:  byName,
+	}
+}
+
+// Dispatch рендерит шаблон события и отправляет его во все выбранные каналы.
+// Ошибка одного канала не мешает остальным; возвращаются все ошибки разом.
+func (d *Dispatcher) Dispatch(ctx context.Context, userID uuid.UUID, eventType string, data any, raw json.RawMessage) error {
+	prefs, err := d.db.GetPreferences(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("db.GetPreferences: %w", err)
+	}
+
+	names := prefs.ChannelsFor(eventType, d.defaults)
+	if len(names) == 0 {
+		return nil
+	}
+
+	subject, body, err := d.templates.Render(eventType, data)
+	if err != nil {
+		return fmt.Errorf("templates.Render: %w", err)
+	}
+
+	var deliverAfter time.Time
+	if !d.urgent[eventType] {
+		deliverAfter = prefs.DeliverAfter(time.Now())
+	}
+
+	msg := &channels.Message{
+		Recipient: recipient(prefs),
+		EventType: eventType,
+		Subject:   subject,
+		Body:      body,
+		Data:      raw,
+	}
+
+	var errs []error
+	for _, name := range names {
+		if !deliverAfter.IsZero() {
+			if err := d.postpone(ctx, name, msg, deliverAfter); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+			continue
+		}
+
+		if err := d.send(ctx, name, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// FlushDeferred отправляет отложенные уведомления, время которых наступило.
+// Несколько уведомлений одному пользователю в один канал объединяются в дайджест.
+func (d *Dispatcher) FlushDeferred(ctx context.Context) error {
+	due, err := d.db.ClaimDueNotifications(ctx, flushBatchSize, claimLease)
+	if err != nil {
+		return fmt.Errorf("db.ClaimDueNotifications: %w", err)
+	}
+
+	type groupKey struct {
+		userID  uuid.UUID
+		channel string
+	}
+	var keys []groupKey
+	groups := make(map[groupKey][]*infra.DeferredNotification)
+	for _, n := range due {
+		key := groupKey{userID: n.UserID, channel: n.Channel}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], n)
+	}
+
+	var errs []error
+	for _, key := range keys {
+		group := groups[key]
+
+		prefs, err := d.db.GetPreferences(ctx, key.userID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("db.GetPreferences: %w", err))
+			d.retry(ctx, group)
+			continue
+		}
+
+		msg := digest(group)
+		msg.Recipient = recipient(prefs)
+
+		if err := d.send(ctx, key.channel, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key.channel, err))
+			d.retry(ctx, group)
+			continue
+		}
+		// Если удалить не удастся, уведомление уйдёт ещё раз после claimLease:
+		// дубль лучше потери
+		if err := d.db.DeleteDeferredNotifications(ctx, ids(group)); err != nil {
+			errs = append(errs, fmt.Errorf("db.DeleteDeferredNotifications: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Run периодически вызывает FlushDeferred, пока не отменён ctx.
+func (d *Dispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.FlushDeferred(ctx); err != nil {
+				d.logger.Error("failed to flush deferred notifications", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, name string, msg *channels.Message) error {
+	ch, ok := d.channels[name]
+	if !ok {
+		d.logger.Warn("channel is not enabled", zap.String("channel", name))
+		return nil
+	}
+
+	if err := ch.Send(ctx, msg); err != nil {
+		d.logger.Error("failed to send notification",
+			zap.String("channel", name),
+			zap.String("userID", msg.Recipient.UserID.String()),
+			zap.Error(err),
+		)
+		return err
+	}
+
+	d.logger.Info("notification sent", zap.String("channel", name), zap.String("userID", msg.Recipient.UserID.String()))
+	return nil
+}
+
+func (d *Dispatcher) postpone(ctx context.Context, name string, msg *channels.Message, deliverAfter time.Time) error {
+	err := d.db.DeferNotification(ctx, &infra.DeferredNotification{
+		UserID:       msg.Recipient.UserID,
+		EventType:    msg.EventType,
+		Channel:      name,
+		Subject:      msg.Subject,
+		Body:         msg.Body,
+		Data:         msg.Data,
+		DeliverAfter: deliverAfter,
+	})
+	if err != nil {
+		return err
+	}
+
+	d.logger.Info("notification deferred",
+		zap.String("channel", name),
+		zap.String("userID", msg.Recipient.UserID.String()),
+		zap.Time("deliverAfter", deliverAfter),
+	)
+	return nil
+}
+
+// retry откладывает отправку уведомлений на retryDelay. Если и это не удастся,
+// они вернутся в очередь после claimLease.
+func (d *Dispatcher) retry(ctx context.Context, group []*infra.DeferredNotification) {
+	if err := d.db.RescheduleDeferredNotifications(ctx, ids(group), time.Now().Add(retryDelay)); err != nil {
+		d.logger.Error("failed to reschedule deferred notifications", zap.Error(err))
+	}
+}
+
+func ids(group []*infra.DeferredNotification) []int64 {
+	ids := make([]int64, len(group))
+	for i, n := range group {
+		ids[i] = n.ID
+	}
+	return ids
+}
+
+func recipient(prefs *infra.Preferences) channels.Recipient {
+	return channels.Recipient{
+		UserID:     prefs.UserID,
+		Email:      prefs.Email,
+		Phone:      prefs.Phone,
+		WebhookURL: prefs.WebhookURL,
+	}
+}
+
+// digest собирает группу отложенных уведомлений в одно сообщение.
+func digest(group []*infra.DeferredNotification) *channels.Message {
+	if len(group) == 1 {
+		n := group[0]
+		return &channels.Message{EventType: n.EventType, Subject: n.Subject, Body: n.Body, Data: n.Data}
+	}
+
+	bodies := make([]string, len(group))
+	items := make([]json.RawMessage, 0, len(group))
+	for i, n := range group {
+		bodies[i] = n.Body
+		if len(n.Data) > 0 {
+			items = append(items, n.Data)
+		}
+	}
+	data, _ := json.Marshal(items)
+
+	return &channels.Message{
+		EventType: "digest",
+		Subject:   fmt.Sprintf("OrderQ: %d updates on your orders", len(group)),
+		Body:      strings.Join(bodies, "\n\n"),
+		Data:      data,
+	}
+}
@@ -1,203 +1,145 @@
 package entrypoint
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"sync"
 	"syscall"
 	"time"
 
+	"notification_service/internal/channels"
 	"notification_service/internal/config"
+	"notification_service/internal/dedup"
+	"notification_service/internal/dispatcher"
+	"notification_service/internal/handlers"
+	"notification_service/internal/hub"
 	impl "notification_service/internal/impl"
 	"notification_service/internal/infra/broker"
+	"notification_service/internal/infra/database"
+	"notification_service/internal/infra/store"
+	"notification_service/internal/interfaces"
+	proto "notification_service/proto/notification_service"
 
-	"github.com/google/uuid"
-	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 )
 
-var clients = sync.Map{}
-
-func addClient(userID uuid.UUID, conn *websocket.Conn) {
-	value, _ := clients.LoadOrStore(userID, []*websocket.Conn{})
-	conns := value.([]*websocket.Conn)
-	conns = append(conns, conn)
-	clients.Store(userID, conns)
-}
+func Run(cfg *config.Config, logger *zap.Logger) error {
 
-func removeClient(userID uuid.UUID, conn *websocket.Conn) {
-	value, ok := clients.Load(userID)
-	if !ok {
-		return
-	}
-	conns := value.([]*websocket.Conn)
-	var newConns []*websocket.Conn
-	for _, c := range conns {
-		if c != conn {
-			newConns = append(newConns, c)
-		}
-	}
-	if len(newConns) == 0 {
-		clients.Delete(userID)
-	} else {
-		clients.Store(userID, newConns)
+	db, err := database.New(logger, &cfg.Postgres)
+	if err != nil {
+		logger.Fatal("failed to create database", zap.Error(err))
 	}
-}
+	defer db.Close()
 
-func SendNotification(userID uuid.UUID, msg any) {
-	value, ok := clients.Load(userID)
-	if !ok {
-		return
+	broker, err := broker.New(logger, &cfg.RabbitMQ)
+	if err != nil {
+		logger.Fatal("failed to create broker", zap.Error(err))
 	}
+	defer broker.Close()
 
-	conns := value.([]*websocket.Conn)
-	for _, conn := range conns {
-		err := conn.WriteJSON(msg)
-		if err != nil {
-			log.Println("Write error:", err)
-		}
-	}
-}
+	done := make(chan os.Signal, 1)
+	signal.Notify(done, syscall.SIGINT, syscall.SIGTERM)
 
-func WebSocketHandler(w http.ResponseWriter, r *http.Request) {
-	userIDStr := r.URL.Query().Get("user_id")
-	if userIDStr == "" {
-		http.Error(w, "Missing user_id", http.StatusBadRequest)
-		return
-	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		http.Error(w, "Invalid user_id format", http.StatusBadRequest)
-		return
-	}
+	eventStore := store.New(&cfg.Replay)
+	notificationHub := hub.New(logger, eventStore, db, broker, cfg.StreamSecret)
 
-	// 3. Upgrade до WebSocket
-	upgrader := websocket.Upgrader{
-		CheckOrigin: func(r *http.Request) bool { return true }, // на проде проверь origin!
+	templates, err := channels.LoadTemplates()
+	if err != nil {
+		logger.Fatal("failed to load templates", zap.Error(err))
 	}
-
-	conn, err := upgrader.Upgrade(w, r, nil)
+	outbound, err := channels.FromConfig(&cfg.Channels, cfg.Secret)
 	if err != nil {
-		log.Println("WebSocket upgrade error:", err)
-		return
+		logger.Fatal("failed to create channels", zap.Error(err))
 	}
+	notificationDispatcher := dispatcher.New(logger, templates, db, &cfg.Channels, outbound...)
+	go notificationDispatcher.Run(ctx, time.Minute)
 
-	// 4. Сохраняем соединение
-	addClient(userID, conn)
-	log.Println("Client connected:", userID)
-
-	// 5. Слушаем клиент → если отключился — удаляем
-	go func() {
-		defer func() {
-			removeClient(userID, conn)
-			conn.Close()
-			log.Println("Client disconnected:", userID)
-		}()
-		// Keep connection alive by reading from it
-		for {
-			_, _, err := conn.ReadMessage()
-			if err != nil {
-				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-					log.Printf("WebSocket error: %v", err)
-				}
-				break
-			}
-		}
-	}()
-}
-
-type Order struct {
-	OrderID       uuid.UUID     `json:"order_id"`
-	UserID        uuid.UUID     `json:"user_id"`
-	AgentID       uuid.UUID     `json:"agent_id"`
-	OrderAddress  string        `json:"order_address"`
-	OrderLocation string        `json:"order_location"`
-	OrderDate     time.Time     `json:"order_date"`
-	OrderTimeGap  time.Duration `json:"order_time_gap"`
-	OrderStatus   string        `json:"order_status"`
-}
-
-func Run(cfg *config.Config, logger *zap.Logger) error {
-
-	broker, err := broker.New(logger, &cfg.RabbitMQ)
-	if err != nil {
-		logger.Fatal("failed to create broker", zap.Error(err))
+	var processed dedup.Store = db
+	if cfg.Dedup.Store == "memory" {
+		processed = dedup.NewMemory(cfg.Dedup.CacheSize)
 	}
-	defer broker.Close()
+	deduplicator := dedup.New(logger, processed, cfg.Dedup.TTL)
+	go deduplicator.Run(ctx, time.Hour)
 
-	// grpcServer := grpc.NewServer()
-	// proto.RegisterNotificationServiceServer(grpcServer, handlers.New(impl.New(logger, broker)))
+	service := impl.New(logger, db, broker, notificationHub, notificationDispatcher, deduplicator)
 
-	done := make(chan os.Signal, 1)
-	signal.Notify(done, syscall.SIGINT, syscall.SIGTERM)
+	grpcServer := grpc.NewServer()
+	proto.RegisterNotificationServiceServer(grpcServer, handlers.New(service))
 
-	// go func() {
-	// 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GRPCPort))
-	// 	if err != nil {
-	// 		logger.Fatal("failed to listen", zap.Error(err))
-	// 	}
-	// 	logger.Info("Notification service started", zap.String("port", cfg.GRPCPort))
-	// 	if err := grpcServer.Serve(lis); err != nil {
-	// 		logger.Error("failed to serve", zap.Error(err))
-	// 	}
-	// }()
-
-	http.HandleFunc("/ws", WebSocketHandler)
 	go func() {
-		err := http.ListenAndServe(":8081", nil)
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GRPCPort))
 		if err != nil {
-			logger.Error("failed to start http server", zap.Error(err))
+			logger.Fatal("failed to listen", zap.Error(err))
+		}
+		logger.Info("Notification service started", zap.String("port", cfg.GRPCPort))
+		if err := grpcServer.Serve(lis); err != nil {
+			logger.Error("failed to serve", zap.Error(err))
 		}
 	}()
 
 	go func() {
-		msgs, err := broker.GetChannel().Consume(
-			"queue_order_cancelled",
-			"",
-			false,
-			false,
-			false,
-			false,
-			nil,
-		)
-
+		err := broker.ConsumeDeliveries(ctx, notificationHub.Deliver)
 		if err != nil {
-			logger.Error("failed to consume messages", zap.Error(err))
-		}
-
-		for msg := range msgs {
-			logger.Info("received cancelled order", zap.String("message", string(msg.Body)))
-			logger.Info("message", zap.String("message", string(msg.Body)))
-			order := Order{}
-			err := json.Unmarshal(msg.Body, &order)
-			if err != nil {
-				logger.Error("failed to unmarshal message", zap.Error(err))
-			}
-			SendNotification(order.UserID, msg.Body)
+			logger.Error("failed to consume deliveries", zap.Error(err))
 		}
 	}()
 
 	go func() {
-		err := impl.New(logger, broker).HandleOrderCancelledMessages()
-		if err != nil {
-			logger.Error("failed to handle messages", zap.Error(err))
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				eventStore.Cleanup()
+			}
 		}
 	}()
 
+	http.HandleFunc("/ws", notificationHub.WebSocketHandler)
+	http.HandleFunc("/events", notificationHub.EventsHandler)
+	http.HandleFunc("/healthz", healthHandler(service))
+	http.Handle("/metrics", promhttp.Handler())
+	if cfg.Channels.SMS.Fake {
+		http.HandleFunc("/dev/sms", channels.FakeSMSProvider(logger))
+	}
 	go func() {
-		err := impl.New(logger, broker).HandleOrderCompletedMessages()
+		err := http.ListenAndServe(":8081", nil)
 		if err != nil {
-			logger.Error("failed to handle messages", zap.Error(err))
+			logger.Error("failed to start http server", zap.Error(err))
 		}
 	}()
 
+	go service.HandleOrderMessages(ctx)
+
 	<-done
 	logger.Info("Notification service stopped")
-	// grpcServer.Stop()
+	cancel()
+	grpcServer.Stop()
 
 	return nil
 }
+
+// healthHandler отвечает 503, пока сервис не может читать события из RabbitMQ.
+func healthHandler(service interfaces.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if err := service.HealthCheck(r.Context()); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]string{"status": "unavailable", "error": err.Error()})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	}
+}
@@ -2,8 +2,16 @@ package handlers
 
 import (
 	"context"
+	"errors"
+
+	"notification_service/internal/impl"
 	"notification_service/internal/interfaces"
+	"notification_service/internal/mapper"
 	pb "notification_service/proto/notification_service"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type NotificationService struct {
@@ -16,5 +24,41 @@ func New(service interfaces.Service) *NotificationService {
 }
 
 func (s *NotificationService) HealthCheck(ctx context.Context, req *pb.HealthCheckRequest) (*pb.HealthCheckResponse, error) {
+	if err := s.service.HealthCheck(ctx); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "unhealthy: %v", err)
+	}
 	return &pb.HealthCheckResponse{Status: "OK"}, nil
 }
+
+func (s *NotificationService) GetPreferences(ctx context.Context, req *pb.GetPreferencesRequest) (*pb.GetPreferencesResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user_id: %v", err)
+	}
+
+	prefs, err := s.service.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "get preferences failed: %v", err)
+	}
+
+	return &pb.GetPreferencesResponse{Preferences: mapper.ToPbPreferences(prefs)}, nil
+}
+
+func (s *NotificationService) UpdatePreferences(ctx context.Context, req *pb.UpdatePreferencesRequest) (*pb.UpdatePreferencesResponse, error) {
+	userID, err := uuid.Parse(req.GetPreferences().GetUserId())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid user_id: %v", err)
+	}
+
+	prefs := mapper.ToInfraPreferences(req.GetPreferences())
+	prefs.UserID = userID
+
+	if err := s.service.UpdatePreferences(ctx, prefs); err != nil {
+		if errors.Is(err, impl.ErrInvalidPreferences) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "update preferences failed: %v", err)
+	}
+
+	return &pb.UpdatePreferencesResponse{Preferences: mapper.ToPbPreferences(prefs)}, nil
+}
@@ -0,0 +1,35 @@
+package hub
+
+import (
+	"sync"
+
+	"notification_service/internal/infra/store"
+)
+
+// LocalBus — шина внутри одного процесса. Используется, когда сервис запущен
+// в одном экземпляре без брокера, и в тестах.
+type LocalBus struct {
+	mu   sync.RWMutex
+	hubs []*Hub
+}
+
+func NewLocalBus() *LocalBus {
+	return &LocalBus{}
+}
+
+func (b *LocalBus) Attach(h *Hub) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.hubs = append(b.hubs, h)
+}
+
+func (b *LocalBus) Broadcast(event *store.Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, h := range b.hubs {
+		h.Deliver(event)
+	}
+	return nil
+}
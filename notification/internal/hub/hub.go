@@ -0,0 +1,152 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"notification_service/internal/infra/store"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// EventResync отправляется клиенту, если часть событий после его last_event_id
+// уже вытеснена из хранилища и состояние нужно перечитать целиком.
+const EventResync = "resync"
+
+// Client — подключение пользователя, в которое хаб доставляет события.
+// Send не должен блокироваться: false означает, что клиент не успевает читать.
+type Client interface {
+	Send(event *store.Event) bool
+	Close()
+}
+
+// Sequence выдаёт ID событий пользователя. Источник должен быть общим для всех
+// реплик: ID, выданные двумя репликами независимо, совпали бы.
+type Sequence interface {
+	NextEventID(ctx context.Context, userID uuid.UUID) (uint64, error)
+}
+
+// Bus рассылает событие всем репликам сервиса, включая отправителя.
+// Каждая реплика получает его в Deliver и доставляет своим клиентам.
+type Bus interface {
+	Broadcast(event *store.Event) error
+}
+
+type Hub struct {
+	logger *zap.Logger
+	store  *store.EventStore
+	seq    Sequence
+	bus    Bus
+	// streamSecret — общий с gateway секрет токенов /ws и /events
+	streamSecret []byte
+	mu           sync.Mutex
+	clients      map[uuid.UUID]map[Client]struct{}
+}
+
+func New(logger *zap.Logger, store *store.EventStore, seq Sequence, bus Bus, streamSecret string) *Hub {
+	return &Hub{
+		logger:       logger,
+		store:        store,
+		seq:          seq,
+		bus:          bus,
+		streamSecret: []byte(streamSecret),
+		clients:      make(map[uuid.UUID]map[Client]struct{}),
+	}
+}
+
+// Publish присваивает событию ID и рассылает его через шину всем репликам:
+// пользователь может быть подключён к любой из них. В хранилище событие
+// попадает уже в Deliver, поэтому клиент не получит его дважды — из replay и live.
+func (h *Hub) Publish(ctx context.Context, userID uuid.UUID, eventType string, data json.RawMessage) (*store.Event, error) {
+	id, err := h.seq.NextEventID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("seq.NextEventID: %w", err)
+	}
+
+	event := &store.Event{
+		ID:     id,
+		UserID: userID,
+		Type:   eventType,
+		Time:   time.Now().UTC(),
+		Data:   data,
+	}
+	if err := h.bus.Broadcast(event); err != nil {
+		return nil, fmt.Errorf("bus.Broadcast: %w", err)
+	}
+
+	return event, nil
+}
+
+// Deliver сохраняет событие, пришедшее из шины, для replay и отправляет его
+// подключённым к этой реплике клиентам пользователя. Событие, которое уже есть
+// в хранилище, клиенты уже получили, и второй раз оно не отправляется.
+func (h *Hub) Deliver(event *store.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.store.Put(event) {
+		h.logger.Debug("duplicate event skipped", zap.Uint64("eventID", event.ID))
+		return
+	}
+
+	userID := event.UserID
+	for c := range h.clients[userID] {
+		if !c.Send(event) {
+			h.logger.Warn("client is too slow, dropping connection", zap.String("userID", userID.String()))
+			h.remove(userID, c)
+			c.Close()
+		}
+	}
+}
+
+// Subscribe подключает клиента. Если передан lastEventID, сначала досылаются все
+// события после него, и только потом клиент начинает получать новые — под одной
+// блокировкой, чтобы между replay и live-доставкой ничего не потерялось.
+func (h *Hub) Subscribe(userID uuid.UUID, c Client, lastEventID *uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if lastEventID != nil {
+		events, complete := h.store.Since(userID, *lastEventID)
+		if !complete {
+			events = []*store.Event{{
+				ID:     h.store.LastID(userID),
+				UserID: userID,
+				Type:   EventResync,
+				Time:   time.Now().UTC(),
+			}}
+		}
+		for _, e := range events {
+			c.Send(e)
+		}
+	}
+
+	conns, ok := h.clients[userID]
+	if !ok {
+		conns = make(map[Client]struct{})
+		h.clients[userID] = conns
+	}
+	conns[c] = struct{}{}
+}
+
+func (h *Hub) Unsubscribe(userID uuid.UUID, c Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(userID, c)
+}
+
+func (h *Hub) remove(userID uuid.UUID, c Client) {
+	conns, ok := h.clients[userID]
+	if !ok {
+		return
+	}
+	delete(conns, c)
+	if len(conns) == 0 {
+		delete(h.clients, userID)
+	}
+}
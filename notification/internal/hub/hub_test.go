@@ -0,0 +1,183 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"notification_service/internal/config"
+	"notification_service/internal/infra/store"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type recorder struct {
+	events []*store.Event
+}
+
+func (r *recorder) Send(event *store.Event) bool {
+	r.events = append(r.events, event)
+	return true
+}
+
+func (r *recorder) Close() {}
+
+func newReplica(bus *LocalBus, seq Sequence) *Hub {
+	h := New(zap.NewNop(), store.New(&config.Replay{Retention: time.Hour, MaxEvents: 100}), seq, bus, testStreamSecret)
+	bus.Attach(h)
+	return h
+}
+
+func TestPublishReachesClientOnOtherReplica(t *testing.T) {
+	bus := NewLocalBus()
+	seq := NewLocalSequence()
+	a := newReplica(bus, seq)
+	b := newReplica(bus, seq)
+
+	userID := uuid.New()
+	client := &recorder{}
+	b.Subscribe(userID, client, nil)
+
+	event, err := a.Publish(context.Background(), userID, "order.cancelled", json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if len(client.events) != 1 {
+		t.Fatalf("expected 1 event on replica b, got %d", len(client.events))
+	}
+	if client.events[0].ID != event.ID {
+		t.Fatalf("expected event id %d, got %d", event.ID, client.events[0].ID)
+	}
+}
+
+func TestReplayOnAnyReplica(t *testing.T) {
+	bus := NewLocalBus()
+	seq := NewLocalSequence()
+	a := newReplica(bus, seq)
+	b := newReplica(bus, seq)
+
+	userID := uuid.New()
+	for i := 0; i < 3; i++ {
+		if _, err := a.Publish(context.Background(), userID, "order.created", json.RawMessage(`{}`)); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	// Клиент видел первое событие на реплике a и переподключился к b
+	lastEventID := uint64(1)
+	client := &recorder{}
+	b.Subscribe(userID, client, &lastEventID)
+
+	if len(client.events) != 2 {
+		t.Fatalf("expected 2 replayed events, got %d", len(client.events))
+	}
+	if client.events[0].ID != 2 || client.events[1].ID != 3 {
+		t.Fatalf("unexpected replay order: %d, %d", client.events[0].ID, client.events[1].ID)
+	}
+
+	// Следующее событие, опубликованное репликой b, продолжает общую нумерацию
+	event, err := b.Publish(context.Background(), userID, "order.completed", json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if event.ID != 4 {
+		t.Fatalf("expected next id 4, got %d", event.ID)
+	}
+	if len(client.events) != 3 {
+		t.Fatalf("expected live event after replay, got %d events", len(client.events))
+	}
+}
+
+func TestReplayGapRequestsResync(t *testing.T) {
+	bus := NewLocalBus()
+	h := New(zap.NewNop(), store.New(&config.Replay{Retention: time.Hour, MaxEvents: 2}), NewLocalSequence(), bus, testStreamSecret)
+	bus.Attach(h)
+
+	userID := uuid.New()
+	for i := 0; i < 5; i++ {
+		if _, err := h.Publish(context.Background(), userID, "order.created", json.RawMessage(`{}`)); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+
+	lastEventID := uint64(1)
+	client := &recorder{}
+	h.Subscribe(userID, client, &lastEventID)
+
+	if len(client.events) != 1 || client.events[0].Type != EventResync {
+		t.Fatalf("expected a single resync event, got %+v", client.events)
+	}
+	if client.events[0].ID != 5 {
+		t.Fatalf("expected resync to carry last id 5, got %d", client.events[0].ID)
+	}
+}
+
+func TestConcurrentReplicasShareSequence(t *testing.T) {
+	bus := NewLocalBus()
+	seq := NewLocalSequence()
+	a := newReplica(bus, seq)
+	b := newReplica(bus, seq)
+
+	userID := uuid.New()
+	client := &recorder{}
+	b.Subscribe(userID, client, nil)
+
+	const perReplica = 40
+	var wg sync.WaitGroup
+	for _, h := range []*Hub{a, b} {
+		wg.Add(1)
+		go func(h *Hub) {
+			defer wg.Done()
+			for i := 0; i < perReplica; i++ {
+				if _, err := h.Publish(context.Background(), userID, "order.updated", json.RawMessage(`{}`)); err != nil {
+					t.Errorf("Publish: %v", err)
+				}
+			}
+		}(h)
+	}
+	wg.Wait()
+
+	seen := make(map[uint64]bool)
+	for _, e := range client.events {
+		if seen[e.ID] {
+			t.Fatalf("event id %d delivered twice", e.ID)
+		}
+		seen[e.ID] = true
+	}
+	if len(seen) != 2*perReplica {
+		t.Fatalf("expected %d live events, got %d", 2*perReplica, len(seen))
+	}
+
+	// Ни одно событие не потерялось для replay ни на одной из реплик
+	lastEventID := uint64(0)
+	for name, h := range map[string]*Hub{"a": a, "b": b} {
+		replayed := &recorder{}
+		h.Subscribe(userID, replayed, &lastEventID)
+		if len(replayed.events) != 2*perReplica {
+			t.Fatalf("expected %d replayed events on replica %s, got %d", 2*perReplica, name, len(replayed.events))
+		}
+	}
+}
+
+func TestRedeliveredEventIsNotSentTwice(t *testing.T) {
+	bus := NewLocalBus()
+	h := newReplica(bus, NewLocalSequence())
+
+	userID := uuid.New()
+	client := &recorder{}
+	h.Subscribe(userID, client, nil)
+
+	event, err := h.Publish(context.Background(), userID, "order.created", json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	h.Deliver(event)
+
+	if len(client.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(client.events))
+	}
+}
@@ -0,0 +1,27 @@
+package hub
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// LocalSequence — последовательность ID внутри одного процесса. Годится, только
+// когда все хабы работают в нём же: одна реплика без брокера и тесты.
+type LocalSequence struct {
+	mu     sync.Mutex
+	lastID map[uuid.UUID]uint64
+}
+
+func NewLocalSequence() *LocalSequence {
+	return &LocalSequence{lastID: make(map[uuid.UUID]uint64)}
+}
+
+func (s *LocalSequence) NextEventID(_ context.Context, userID uuid.UUID) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID[userID]++
+	return s.lastID[userID], nil
+}
@@ -0,0 +1,126 @@
+package hub
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"notification_service/internal/infra/store"
+
+	"go.uber.org/zap"
+)
+
+const heartbeatInterval = 15 * time.Second
+
+type sseClient struct {
+	send chan *store.Event
+	done chan struct{}
+	once sync.Once
+}
+
+func (c *sseClient) Send(event *store.Event) bool {
+	select {
+	case <-c.done:
+		return false
+	case c.send <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *sseClient) Close() {
+	c.once.Do(func() { close(c.done) })
+}
+
+// EventsHandler — Server-Sent Events альтернатива /ws для клиентов за прокси,
+// которые не пропускают WebSocket upgrade. Отдаёт те же события; позиция для
+// досылки берётся из заголовка Last-Event-ID (его шлёт EventSource при
+// переподключении) или из query-параметра last_event_id.
+func (h *Hub) EventsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.streamUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	lastEventID, errMsg := parseLastEventID(r)
+	if errMsg != "" {
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid Last-Event-ID format", http.StatusBadRequest)
+			return
+		}
+		lastEventID = &id
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	client := &sseClient{
+		send: make(chan *store.Event, sendBufferSize+h.store.MaxEvents()),
+		done: make(chan struct{}),
+	}
+
+	h.Subscribe(userID, client, lastEventID)
+	h.logger.Info("SSE client connected", zap.String("userID", userID.String()))
+
+	defer func() {
+		h.Unsubscribe(userID, client)
+		client.Close()
+		h.logger.Info("SSE client disconnected", zap.String("userID", userID.String()))
+	}()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event := <-client.send:
+			if err := writeSSEEvent(w, event); err != nil {
+				h.logger.Warn("SSE write error", zap.Error(err))
+				return
+			}
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-client.done:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeSSEEvent(w http.ResponseWriter, event *store.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	// Тип события уже есть в конверте, поэтому поле event не задаём —
+	// так все сообщения приходят в EventSource.onmessage
+	_, err = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", event.ID, data)
+	return err
+}
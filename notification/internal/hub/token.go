@@ -0,0 +1,67 @@
+package hub
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Токен потока выдаёт gateway после проверки JWT: "<user_id>.<exp unix>.<подпись>",
+// подпись — HMAC-SHA256 от "<user_id>.<exp unix>" общим с gateway секретом.
+var (
+	errMissingToken = errors.New("missing token")
+	errInvalidToken = errors.New("invalid token")
+	errExpiredToken = errors.New("token expired")
+)
+
+// verifyStreamToken проверяет подпись и срок токена и возвращает пользователя.
+func verifyStreamToken(secret []byte, token string, now time.Time) (uuid.UUID, error) {
+	if token == "" {
+		return uuid.Nil, errMissingToken
+	}
+
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 {
+		return uuid.Nil, errInvalidToken
+	}
+	payload, signature := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(streamSignature(secret, payload))) {
+		return uuid.Nil, errInvalidToken
+	}
+
+	userIDStr, expStr, ok := strings.Cut(payload, ".")
+	if !ok {
+		return uuid.Nil, errInvalidToken
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return uuid.Nil, errInvalidToken
+	}
+	exp, err := strconv.ParseInt(expStr, 10, 64)
+	if err != nil {
+		return uuid.Nil, errInvalidToken
+	}
+	if !now.Before(time.Unix(exp, 0)) {
+		return uuid.Nil, errExpiredToken
+	}
+
+	return userID, nil
+}
+
+func streamSignature(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// streamUser определяет пользователя потока по query-параметру token.
+func (h *Hub) streamUser(r *http.Request) (uuid.UUID, error) {
+	return verifyStreamToken(h.streamSecret, r.URL.Query().Get("token"), time.Now())
+}
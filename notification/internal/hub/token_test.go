@@ -0,0 +1,114 @@
+package hub
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const testStreamSecret = "test-stream-secret"
+
+// signStreamToken подписывает токен потока так же, как gateway.
+func signStreamToken(secret []byte, userID uuid.UUID, expiresAt time.Time) string {
+	payload := userID.String() + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + streamSignature(secret, payload)
+}
+
+func TestVerifyStreamToken(t *testing.T) {
+	secret := []byte(testStreamSecret)
+	now := time.Unix(1_800_000_000, 0)
+	userID := uuid.New()
+	valid := signStreamToken(secret, userID, now.Add(time.Minute))
+	payload := userID.String() + "." + strconv.FormatInt(now.Add(time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "valid", token: valid},
+		{name: "missing", token: "", wantErr: errMissingToken},
+		{name: "expired", token: signStreamToken(secret, userID, now), wantErr: errExpiredToken},
+		{name: "other secret", token: signStreamToken([]byte("other"), userID, now.Add(time.Minute)), wantErr: errInvalidToken},
+		{name: "other user", token: uuid.NewString() + valid[len(userID.String()):], wantErr: errInvalidToken},
+		{name: "no signature", token: payload, wantErr: errInvalidToken},
+		{name: "garbage", token: "garbage", wantErr: errInvalidToken},
+		{name: "bad user id", token: "user.1." + streamSignature(secret, "user.1"), wantErr: errInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifyStreamToken(secret, tt.token, now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got != userID {
+				t.Errorf("user = %s, want %s", got, userID)
+			}
+		})
+	}
+}
+
+// Тот же вектор подписывает gateway в своих тестах.
+func TestVerifyGatewayToken(t *testing.T) {
+	token := "3f1c2a9e-5b7d-4e21-9c0a-6d8f4b2e1a77.1800000060.xrQbkUtk7xQ4293DAEQEhiuIEExPnNs5fHS1dEi2R3g"
+	got, err := verifyStreamToken([]byte(testStreamSecret), token, time.Unix(1800000000, 0))
+	if err != nil {
+		t.Fatalf("verifyStreamToken: %v", err)
+	}
+	if got.String() != "3f1c2a9e-5b7d-4e21-9c0a-6d8f4b2e1a77" {
+		t.Errorf("user = %s", got)
+	}
+}
+
+func TestStreamHandlersRequireToken(t *testing.T) {
+	h := newReplica(NewLocalBus(), NewLocalSequence())
+	userID := uuid.New()
+
+	tests := []struct {
+		name  string
+		query url.Values
+		want  int
+	}{
+		{name: "user_id only", query: url.Values{"user_id": {userID.String()}}, want: http.StatusUnauthorized},
+		{name: "expired token", query: url.Values{"token": {signStreamToken([]byte(testStreamSecret), userID, time.Now().Add(-time.Second))}}, want: http.StatusUnauthorized},
+		{name: "forged token", query: url.Values{"token": {signStreamToken([]byte("forged"), userID, time.Now().Add(time.Minute))}}, want: http.StatusUnauthorized},
+		{name: "bad last_event_id", query: url.Values{"token": {signStreamToken([]byte(testStreamSecret), userID, time.Now().Add(time.Minute))}, "last_event_id": {"x"}}, want: http.StatusBadRequest},
+	}
+	handlers := map[string]http.HandlerFunc{"/events": h.EventsHandler, "/ws": h.WebSocketHandler}
+	for path, handler := range handlers {
+		for _, tt := range tests {
+			t.Run(path+" "+tt.name, func(t *testing.T) {
+				rec := httptest.NewRecorder()
+				handler(rec, httptest.NewRequest(http.MethodGet, path+"?"+tt.query.Encode(), nil))
+				if rec.Code != tt.want {
+					t.Errorf("status = %d, want %d", rec.Code, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestEventsHandlerAcceptsToken(t *testing.T) {
+	h := newReplica(NewLocalBus(), NewLocalSequence())
+	token := signStreamToken([]byte(testStreamSecret), uuid.New(), time.Now().Add(time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/events?token="+url.QueryEscape(token), nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	h.EventsHandler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("Content-Type = %q", got)
+	}
+}
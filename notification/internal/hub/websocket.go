@@ -0,0 +1,135 @@
+package hub
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"notification_service/internal/infra/store"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+const (
+	sendBufferSize = 64
+	writeTimeout   = 10 * time.Second
+	pingInterval   = 30 * time.Second
+)
+
+type wsClient struct {
+	conn *websocket.Conn
+	send chan *store.Event
+	done chan struct{}
+	once sync.Once
+}
+
+func (c *wsClient) Send(event *store.Event) bool {
+	select {
+	case <-c.done:
+		return false
+	case c.send <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *wsClient) Close() {
+	c.once.Do(func() { close(c.done) })
+}
+
+func (c *wsClient) writePump(logger *zap.Logger) {
+	ticker := time.NewTicker(pingInterval)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case event := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := c.conn.WriteJSON(event); err != nil {
+				logger.Warn("websocket write error", zap.Error(err))
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// parseLastEventID достаёт необязательный last_event_id из query-параметров.
+func parseLastEventID(r *http.Request) (*uint64, string) {
+	lastEventIDStr := r.URL.Query().Get("last_event_id")
+	if lastEventIDStr == "" {
+		return nil, ""
+	}
+
+	lastEventID, err := strconv.ParseUint(lastEventIDStr, 10, 64)
+	if err != nil {
+		return nil, "Invalid last_event_id format"
+	}
+
+	return &lastEventID, ""
+}
+
+func (h *Hub) WebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.streamUser(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	lastEventID, errMsg := parseLastEventID(r)
+	if errMsg != "" {
+		http.Error(w, errMsg, http.StatusBadRequest)
+		return
+	}
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true }, // на проде проверь origin!
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.logger.Error("WebSocket upgrade error", zap.Error(err))
+		return
+	}
+
+	client := &wsClient{
+		conn: conn,
+		send: make(chan *store.Event, sendBufferSize+h.store.MaxEvents()),
+		done: make(chan struct{}),
+	}
+
+	// Сначала досылаем пропущенные события, затем подписываем на новые
+	h.Subscribe(userID, client, lastEventID)
+	h.logger.Info("Client connected", zap.String("userID", userID.String()))
+
+	go client.writePump(h.logger)
+
+	// Слушаем клиент → если отключился — удаляем
+	go func() {
+		defer func() {
+			h.Unsubscribe(userID, client)
+			client.Close()
+			h.logger.Info("Client disconnected", zap.String("userID", userID.String()))
+		}()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+					h.logger.Warn("WebSocket error", zap.Error(err))
+				}
+				return
+			}
+		}
+	}()
+}
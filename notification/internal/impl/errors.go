@@ -0,0 +1,5 @@
+package impl
+
+import "errors"
+
+var ErrInvalidPreferences = errors.New("invalid preferences")
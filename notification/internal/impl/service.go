@@ -1,83 +1,220 @@
 package impl
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"notification_service/internal/channels"
+	"notification_service/internal/dedup"
+	"notification_service/internal/dispatcher"
+	"notification_service/internal/hub"
+	"notification_service/internal/infra"
 	"notification_service/internal/infra/broker"
+	"notification_service/internal/infra/database"
 	"notification_service/internal/interfaces"
+	"orderq/pkg/events"
 
+	"github.com/google/uuid"
+	"github.com/streadway/amqp"
 	"go.uber.org/zap"
 )
 
 type service struct {
-	logger *zap.Logger
-	broker *broker.RabbitMQ
+	logger     *zap.Logger
+	db         *database.PostgresDB
+	broker     *broker.RabbitMQ
+	hub        *hub.Hub
+	dispatcher *dispatcher.Dispatcher
+	dedup      *dedup.Deduplicator
 }
 
-func New(logger *zap.Logger, broker *broker.RabbitMQ) interfaces.Service {
-	return &service{logger: logger, broker: broker}
+func New(logger *zap.Logger, db *database.PostgresDB, broker *broker.RabbitMQ, hub *hub.Hub, dispatcher *dispatcher.Dispatcher, dedup *dedup.Deduplicator) interfaces.Service {
+	return &service{logger: logger, db: db, broker: broker, hub: hub, dispatcher: dispatcher, dedup: dedup}
 }
 
-func (s *service) HandleOrderCreatedMessages() error {
-	msgs, err := s.broker.GetChannel().Consume(
-		"queue_order_created",
-		"",
-		false,
-		false,
-		false,
-		false,
-		nil,
-	)
+// HealthCheck сообщает об ошибке, пока нет подключения к RabbitMQ: в это время
+// сервис не получает события заказов и не рассылает уведомления.
+func (s *service) HealthCheck(ctx context.Context) error {
+	if state := s.broker.State(); state != broker.StateConnected {
+		return fmt.Errorf("rabbitmq is %s", state)
+	}
+	return nil
+}
 
-	if err != nil {
-		s.logger.Error("failed to consume messages", zap.Error(err))
-		return err
+// subscription связывает очередь уведомлений с событием заказа и тем, кому
+// уведомление адресовано.
+type subscription struct {
+	queue     string
+	eventType string
+	recipient recipientFunc
+}
+
+// subscriptions — очереди, которые читает сервис. Новое событие заказа
+// добавляется сюда и в events.NotificationConsumer.
+var subscriptions = []subscription{
+	{events.NotificationOrderCreated, events.OrderCreated, orderOwner},
+	{events.NotificationOrderCancelled, events.OrderCancelled, orderOwner},
+	{events.NotificationOrderCompleted, events.OrderCompleted, orderOwner},
+	{events.NotificationOrderExpired, events.OrderExpired, orderOwner},
+	{events.NotificationOrderUpdated, events.OrderUpdated, orderOwner},
+	{events.NotificationOrderAssigned, events.OrderAssigned, orderOwner},
+	// Исполнителю — что клиент выбрал другого или заказа больше нет
+	{events.NotificationCandidateReleased, events.OrderCandidateReleased, eventAgent},
+	{events.NotificationOrderOffered, events.OrderOffered, eventAgent},
+	{events.NotificationOfferExpired, events.OrderOfferExpired, eventAgent},
+	// Владельцу заказа — место исполнителя и ETA
+	{events.NotificationAgentLocation, events.OrderAgentLocation, orderOwner},
+}
+
+// HandleOrderMessages читает все очереди subscriptions и рассылает уведомления,
+// пока не отменён ctx. Сбой одной очереди не останавливает остальные.
+func (s *service) HandleOrderMessages(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, sub := range subscriptions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.consumeOrderMessages(ctx, sub); err != nil {
+				s.logger.Error("failed to handle messages", zap.String("queue", sub.queue), zap.Error(err))
+			}
+		}()
 	}
+	wg.Wait()
+}
+
+// liveOnlyEvents уходят только открытым страницам: место исполнителя
+// устаревает за минуты, и по почте или SMS его слать незачем.
+var liveOnlyEvents = map[string]bool{
+	events.OrderAgentLocation: true,
+}
+
+// recipientFunc выбирает, кому адресовано уведомление о событии.
+type recipientFunc func(event events.OrderEvent, order events.Order) uuid.UUID
+
+func orderOwner(_ events.OrderEvent, order events.Order) uuid.UUID {
+	return order.UserID
+}
 
-	for msg := range msgs {
-		s.logger.Info("received created order", zap.String("message", string(msg.Body)))
+// eventAgent — исполнитель из поля agent_id события, а не из заказа.
+func eventAgent(event events.OrderEvent, _ events.Order) uuid.UUID {
+	e, ok := event.(interface{ GetAgentId() string })
+	if !ok {
+		return uuid.Nil
 	}
-	return nil
+	agentID, err := uuid.Parse(e.GetAgentId())
+	if err != nil {
+		return uuid.Nil
+	}
+	return agentID
 }
 
-func (s *service) HandleOrderCancelledMessages() error {
-	msgs, err := s.broker.GetChannel().Consume(
-		"queue_order_cancelled",
-		"",
-		false,
-		false,
-		false,
-		false,
-		nil,
-	)
+func (s *service) consumeOrderMessages(ctx context.Context, sub subscription) error {
+	return s.broker.Consume(ctx, sub.queue, s.dedup.Wrap(sub.queue, func(ctx context.Context, msg amqp.Delivery) error {
+		s.logger.Info("received order event", zap.String("type", sub.eventType), zap.String("messageID", msg.MessageId))
+		return s.notify(ctx, msg, sub.eventType, sub.recipient)
+	}))
+}
 
+// notify возвращает ошибку только пока уведомление никуда не ушло: после
+// Publish повтор продублировал бы его в WebSocket, поэтому сбои внешних каналов
+// лишь логируются — у отложенных уведомлений свой повтор в dispatcher.
+func (s *service) notify(ctx context.Context, msg amqp.Delivery, eventType string, recipient recipientFunc) error {
+	// Схему события задаёт ce-type; у сообщений, опубликованных до перехода
+	// на CloudEvents, — свойство type
+	schema := msg.Type
+	ce, ok, err := events.ParseCloudEvent(msg.Headers)
 	if err != nil {
-		s.logger.Error("failed to consume messages", zap.Error(err))
-		return err
+		return broker.Permanent(err)
+	}
+	if ok {
+		schema = ce.Type
+		s.logger.Debug("cloud event",
+			zap.String("id", ce.ID),
+			zap.String("source", ce.Source),
+			zap.String("type", ce.Type),
+			zap.String("subject", ce.Subject),
+		)
 	}
 
-	for msg := range msgs {
-		s.logger.Info("received cancelled order", zap.String("message", string(msg.Body)))
+	orderEvent, err := events.Decode(msg.RoutingKey, schema, msg.ContentType, msg.Body)
+	if err != nil {
+		return broker.Permanent(fmt.Errorf("events.Decode: %w", err))
+	}
+	order, err := events.OrderFromProto(orderEvent.GetOrder())
+	if err != nil {
+		return broker.Permanent(fmt.Errorf("events.OrderFromProto: %w", err))
+	}
+	userID := recipient(orderEvent, order)
+	if userID == uuid.Nil {
+		return broker.Permanent(errors.New("order event without recipient"))
+	}
+
+	// Клиентам и внешним каналам событие уходит в JSON схемы, в каком бы
+	// формате оно ни пришло из брокера
+	data, err := events.MarshalJSON(orderEvent)
+	if err != nil {
+		return broker.Permanent(fmt.Errorf("events.MarshalJSON: %w", err))
 	}
-	return nil
-}
 
-func (s *service) HandleOrderCompletedMessages() error {
-	msgs, err := s.broker.GetChannel().Consume(
-		"queue_order_completed",
-		"",
-		false,
-		false,
-		false,
-		false,
-		nil,
+	event, err := s.hub.Publish(ctx, userID, eventType, data)
+	if err != nil {
+		return fmt.Errorf("hub.Publish: %w", err)
+	}
+	s.logger.Info("notification published",
+		zap.String("userID", userID.String()),
+		zap.Uint64("eventID", event.ID),
 	)
 
+	if liveOnlyEvents[eventType] {
+		return nil
+	}
+	if err := s.dispatcher.Dispatch(ctx, userID, eventType, order, data); err != nil {
+		s.logger.Error("failed to dispatch notification", zap.Error(err))
+	}
+	return nil
+}
+
+func (s *service) GetPreferences(ctx context.Context, userID uuid.UUID) (*infra.Preferences, error) {
+	s.logger.Info("Getting preferences", zap.String("userID", userID.String()))
+	prefs, err := s.db.GetPreferences(ctx, userID)
 	if err != nil {
-		s.logger.Error("failed to consume messages", zap.Error(err))
-		return err
+		s.logger.Error("Failed to get preferences", zap.Error(err))
+		return nil, err
 	}
 
-	for msg := range msgs {
-		s.logger.Info("received completed order", zap.String("message", string(msg.Body)))
+	return prefs, nil
+}
+
+func (s *service) UpdatePreferences(ctx context.Context, prefs *infra.Preferences) error {
+	s.logger.Info("Updating preferences", zap.String("userID", prefs.UserID.String()))
+
+	if prefs.DigestMode == "" {
+		prefs.DigestMode = infra.DigestOff
+	}
+	if prefs.QuietHours.Timezone == "" {
+		prefs.QuietHours.Timezone = "UTC"
+	}
+	if err := prefs.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPreferences, err)
 	}
+	if prefs.WebhookURL != "" {
+		if err := channels.ValidateWebhookURL(prefs.WebhookURL); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPreferences, err)
+		}
+	}
+	for _, cp := range prefs.Channels {
+		if !channels.Known(cp.Channel) {
+			return fmt.Errorf("%w: unknown channel %q", ErrInvalidPreferences, cp.Channel)
+		}
+	}
+
+	if err := s.db.UpdatePreferences(ctx, prefs); err != nil {
+		s.logger.Error("Failed to update preferences", zap.Error(err))
+		return err
+	}
+
+	s.logger.Info("Preferences updated successfully")
 	return nil
 }
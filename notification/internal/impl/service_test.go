@@ -0,0 +1,37 @@
+package impl
+
+import (
+	"testing"
+
+	"orderq/pkg/events"
+)
+
+// Каждая очередь топологии должна читаться, и каждая подписка — читать
+// очередь, привязанную к своему событию.
+func TestSubscriptionsMatchTopology(t *testing.T) {
+	bound := map[string]string{}
+	for _, q := range events.NotificationConsumer.Queues {
+		for _, b := range q.Bindings {
+			bound[q.Name] = b.RoutingKey
+		}
+	}
+
+	subscribed := map[string]bool{}
+	for _, sub := range subscriptions {
+		if key, ok := bound[sub.queue]; !ok {
+			t.Errorf("%s: queue is not in the topology", sub.queue)
+		} else if key != sub.eventType {
+			t.Errorf("%s: bound to %s, handled as %s", sub.queue, key, sub.eventType)
+		}
+		if subscribed[sub.queue] {
+			t.Errorf("%s: subscribed twice", sub.queue)
+		}
+		subscribed[sub.queue] = true
+	}
+
+	for queue := range bound {
+		if !subscribed[queue] {
+			t.Errorf("%s: nobody reads the queue", queue)
+		}
+	}
+}
@@ -1,94 +1,222 @@
 package broker
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"notification_service/internal/config"
+	"orderq/pkg/events"
 
 	"github.com/streadway/amqp"
 	"go.uber.org/zap"
 )
 
+// State — состояние подключения к RabbitMQ, отдаётся в health check.
+type State string
+
+const (
+	StateConnecting State = "connecting"
+	StateConnected  State = "connected"
+	StateClosed     State = "closed"
+)
+
+var (
+	ErrNotConnected = errors.New("rabbitmq is not connected")
+	ErrClosed       = errors.New("rabbitmq connection is closed")
+)
+
+// RabbitMQ держит подключение к брокеру и восстанавливает его при обрыве:
+// переподключается с экспоненциальной задержкой, заново объявляет топологию,
+// а потребители из Consume и ConsumeDeliveries сами подписываются снова.
 type RabbitMQ struct {
-	logger *zap.Logger
-	conn   *amqp.Connection
-	ch     *amqp.Channel
+	logger        *zap.Logger
+	cfg           *config.RabbitMQ
+	mu            sync.RWMutex
+	conn          *amqp.Connection
+	ch            *amqp.Channel
+	deliveryQueue string
+	state         State
+	ready         chan struct{} // закрывается, когда подключение установлено
+	closed        chan struct{}
+	closeOnce     sync.Once
 }
 
-const OrderEventExchange = "order.events"
-
 func New(log *zap.Logger, cfg *config.RabbitMQ) (*RabbitMQ, error) {
-	conn, err := amqp.Dial(cfg.URL)
+	r := &RabbitMQ{
+		logger: log,
+		cfg:    cfg,
+		state:  StateConnecting,
+		ready:  make(chan struct{}),
+		closed: make(chan struct{}),
+	}
+
+	if err := r.connect(); err != nil {
+		return nil, err
+	}
+	go r.watch()
+
+	log.Info("Connect to RabbitMQ success")
+
+	return r, nil
+}
+
+func (r *RabbitMQ) connect() error {
+	conn, err := amqp.Dial(r.cfg.URL)
 	if err != nil {
-		return nil, fmt.Errorf("amqp.Dial: %w", err)
+		return fmt.Errorf("amqp.Dial: %w", err)
 	}
 
 	ch, err := conn.Channel()
 	if err != nil {
-		return nil, fmt.Errorf("conn.Channel: %w", err)
+		conn.Close()
+		return fmt.Errorf("conn.Channel: %w", err)
+	}
+
+	if err := declareTopology(ch); err != nil {
+		conn.Close()
+		return err
 	}
 
-	if err := ch.ExchangeDeclare(
-		OrderEventExchange,
-		"topic",
-		true,  // durable
-		false, // auto-delete
-		false,
-		false,
-		nil,
-	); err != nil {
-		return nil, fmt.Errorf("ch.ExchangeDeclare: %w", err)
+	deliveryQueue, err := declareDelivery(ch)
+	if err != nil {
+		conn.Close()
+		return err
 	}
 
-	var orderQueues = map[string][]string{
-		"order.created":   {"queue_order_created"},
-		"order.assigned":  {"queue_order_assigned"},
-		"order.accepted":  {"queue_order_accepted"},
-		"order.cancelled": {"queue_order_cancelled"},
-		"order.completed": {"queue_order_completed"},
-		"order.updated":   {"queue_order_updated"},
+	r.mu.Lock()
+	r.conn = conn
+	r.ch = ch
+	r.deliveryQueue = deliveryQueue
+	r.mu.Unlock()
+
+	r.setState(StateConnected)
+	return nil
+}
+
+// declareTopology объявляет очереди, которые читает сервис, и dead-letter exchange.
+func declareTopology(ch *amqp.Channel) error {
+	if err := events.NotificationConsumer.Declare(ch); err != nil {
+		return err
 	}
 
-	for routingKey, queues := range orderQueues {
-		for _, queueName := range queues {
-			_, err := ch.QueueDeclare(
-				queueName,
-				true,  // durable
-				false, // auto-delete
-				false,
-				false,
-				nil,
-			)
-			if err != nil {
-				return nil, err
-			}
-
-			// Привязка очереди к exchange и routing key
-			if err := ch.QueueBind(
-				queueName,
-				routingKey,
-				OrderEventExchange,
-				false,
-				nil,
-			); err != nil {
-				return nil, err
-			}
+	return declareDeadLetter(ch)
+}
+
+// watch ждёт обрыва подключения или основного канала и переподключается,
+// пока не вызван Close.
+func (r *RabbitMQ) watch() {
+	for {
+		r.mu.RLock()
+		conn, ch := r.conn, r.ch
+		r.mu.RUnlock()
+
+		var cause *amqp.Error
+		select {
+		case <-r.closed:
+			return
+		case cause = <-conn.NotifyClose(make(chan *amqp.Error, 1)):
+		case cause = <-ch.NotifyClose(make(chan *amqp.Error, 1)):
+			// Канал закрыт сервером, а соединение живо — пересоздаём всё целиком
+			conn.Close()
 		}
+
+		select {
+		case <-r.closed:
+			return
+		default:
+		}
+
+		r.logger.Warn("RabbitMQ connection lost, reconnecting", zap.Any("cause", cause))
+		r.setState(StateConnecting)
+		r.reconnect()
 	}
+}
 
-	log.Info("Connect to RabbitMQ success")
+func (r *RabbitMQ) reconnect() {
+	delay := r.cfg.ReconnectMin
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-r.closed:
+			return
+		case <-time.After(delay):
+		}
 
-	return &RabbitMQ{
-		conn:   conn,
-		ch:     ch,
-		logger: log,
-	}, nil
+		err := r.connect()
+		if err == nil {
+			r.logger.Info("Reconnect to RabbitMQ success", zap.Int("attempt", attempt))
+			return
+		}
+
+		r.logger.Warn("failed to reconnect to RabbitMQ", zap.Int("attempt", attempt), zap.Duration("delay", delay), zap.Error(err))
+		delay = min(delay*2, r.cfg.ReconnectMax)
+	}
 }
 
-func (r *RabbitMQ) Close() error {
-	return r.conn.Close()
+func (r *RabbitMQ) setState(state State) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.state == state || r.state == StateClosed {
+		return
+	}
+	if state == StateConnected {
+		close(r.ready)
+	} else if r.state == StateConnected {
+		r.ready = make(chan struct{})
+	}
+	r.state = state
 }
 
-func (r *RabbitMQ) GetChannel() *amqp.Channel {
-	return r.ch
+// State возвращает текущее состояние подключения.
+func (r *RabbitMQ) State() State {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.state
+}
+
+// wait блокируется до восстановления подключения.
+func (r *RabbitMQ) wait(ctx context.Context) error {
+	for {
+		r.mu.RLock()
+		state, ready := r.state, r.ready
+		r.mu.RUnlock()
+
+		switch state {
+		case StateConnected:
+			return nil
+		case StateClosed:
+			return ErrClosed
+		}
+
+		select {
+		case <-ready:
+		case <-r.closed:
+			return ErrClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// channel возвращает основной канал для публикации.
+func (r *RabbitMQ) channel() (*amqp.Channel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.state != StateConnected {
+		return nil, ErrNotConnected
+	}
+	return r.ch, nil
+}
+
+func (r *RabbitMQ) Close() error {
+	r.closeOnce.Do(func() { close(r.closed) })
+	r.setState(StateClosed)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.conn.Close()
 }
@@ -0,0 +1,260 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"orderq/pkg/events"
+
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+)
+
+// DeadLetterExchange принимает битые (Permanent) сообщения и исчерпавшие
+// все повторные попытки.
+const DeadLetterExchange = events.DeadLetterExchange
+
+// Handler обрабатывает одно сообщение. nil — сообщение подтверждается,
+// ошибка — отправляется на повтор, ошибка из Permanent — сразу в dead-letter.
+type Handler func(ctx context.Context, msg amqp.Delivery) error
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent помечает ошибку как неустранимую: повтор не поможет, например
+// если тело сообщения не разбирается.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+func declareDeadLetter(ch *amqp.Channel) error {
+	if err := ch.ExchangeDeclare(
+		DeadLetterExchange,
+		"direct",
+		true,  // durable
+		false, // auto-delete
+		false,
+		false,
+		nil,
+	); err != nil {
+		return fmt.Errorf("ch.ExchangeDeclare: %w", err)
+	}
+	return nil
+}
+
+// declareRetry объявляет для очереди dead-letter очередь и по одной очереди
+// ожидания на каждую задержку из RetryDelays. Очередь ожидания не читается:
+// по истечении TTL сообщение возвращается в исходную очередь через default exchange.
+// Аргументы исходной очереди не меняются, поэтому её объявление в order service
+// остаётся совместимым.
+func (r *RabbitMQ) declareRetry(ch *amqp.Channel, queue string) error {
+	dead := events.DeadLetterQueue(queue)
+	if _, err := ch.QueueDeclare(dead, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("ch.QueueDeclare: %w", err)
+	}
+	if err := ch.QueueBind(dead, queue, DeadLetterExchange, false, nil); err != nil {
+		return fmt.Errorf("ch.QueueBind: %w", err)
+	}
+
+	for i, delay := range r.cfg.Consumer.RetryDelays {
+		_, err := ch.QueueDeclare(
+			retryQueue(queue, i),
+			true,  // durable
+			false, // auto-delete
+			false,
+			false,
+			amqp.Table{
+				"x-message-ttl":             delay.Milliseconds(),
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": queue,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("ch.QueueDeclare: %w", err)
+		}
+	}
+	return nil
+}
+
+func retryQueue(queue string, attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", queue, attempt+1)
+}
+
+// Consume читает очередь с ручным подтверждением, пока не отменён ctx.
+// После обрыва подключения дожидается переподключения и подписывается снова.
+func (r *RabbitMQ) Consume(ctx context.Context, queue string, handler Handler) error {
+	for {
+		if err := r.wait(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		err := r.consume(ctx, queue, handler)
+		if ctx.Err() != nil {
+			return nil
+		}
+		r.logger.Warn("consumer interrupted, waiting for reconnect", zap.String("queue", queue), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(r.cfg.ReconnectMin):
+		}
+	}
+}
+
+// consume подписывается на очередь до закрытия канала. Под каждую очередь
+// открывается свой канал, чтобы prefetch считался отдельно; сообщения
+// разбирают Concurrency обработчиков.
+func (r *RabbitMQ) consume(ctx context.Context, queue string, handler Handler) error {
+	r.mu.RLock()
+	conn := r.conn
+	r.mu.RUnlock()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("conn.Channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err := r.declareRetry(ch, queue); err != nil {
+		return err
+	}
+
+	rp, err := newRepublisher(conn)
+	if err != nil {
+		return err
+	}
+	defer rp.close()
+
+	if err := ch.Qos(r.cfg.Consumer.Prefetch, 0, false); err != nil {
+		return fmt.Errorf("ch.Qos: %w", err)
+	}
+
+	msgs, err := ch.Consume(
+		queue,
+		"",
+		false, // auto-ack
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("ch.Consume: %w", err)
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			ch.Close()
+		case <-stop:
+		}
+	}()
+
+	workers := max(r.cfg.Consumer.Concurrency, 1)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for msg := range msgs {
+				r.handle(ctx, rp, queue, msg, handler)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.New("channel closed")
+}
+
+func (r *RabbitMQ) handle(ctx context.Context, rp *republisher, queue string, msg amqp.Delivery, handler Handler) {
+	err := handler(ctx, msg)
+	if err == nil {
+		r.ack(msg)
+		return
+	}
+
+	attempt := retryCount(msg)
+	var permanent *permanentError
+	switch {
+	case errors.As(err, &permanent):
+		r.logger.Error("message rejected", zap.String("queue", queue), zap.Error(err))
+		err = r.republish(ctx, rp, DeadLetterExchange, queue, msg, attempt, err)
+	case attempt < len(r.cfg.Consumer.RetryDelays):
+		r.logger.Warn("message processing failed, scheduling retry",
+			zap.String("queue", queue),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("delay", r.cfg.Consumer.RetryDelays[attempt]),
+			zap.Error(err),
+		)
+		err = r.republish(ctx, rp, "", retryQueue(queue, attempt), msg, attempt+1, err)
+	default:
+		r.logger.Error("message retries exhausted", zap.String("queue", queue), zap.Int("attempts", attempt), zap.Error(err))
+		err = r.republish(ctx, rp, DeadLetterExchange, queue, msg, attempt, err)
+	}
+
+	if err != nil {
+		// Брокер не подтвердил копию — возвращаем сообщение в очередь как есть
+		r.logger.Error("failed to republish message", zap.String("queue", queue), zap.Error(err))
+		if err := msg.Nack(false, true); err != nil {
+			r.logger.Error("failed to nack message", zap.Error(err))
+		}
+		return
+	}
+	r.ack(msg)
+}
+
+func (r *RabbitMQ) ack(msg amqp.Delivery) {
+	if err := msg.Ack(false); err != nil {
+		r.logger.Error("failed to ack message", zap.Error(err))
+	}
+}
+
+// republish копирует сообщение в exchange с обновлённым счётчиком попыток
+// и текстом последней ошибки и ждёт, пока брокер примет копию.
+func (r *RabbitMQ) republish(ctx context.Context, rp *republisher, exchange, routingKey string, msg amqp.Delivery, attempt int, cause error) error {
+	headers := amqp.Table{}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	headers[events.HeaderRetryCount] = int32(attempt)
+	headers[events.HeaderError] = cause.Error()
+
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.ConfirmTimeout)
+	defer cancel()
+
+	return rp.publish(ctx, exchange, routingKey, amqp.Publishing{
+		Headers:       headers,
+		ContentType:   msg.ContentType,
+		DeliveryMode:  amqp.Persistent,
+		CorrelationId: msg.CorrelationId,
+		MessageId:     msg.MessageId,
+		Timestamp:     msg.Timestamp,
+		Type:          msg.Type,
+		Body:          msg.Body,
+	})
+}
+
+func retryCount(msg amqp.Delivery) int {
+	switch v := msg.Headers[events.HeaderRetryCount].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
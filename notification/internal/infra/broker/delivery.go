@@ -0,0 +1,141 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"notification_service/internal/infra/store"
+
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+)
+
+// NotificationDeliveryExchange — fanout exchange, через который готовые
+// уведомления рассылаются всем репликам сервиса. Каждая реплика слушает его
+// своей эксклюзивной auto-delete очередью, поэтому событие доходит до
+// пользователя, к какой бы реплике он ни был подключён. Durable очереди
+// order.events при этом остаются рабочими: их разбирает одна из реплик.
+const NotificationDeliveryExchange = "notification.delivery"
+
+// declareDelivery объявляет очередь реплики заново при каждом подключении:
+// она эксклюзивная, и сервер удаляет её вместе с оборванным соединением.
+// Уведомления, разосланные, пока реплика была отключена, до неё не дойдут —
+// клиенты получат их из replay при переподключении к другой реплике.
+func declareDelivery(ch *amqp.Channel) (string, error) {
+	if err := ch.ExchangeDeclare(
+		NotificationDeliveryExchange,
+		"fanout",
+		true,  // durable
+		false, // auto-delete
+		false,
+		false,
+		nil,
+	); err != nil {
+		return "", fmt.Errorf("ch.ExchangeDeclare: %w", err)
+	}
+
+	queue, err := ch.QueueDeclare(
+		"",    // имя выдаст сервер
+		false, // durable
+		true,  // auto-delete
+		true,  // exclusive
+		false,
+		nil,
+	)
+	if err != nil {
+		return "", fmt.Errorf("ch.QueueDeclare: %w", err)
+	}
+
+	if err := ch.QueueBind(
+		queue.Name,
+		"",
+		NotificationDeliveryExchange,
+		false,
+		nil,
+	); err != nil {
+		return "", fmt.Errorf("ch.QueueBind: %w", err)
+	}
+
+	return queue.Name, nil
+}
+
+// Broadcast публикует уведомление для всех реплик сервиса.
+func (r *RabbitMQ) Broadcast(event *store.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	ch, err := r.channel()
+	if err != nil {
+		return err
+	}
+
+	return ch.Publish(
+		NotificationDeliveryExchange,
+		"",
+		false,
+		false,
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+}
+
+// ConsumeDeliveries читает уведомления из очереди этой реплики и передаёт их
+// в deliver, пока не отменён ctx; после переподключения подписывается снова.
+func (r *RabbitMQ) ConsumeDeliveries(ctx context.Context, deliver func(event *store.Event)) error {
+	for {
+		if err := r.wait(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		err := r.consumeDeliveries(deliver)
+		if ctx.Err() != nil {
+			return nil
+		}
+		r.logger.Warn("delivery consumer interrupted, waiting for reconnect", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(r.cfg.ReconnectMin):
+		}
+	}
+}
+
+func (r *RabbitMQ) consumeDeliveries(deliver func(event *store.Event)) error {
+	r.mu.RLock()
+	ch, queue := r.ch, r.deliveryQueue
+	r.mu.RUnlock()
+
+	msgs, err := ch.Consume(
+		queue,
+		"",
+		true, // auto-ack: очередь временная, повторная доставка не нужна
+		true, // exclusive
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		return fmt.Errorf("ch.Consume: %w", err)
+	}
+
+	for msg := range msgs {
+		event := &store.Event{}
+		if err := json.Unmarshal(msg.Body, event); err != nil {
+			r.logger.Error("failed to unmarshal delivery", zap.Error(err))
+			continue
+		}
+		deliver(event)
+	}
+	return errors.New("channel closed")
+}
@@ -0,0 +1,118 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/streadway/amqp"
+)
+
+var (
+	// ErrUnroutable — сообщение опубликовано с mandatory, но ни одна очередь его не приняла.
+	ErrUnroutable = errors.New("message is unroutable")
+	// ErrNacked — брокер не смог принять сообщение.
+	ErrNacked = errors.New("message was nacked by broker")
+)
+
+// Размер буферов уведомлений: подтверждение, пришедшее после таймаута,
+// не должно блокировать чтение соединения до следующей публикации.
+const notifyBuffer = 64
+
+// republisher перекладывает сообщения на повтор и в dead-letter через канал в
+// режиме confirm. Исходное сообщение подтверждается только после того, как
+// брокер принял копию. Номер публикации совпадает с DeliveryTag подтверждения,
+// поэтому обработчики очереди публикуют по одному.
+type republisher struct {
+	mu       sync.Mutex
+	ch       *amqp.Channel
+	confirms chan amqp.Confirmation
+	returns  chan amqp.Return
+	seq      uint64
+}
+
+func newRepublisher(conn *amqp.Connection) (*republisher, error) {
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("conn.Channel: %w", err)
+	}
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		return nil, fmt.Errorf("ch.Confirm: %w", err)
+	}
+
+	return &republisher{
+		ch:       ch,
+		confirms: ch.NotifyPublish(make(chan amqp.Confirmation, notifyBuffer)),
+		returns:  ch.NotifyReturn(make(chan amqp.Return, notifyBuffer)),
+	}, nil
+}
+
+// publish отправляет сообщение с mandatory и ждёт подтверждения брокера.
+func (p *republisher) publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.ch.Publish(exchange, routingKey, true, false, msg); err != nil {
+		return fmt.Errorf("ch.Publish: %w", err)
+	}
+	p.seq++
+
+	var returned *amqp.Return
+	for {
+		select {
+		case ret, ok := <-p.returns:
+			if !ok {
+				return ErrNotConnected
+			}
+			if ret.MessageId != msg.MessageId {
+				// Возврат сообщения, подтверждения которого уже не дождались
+				continue
+			}
+			// Брокер присылает basic.return раньше подтверждения того же сообщения
+			returned = &ret
+		case confirm, ok := <-p.confirms:
+			if !ok {
+				return ErrNotConnected
+			}
+			if confirm.DeliveryTag < p.seq {
+				continue
+			}
+			if !confirm.Ack {
+				return ErrNacked
+			}
+			if returned == nil {
+				returned = p.pendingReturn(msg.MessageId)
+			}
+			if returned != nil {
+				return fmt.Errorf("%w: %s", ErrUnroutable, returned.ReplyText)
+			}
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for confirm: %w", ctx.Err())
+		}
+	}
+}
+
+// pendingReturn забирает уже пришедший возврат сообщения: select мог выбрать
+// подтверждение раньше.
+func (p *republisher) pendingReturn(messageID string) *amqp.Return {
+	for {
+		select {
+		case ret, ok := <-p.returns:
+			if !ok {
+				return nil
+			}
+			if ret.MessageId == messageID {
+				return &ret
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+func (p *republisher) close() error {
+	return p.ch.Close()
+}
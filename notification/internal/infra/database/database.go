@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"notification_service/internal/config"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+type PostgresDB struct {
+	Logger *zap.Logger
+	Db     *pgxpool.Pool
+}
+
+func New(logger *zap.Logger, cfg *config.Postgres) (*PostgresDB, error) {
+	dsn := fmt.Sprintf(
+		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		cfg.Username,
+		cfg.Password,
+		cfg.Host,
+		cfg.Port,
+		cfg.Database,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		logger.Error("failed to create pgx pool", zap.Error(err))
+		return nil, err
+	}
+
+	// Проверка соединения
+	if err := pool.Ping(ctx); err != nil {
+		logger.Error("failed to ping pgx pool", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("connected to database", zap.String("host", cfg.Host), zap.String("database", cfg.Database))
+	return &PostgresDB{Logger: logger, Db: pool}, nil
+}
+
+func (p *PostgresDB) Close() error {
+	p.Db.Close()
+	return nil
+}
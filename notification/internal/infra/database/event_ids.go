@@ -0,0 +1,28 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// NextEventID выдаёт следующий ID события пользователя. Строка счётчика
+// блокируется до конца запроса, поэтому реплики не получат одинаковый ID.
+func (p *PostgresDB) NextEventID(ctx context.Context, userID uuid.UUID) (uint64, error) {
+	var id int64
+	err := p.Db.QueryRow(ctx, `
+	INSERT INTO notification_event_ids (user_id, last_id)
+	VALUES ($1, 1)
+	ON CONFLICT (user_id) DO UPDATE
+	SET last_id = notification_event_ids.last_id + 1
+	RETURNING last_id
+	`, userID).Scan(&id)
+	if err != nil {
+		p.Logger.Error("failed to get next event id", zap.Error(err))
+		return 0, fmt.Errorf("failed to get next event id: %w", err)
+	}
+
+	return uint64(id), nil
+}
@@ -0,0 +1,271 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"notification_service/internal/infra"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// GetPreferences возвращает настройки пользователя. Если пользователь их ещё
+// не сохранял, возвращаются значения по умолчанию.
+func (p *PostgresDB) GetPreferences(ctx context.Context, userID uuid.UUID) (*infra.Preferences, error) {
+	query := `
+	SELECT
+		email,
+		phone,
+		webhook_url,
+		quiet_hours_enabled,
+		quiet_hours_start,
+		quiet_hours_end,
+		timezone,
+		digest_mode,
+		updated_at
+	FROM notification_preferences
+	WHERE user_id = $1
+	`
+	prefs := &infra.Preferences{UserID: userID}
+	err := p.Db.QueryRow(ctx, query, userID).Scan(
+		&prefs.Email,
+		&prefs.Phone,
+		&prefs.WebhookURL,
+		&prefs.QuietHours.Enabled,
+		&prefs.QuietHours.Start,
+		&prefs.QuietHours.End,
+		&prefs.QuietHours.Timezone,
+		&prefs.DigestMode,
+		&prefs.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return defaultPreferences(userID), nil
+	}
+	if err != nil {
+		p.Logger.Error("failed to get preferences", zap.Error(err))
+		return nil, fmt.Errorf("failed to get preferences: %w", err)
+	}
+
+	rows, err := p.Db.Query(ctx, `
+	SELECT event_type, channel, enabled
+	FROM notification_channel_preferences
+	WHERE user_id = $1
+	ORDER BY event_type, channel
+	`, userID)
+	if err != nil {
+		p.Logger.Error("failed to get channel preferences", zap.Error(err))
+		return nil, fmt.Errorf("failed to get channel preferences: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cp infra.ChannelPreference
+		if err := rows.Scan(&cp.EventType, &cp.Channel, &cp.Enabled); err != nil {
+			p.Logger.Error("failed to scan channel preference", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan channel preference: %w", err)
+		}
+		prefs.Channels = append(prefs.Channels, cp)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.Logger.Error("failed to iterate over channel preferences", zap.Error(err))
+		return nil, fmt.Errorf("failed to iterate over channel preferences: %w", err)
+	}
+
+	return prefs, nil
+}
+
+// UpdatePreferences целиком заменяет настройки пользователя.
+func (p *PostgresDB) UpdatePreferences(ctx context.Context, prefs *infra.Preferences) error {
+	tx, err := p.Db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+	INSERT INTO notification_preferences (
+		user_id,
+		email,
+		phone,
+		webhook_url,
+		quiet_hours_enabled,
+		quiet_hours_start,
+		quiet_hours_end,
+		timezone,
+		digest_mode,
+		updated_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+	ON CONFLICT (user_id) DO UPDATE SET
+		email = EXCLUDED.email,
+		phone = EXCLUDED.phone,
+		webhook_url = EXCLUDED.webhook_url,
+		quiet_hours_enabled = EXCLUDED.quiet_hours_enabled,
+		quiet_hours_start = EXCLUDED.quiet_hours_start,
+		quiet_hours_end = EXCLUDED.quiet_hours_end,
+		timezone = EXCLUDED.timezone,
+		digest_mode = EXCLUDED.digest_mode,
+		updated_at = NOW()
+	RETURNING updated_at
+	`
+	if err := tx.QueryRow(
+		ctx,
+		query,
+		prefs.UserID,
+		prefs.Email,
+		prefs.Phone,
+		prefs.WebhookURL,
+		prefs.QuietHours.Enabled,
+		prefs.QuietHours.Start,
+		prefs.QuietHours.End,
+		prefs.QuietHours.Timezone,
+		prefs.DigestMode,
+	).Scan(&prefs.UpdatedAt); err != nil {
+		p.Logger.Error("failed to update preferences", zap.Error(err))
+		return fmt.Errorf("failed to update preferences: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM notification_channel_preferences WHERE user_id = $1`, prefs.UserID); err != nil {
+		p.Logger.Error("failed to clear channel preferences", zap.Error(err))
+		return fmt.Errorf("failed to clear channel preferences: %w", err)
+	}
+
+	for _, cp := range prefs.Channels {
+		if _, err := tx.Exec(ctx, `
+		INSERT INTO notification_channel_preferences (user_id, event_type, channel, enabled)
+		VALUES ($1, $2, $3, $4)
+		`, prefs.UserID, cp.EventType, cp.Channel, cp.Enabled); err != nil {
+			p.Logger.Error("failed to insert channel preference", zap.Error(err))
+			return fmt.Errorf("failed to insert channel preference: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (p *PostgresDB) DeferNotification(ctx context.Context, n *infra.DeferredNotification) error {
+	query := `
+	INSERT INTO deferred_notifications (
+		user_id,
+		event_type,
+		channel,
+		subject,
+		body,
+		data,
+		deliver_after
+	) VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id, created_at
+	`
+	if err := p.Db.QueryRow(
+		ctx,
+		query,
+		n.UserID,
+		n.EventType,
+		n.Channel,
+		n.Subject,
+		n.Body,
+		n.Data,
+		n.DeliverAfter,
+	).Scan(&n.ID, &n.CreatedAt); err != nil {
+		p.Logger.Error("failed to defer notification", zap.Error(err))
+		return fmt.Errorf("failed to defer notification: %w", err)
+	}
+	return nil
+}
+
+// ClaimDueNotifications забирает отложенные уведомления, время отправки которых
+// наступило, и откладывает их на lease. Строки удаляются только после отправки
+// (DeleteDeferredNotifications): если реплика упадёт раньше, через lease их
+// заберёт снова любая реплика. SKIP LOCKED позволяет нескольким репликам
+// разбирать очередь параллельно, не забирая одно уведомление дважды.
+func (p *PostgresDB) ClaimDueNotifications(ctx context.Context, limit int, lease time.Duration) ([]*infra.DeferredNotification, error) {
+	query := `
+	UPDATE deferred_notifications
+	SET deliver_after = NOW() + $2::interval
+	WHERE id IN (
+		SELECT id
+		FROM deferred_notifications
+		WHERE deliver_after <= NOW()
+		ORDER BY created_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING id, user_id, event_type, channel, subject, body, data, deliver_after, created_at
+	`
+	rows, err := p.Db.Query(ctx, query, limit, lease)
+	if err != nil {
+		p.Logger.Error("failed to claim due notifications", zap.Error(err))
+		return nil, fmt.Errorf("failed to claim due notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []*infra.DeferredNotification{}
+	for rows.Next() {
+		var n infra.DeferredNotification
+		if err := rows.Scan(
+			&n.ID,
+			&n.UserID,
+			&n.EventType,
+			&n.Channel,
+			&n.Subject,
+			&n.Body,
+			&n.Data,
+			&n.DeliverAfter,
+			&n.CreatedAt,
+		); err != nil {
+			p.Logger.Error("failed to scan deferred notification", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan deferred notification: %w", err)
+		}
+		notifications = append(notifications, &n)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.Logger.Error("failed to iterate over deferred notifications", zap.Error(err))
+		return nil, fmt.Errorf("failed to iterate over deferred notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// DeleteDeferredNotifications удаляет отправленные отложенные уведомления.
+func (p *PostgresDB) DeleteDeferredNotifications(ctx context.Context, ids []int64) error {
+	_, err := p.Db.Exec(ctx, `DELETE FROM deferred_notifications WHERE id = ANY($1)`, ids)
+	if err != nil {
+		p.Logger.Error("failed to delete deferred notifications", zap.Error(err))
+		return fmt.Errorf("failed to delete deferred notifications: %w", err)
+	}
+	return nil
+}
+
+// RescheduleDeferredNotifications переносит отправку уведомлений на deliverAfter.
+func (p *PostgresDB) RescheduleDeferredNotifications(ctx context.Context, ids []int64, deliverAfter time.Time) error {
+	_, err := p.Db.Exec(ctx, `
+	UPDATE deferred_notifications
+	SET deliver_after = $2
+	WHERE id = ANY($1)
+	`, ids, deliverAfter)
+	if err != nil {
+		p.Logger.Error("failed to reschedule deferred notifications", zap.Error(err))
+		return fmt.Errorf("failed to reschedule deferred notifications: %w", err)
+	}
+	return nil
+}
+
+func defaultPreferences(userID uuid.UUID) *infra.Preferences {
+	return &infra.Preferences{
+		UserID: userID,
+		QuietHours: infra.QuietHours{
+			Start:    "22:00",
+			End:      "07:00",
+			Timezone: "UTC",
+		},
+		DigestMode: infra.DigestOff,
+	}
+}
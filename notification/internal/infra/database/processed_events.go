@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ClaimEvent занимает событие eventID для consumer. false — событие уже
+// обработано или его обрабатывает другой обработчик, занявший его меньше lease назад.
+func (p *PostgresDB) ClaimEvent(ctx context.Context, consumer, eventID string, lease time.Duration) (bool, error) {
+	tag, err := p.Db.Exec(ctx, `
+	INSERT INTO processed_events (consumer, event_id, done)
+	VALUES ($1, $2, FALSE)
+	ON CONFLICT (consumer, event_id) DO UPDATE
+	SET processed_at = NOW()
+	WHERE NOT processed_events.done AND processed_events.processed_at < NOW() - $3::interval
+	`, consumer, eventID, lease)
+	if err != nil {
+		p.Logger.Error("failed to claim event", zap.Error(err))
+		return false, fmt.Errorf("failed to claim event: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// CompleteEvent отмечает занятое событие обработанным.
+func (p *PostgresDB) CompleteEvent(ctx context.Context, consumer, eventID string) error {
+	_, err := p.Db.Exec(ctx, `
+	UPDATE processed_events
+	SET done = TRUE, processed_at = NOW()
+	WHERE consumer = $1 AND event_id = $2
+	`, consumer, eventID)
+	if err != nil {
+		p.Logger.Error("failed to complete event", zap.Error(err))
+		return fmt.Errorf("failed to complete event: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseEvent снимает занятость с необработанного события, чтобы его повтор
+// мог быть обработан.
+func (p *PostgresDB) ReleaseEvent(ctx context.Context, consumer, eventID string) error {
+	_, err := p.Db.Exec(ctx, `
+	DELETE FROM processed_events
+	WHERE consumer = $1 AND event_id = $2 AND NOT done
+	`, consumer, eventID)
+	if err != nil {
+		p.Logger.Error("failed to release event", zap.Error(err))
+		return fmt.Errorf("failed to release event: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteProcessedEventsBefore удаляет записи об обработанных событиях старше before.
+func (p *PostgresDB) DeleteProcessedEventsBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := p.Db.Exec(ctx, `DELETE FROM processed_events WHERE processed_at < $1`, before)
+	if err != nil {
+		p.Logger.Error("failed to delete processed events", zap.Error(err))
+		return 0, fmt.Errorf("failed to delete processed events: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
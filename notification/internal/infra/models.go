@@ -0,0 +1,183 @@
+package infra
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DigestOff    = "off"
+	DigestHourly = "hourly"
+	DigestDaily  = "daily"
+)
+
+// digestDailyHour — час (по часовому поясу пользователя), в который отправляется ежедневный дайджест.
+const digestDailyHour = 9
+
+// Preferences — контакты пользователя и его настройки доставки уведомлений.
+type Preferences struct {
+	UserID     uuid.UUID           `json:"user_id"`
+	Email      string              `json:"email"`
+	Phone      string              `json:"phone"`
+	WebhookURL string              `json:"webhook_url"`
+	Channels   []ChannelPreference `json:"channels"`
+	QuietHours QuietHours          `json:"quiet_hours"`
+	DigestMode string              `json:"digest_mode"`
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+// ChannelPreference включает или выключает канал для конкретного типа события.
+type ChannelPreference struct {
+	EventType string `json:"event_type"`
+	Channel   string `json:"channel"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// QuietHours — интервал [Start, End) в часовом поясе Timezone, в который
+// несрочные уведомления откладываются. Start и End в формате "15:04";
+// интервал может переходить через полночь (22:00–07:00).
+type QuietHours struct {
+	Enabled  bool   `json:"enabled"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Timezone string `json:"timezone"`
+}
+
+// DeferredNotification — уведомление, отложенное до конца тихих часов или до отправки дайджеста.
+type DeferredNotification struct {
+	ID           int64           `json:"id"`
+	UserID       uuid.UUID       `json:"user_id"`
+	EventType    string          `json:"event_type"`
+	Channel      string          `json:"channel"`
+	Subject      string          `json:"subject"`
+	Body         string          `json:"body"`
+	Data         json.RawMessage `json:"data"`
+	DeliverAfter time.Time       `json:"deliver_after"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// ChannelsFor возвращает каналы для события eventType: каналы по умолчанию
+// с учётом включений и выключений, заданных пользователем.
+func (p *Preferences) ChannelsFor(eventType string, defaults []string) []string {
+	enabled := make(map[string]bool, len(defaults))
+	order := append([]string(nil), defaults...)
+	for _, ch := range defaults {
+		enabled[ch] = true
+	}
+
+	for _, cp := range p.Channels {
+		if cp.EventType != eventType {
+			continue
+		}
+		if _, known := enabled[cp.Channel]; !known {
+			order = append(order, cp.Channel)
+		}
+		enabled[cp.Channel] = cp.Enabled
+	}
+
+	var result []string
+	for _, ch := range order {
+		if enabled[ch] {
+			result = append(result, ch)
+		}
+	}
+	return result
+}
+
+// Validate проверяет формат тихих часов и режима дайджеста.
+func (p *Preferences) Validate() error {
+	switch p.DigestMode {
+	case "", DigestOff, DigestHourly, DigestDaily:
+	default:
+		return fmt.Errorf("unknown digest mode %q", p.DigestMode)
+	}
+
+	if !p.QuietHours.Enabled {
+		return nil
+	}
+	if _, err := time.LoadLocation(p.QuietHours.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q", p.QuietHours.Timezone)
+	}
+	if _, err := time.Parse("15:04", p.QuietHours.Start); err != nil {
+		return fmt.Errorf("invalid quiet hours start %q", p.QuietHours.Start)
+	}
+	if _, err := time.Parse("15:04", p.QuietHours.End); err != nil {
+		return fmt.Errorf("invalid quiet hours end %q", p.QuietHours.End)
+	}
+	return nil
+}
+
+// DeliverAfter возвращает момент, не раньше которого можно отправить несрочное
+// уведомление: конец тихих часов и/или ближайшую отправку дайджеста. Нулевое
+// время означает, что отправлять можно сразу.
+func (p *Preferences) DeliverAfter(now time.Time) time.Time {
+	var after time.Time
+
+	loc := p.location()
+	local := now.In(loc)
+
+	switch p.DigestMode {
+	case DigestHourly:
+		after = local.Truncate(time.Hour).Add(time.Hour)
+	case DigestDaily:
+		after = time.Date(local.Year(), local.Month(), local.Day(), digestDailyHour, 0, 0, 0, loc)
+		if !after.After(local) {
+			after = after.AddDate(0, 0, 1)
+		}
+	}
+
+	if end, ok := p.QuietHours.endIfActive(local); ok && end.After(after) {
+		after = end
+	}
+	return after
+}
+
+func (p *Preferences) location() *time.Location {
+	if p.QuietHours.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(p.QuietHours.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// endIfActive возвращает конец тихих часов, если local попадает в них.
+func (q QuietHours) endIfActive(local time.Time) (time.Time, bool) {
+	if !q.Enabled {
+		return time.Time{}, false
+	}
+	start, err := time.Parse("15:04", q.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := time.Parse("15:04", q.End)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	at := func(day time.Time, t time.Time) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, local.Location())
+	}
+
+	todayStart, todayEnd := at(local, start), at(local, end)
+	if !todayEnd.After(todayStart) {
+		// Интервал через полночь: либо мы после сегодняшнего начала, либо до сегодняшнего конца
+		if !local.Before(todayStart) {
+			return todayEnd.AddDate(0, 0, 1), true
+		}
+		if local.Before(todayEnd) {
+			return todayEnd, true
+		}
+		return time.Time{}, false
+	}
+
+	if !local.Before(todayStart) && local.Before(todayEnd) {
+		return todayEnd, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"notification_service/internal/config"
+
+	"github.com/google/uuid"
+)
+
+// Event — уведомление, доставляемое клиенту. ID монотонно растёт в рамках
+// одного пользователя и используется клиентом как last_event_id.
+type Event struct {
+	ID     uint64          `json:"id"`
+	UserID uuid.UUID       `json:"user_id"`
+	Type   string          `json:"type"`
+	Time   time.Time       `json:"time"`
+	Data   json.RawMessage `json:"data"`
+}
+
+type stream struct {
+	lastID  uint64 // последний выданный ID
+	dropped uint64 // наибольший ID, вытесненный из events
+	events  []*Event
+}
+
+// EventStore хранит последние события каждого пользователя в памяти в пределах
+// окна retention и не более maxEvents штук на пользователя.
+type EventStore struct {
+	mu        sync.Mutex
+	retention time.Duration
+	maxEvents int
+	streams   map[uuid.UUID]*stream
+}
+
+func New(cfg *config.Replay) *EventStore {
+	return &EventStore{
+		retention: cfg.Retention,
+		maxEvents: cfg.MaxEvents,
+		streams:   make(map[uuid.UUID]*stream),
+	}
+}
+
+// Put сохраняет событие. ID присваивает общая для реплик последовательность
+// (hub.Sequence), а счётчик пользователя подтягивается до него. Повторно полученные события
+// игнорируются; false означает, что событие уже было в хранилище.
+func (s *EventStore) Put(event *Event) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.stream(event.UserID)
+	if event.ID > st.lastID {
+		st.lastID = event.ID
+	}
+	if event.ID <= st.dropped {
+		return false
+	}
+
+	i := sort.Search(len(st.events), func(i int) bool { return st.events[i].ID >= event.ID })
+	if i < len(st.events) && st.events[i].ID == event.ID {
+		return false
+	}
+	st.events = append(st.events, nil)
+	copy(st.events[i+1:], st.events[i:])
+	st.events[i] = event
+	s.trim(st, time.Now().UTC())
+
+	return true
+}
+
+// Since возвращает события пользователя с ID больше lastID. complete равен false,
+// если часть событий после lastID уже вытеснена и клиенту нужно перечитать состояние.
+func (s *EventStore) Since(userID uuid.UUID, lastID uint64) (events []*Event, complete bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.streams[userID]
+	if !ok {
+		return nil, lastID == 0
+	}
+	s.trim(st, time.Now().UTC())
+
+	if lastID > st.lastID || lastID < st.dropped {
+		return nil, false
+	}
+
+	for _, e := range st.events {
+		if e.ID > lastID {
+			events = append(events, e)
+		}
+	}
+	return events, true
+}
+
+// MaxEvents возвращает максимальное число событий, которое может прийти клиенту при replay.
+func (s *EventStore) MaxEvents() int {
+	return s.maxEvents
+}
+
+// LastID возвращает ID последнего события пользователя.
+func (s *EventStore) LastID(userID uuid.UUID) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if st, ok := s.streams[userID]; ok {
+		return st.lastID
+	}
+	return 0
+}
+
+// Cleanup удаляет события старше окна retention у всех пользователей.
+// Счётчик и отметка вытесненных событий остаются: иначе нумерация начнётся
+// заново, и клиент с устаревшим last_event_id получит пустой replay вместо resync.
+func (s *EventStore) Cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	for _, st := range s.streams {
+		s.trim(st, now)
+	}
+}
+
+func (s *EventStore) stream(userID uuid.UUID) *stream {
+	st, ok := s.streams[userID]
+	if !ok {
+		st = &stream{}
+		s.streams[userID] = st
+	}
+	return st
+}
+
+func (s *EventStore) trim(st *stream, now time.Time) {
+	drop := 0
+	for drop < len(st.events) && now.Sub(st.events[drop].Time) > s.retention {
+		drop++
+	}
+	if over := len(st.events) - drop - s.maxEvents; s.maxEvents > 0 && over > 0 {
+		drop += over
+	}
+	if drop > 0 {
+		st.dropped = st.events[drop-1].ID
+		st.events = append([]*Event(nil), st.events[drop:]...)
+	}
+}
@@ -0,0 +1,45 @@
+package store
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"notification_service/internal/config"
+
+	"github.com/google/uuid"
+)
+
+func newEvent(userID uuid.UUID, id uint64) *Event {
+	return &Event{ID: id, UserID: userID, Type: "order.created", Time: time.Now().UTC(), Data: json.RawMessage(`{}`)}
+}
+
+func TestSinceAfterCleanupRequiresResync(t *testing.T) {
+	s := New(&config.Replay{Retention: time.Minute, MaxEvents: 100})
+	userID := uuid.New()
+
+	first := newEvent(userID, 1)
+	s.Put(first)
+	second := newEvent(userID, 2)
+	s.Put(second)
+
+	// Клиент видел первое событие, потом оба устарели и были удалены
+	first.Time = time.Now().UTC().Add(-time.Hour)
+	second.Time = first.Time
+	s.Cleanup()
+
+	if last := s.LastID(userID); last != second.ID {
+		t.Fatalf("expected last id %d to survive cleanup, got %d", second.ID, last)
+	}
+	third := newEvent(userID, 3)
+	s.Put(third)
+
+	if events, complete := s.Since(userID, first.ID); complete {
+		t.Fatalf("expected incomplete replay after lost events, got %d events", len(events))
+	}
+
+	events, complete := s.Since(userID, second.ID)
+	if !complete || len(events) != 1 || events[0].ID != third.ID {
+		t.Fatalf("expected event %d after %d, got %v (complete=%v)", third.ID, second.ID, events, complete)
+	}
+}
@@ -1,7 +1,16 @@
 package interfaces
 
+import (
+	"context"
+
+	"notification_service/internal/infra"
+
+	"github.com/google/uuid"
+)
+
 type Service interface {
-	HandleOrderCreatedMessages() error
-	HandleOrderCancelledMessages() error
-	HandleOrderCompletedMessages() error
+	HealthCheck(ctx context.Context) error
+	HandleOrderMessages(ctx context.Context)
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*infra.Preferences, error)
+	UpdatePreferences(ctx context.Context, prefs *infra.Preferences) error
 }
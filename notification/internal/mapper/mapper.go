@@ -0,0 +1,63 @@
+package mapper
+
+import (
+	"notification_service/internal/infra"
+	pb "notification_service/proto/notification_service"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func ToPbPreferences(prefs *infra.Preferences) *pb.Preferences {
+	channels := make([]*pb.ChannelPreference, len(prefs.Channels))
+	for i, cp := range prefs.Channels {
+		channels[i] = &pb.ChannelPreference{
+			EventType: cp.EventType,
+			Channel:   cp.Channel,
+			Enabled:   cp.Enabled,
+		}
+	}
+
+	pbPrefs := &pb.Preferences{
+		UserId:     prefs.UserID.String(),
+		Email:      prefs.Email,
+		Phone:      prefs.Phone,
+		WebhookUrl: prefs.WebhookURL,
+		Channels:   channels,
+		QuietHours: &pb.QuietHours{
+			Enabled:  prefs.QuietHours.Enabled,
+			Start:    prefs.QuietHours.Start,
+			End:      prefs.QuietHours.End,
+			Timezone: prefs.QuietHours.Timezone,
+		},
+		DigestMode: prefs.DigestMode,
+	}
+	if !prefs.UpdatedAt.IsZero() {
+		pbPrefs.UpdatedAt = timestamppb.New(prefs.UpdatedAt)
+	}
+	return pbPrefs
+}
+
+func ToInfraPreferences(prefs *pb.Preferences) *infra.Preferences {
+	channels := make([]infra.ChannelPreference, len(prefs.GetChannels()))
+	for i, cp := range prefs.GetChannels() {
+		channels[i] = infra.ChannelPreference{
+			EventType: cp.GetEventType(),
+			Channel:   cp.GetChannel(),
+			Enabled:   cp.GetEnabled(),
+		}
+	}
+
+	return &infra.Preferences{
+		Email:      prefs.GetEmail(),
+		Phone:      prefs.GetPhone(),
+		WebhookURL: prefs.GetWebhookUrl(),
+		Channels:   channels,
+		QuietHours: infra.QuietHours{
+			Enabled:  prefs.GetQuietHours().GetEnabled(),
+			Start:    prefs.GetQuietHours().GetStart(),
+			End:      prefs.GetQuietHours().GetEnd(),
+			Timezone: prefs.GetQuietHours().GetTimezone(),
+		},
+		DigestMode: prefs.GetDigestMode(),
+	}
+}
@@ -0,0 +1,22 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// ProcessedEvents — события заказов, обработанные впервые.
+	ProcessedEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "notification",
+		Name:      "events_processed_total",
+		Help:      "Order events processed by the notification service.",
+	}, []string{"queue"})
+
+	// DuplicateEvents — повторно доставленные события, отброшенные без обработки.
+	DuplicateEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "notification",
+		Name:      "events_duplicates_dropped_total",
+		Help:      "Redelivered order events dropped as duplicates.",
+	}, []string{"queue"})
+)
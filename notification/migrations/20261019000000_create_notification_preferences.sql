@@ -0,0 +1,44 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID PRIMARY KEY,
+    email TEXT NOT NULL DEFAULT '',
+    phone TEXT NOT NULL DEFAULT '',
+    webhook_url TEXT NOT NULL DEFAULT '',
+    quiet_hours_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    quiet_hours_start VARCHAR(5) NOT NULL DEFAULT '22:00',
+    quiet_hours_end VARCHAR(5) NOT NULL DEFAULT '07:00',
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    digest_mode VARCHAR(20) NOT NULL DEFAULT 'off',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS notification_channel_preferences (
+    user_id UUID NOT NULL REFERENCES notification_preferences(user_id) ON DELETE CASCADE,
+    event_type VARCHAR(100) NOT NULL,
+    channel VARCHAR(50) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, event_type, channel)
+);
+
+CREATE TABLE IF NOT EXISTS deferred_notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    channel VARCHAR(50) NOT NULL,
+    subject TEXT NOT NULL,
+    body TEXT NOT NULL,
+    data JSONB,
+    deliver_after TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_deferred_notifications_deliver_after ON deferred_notifications(deliver_after);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back
+
+DROP TABLE IF EXISTS deferred_notifications;
+DROP TABLE IF EXISTS notification_channel_preferences;
+DROP TABLE IF EXISTS notification_preferences;
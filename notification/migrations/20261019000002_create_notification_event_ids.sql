@@ -0,0 +1,14 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied
+
+-- Последний ID события каждого пользователя. Общий для всех реплик, чтобы
+-- события, опубликованные разными репликами, не получили одинаковый ID
+CREATE TABLE IF NOT EXISTS notification_event_ids (
+    user_id UUID PRIMARY KEY,
+    last_id BIGINT NOT NULL
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back
+
+DROP TABLE IF EXISTS notification_event_ids;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied
+
+-- Событие сначала занимается (done = false), а обработанным отмечается после
+-- успеха обработчика. Занятое, но не обработанное событие после таймаута
+-- может занять другой обработчик — на случай падения реплики
+ALTER TABLE processed_events
+    ADD COLUMN done BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back
+
+DELETE FROM processed_events WHERE NOT done;
+ALTER TABLE processed_events
+    DROP COLUMN IF EXISTS done;
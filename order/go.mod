@@ -10,8 +10,10 @@ require (
 	github.com/lib/pq v1.10.9
 	github.com/streadway/amqp v1.1.0
 	go.uber.org/zap v1.27.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250218202821-56aae31c358a
 	google.golang.org/grpc v1.72.0
 	google.golang.org/protobuf v1.36.6
+	orderq/pkg/events v0.0.0
 )
 
 require (
@@ -24,5 +26,6 @@ require (
 	golang.org/x/sync v0.11.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
-	google.golang.org/genproto/googleapis/rpc v0.0.0-20250218202821-56aae31c358a // indirect
 )
+
+replace orderq/pkg/events => ../pkg/events
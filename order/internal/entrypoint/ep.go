@@ -1,11 +1,13 @@
 package entrypoint
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"order_service/internal/config"
 	handlers "order_service/internal/handlers"
@@ -16,6 +18,8 @@ import (
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func Run(cfg *config.Config, logger *zap.Logger) error {
@@ -30,14 +34,49 @@ func Run(cfg *config.Config, logger *zap.Logger) error {
 	}
 	defer db.Close()
 
-	broker, err := broker.New(logger, &cfg.RabbitMQ)
+	rabbitMQ, err := broker.New(logger, &cfg.RabbitMQ)
 	if err != nil {
 		logger.Fatal("failed to create broker", zap.Error(err))
 	}
-	defer broker.Close()
+	defer rabbitMQ.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	service := impl.New(logger, db, rabbitMQ, &cfg.Cancellation, &cfg.Matching, &cfg.Dispatch, &cfg.AgentSessions, &cfg.Tracking)
 
 	grpcServer := grpc.NewServer()
-	proto.RegisterOrderServiceServer(grpcServer, handlers.New(impl.New(logger, db, broker)))
+	proto.RegisterOrderServiceServer(grpcServer, handlers.New(service))
+
+	// Фоновые задачи; каждая реплика запускает свои, записи они делят через SKIP LOCKED
+	go runSweeper(ctx, logger, "expiry", cfg.Expiry.Interval, cfg.Expiry.BatchSize, service.ExpireOverdueOrders)
+	go runSweeper(ctx, logger, "agent sessions", cfg.AgentSessions.Interval, cfg.AgentSessions.BatchSize, service.CloseIdleSessions)
+	go runSweeper(ctx, logger, "tracking", cfg.Tracking.Interval, cfg.Tracking.BatchSize, service.DeleteExpiredLocations)
+	// Публикуются только события старше интервала: свежие ещё публикует сам запрос
+	go runSweeper(ctx, logger, "outbox", cfg.Outbox.Interval, cfg.Outbox.BatchSize, func(ctx context.Context, limit int) (int, error) {
+		return service.PublishPendingEvents(ctx, cfg.Outbox.Interval, limit)
+	})
+	if cfg.Matching.AutoSelect {
+		go runSweeper(ctx, logger, "matching", cfg.Matching.Interval, cfg.Matching.BatchSize, service.CloseOverdueMatching)
+	}
+	if cfg.Dispatch.Enabled {
+		// Сначала закрываются просроченные предложения, чтобы их заказы
+		// сразу ушли следующим исполнителям
+		go runSweeper(ctx, logger, "dispatch", cfg.Dispatch.Interval, cfg.Dispatch.BatchSize, service.CloseStaleOffers, service.DispatchOrders)
+	}
+
+	// Стандартный grpc.health.v1: сервис не готов, пока нет подключения к RabbitMQ,
+	// потому что без него события заказов не доходят до потребителей
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	rabbitMQ.OnStateChange(func(state broker.State) {
+		status := healthpb.HealthCheckResponse_NOT_SERVING
+		if state == broker.StateConnected {
+			status = healthpb.HealthCheckResponse_SERVING
+		}
+		healthServer.SetServingStatus("", status)
+		healthServer.SetServingStatus(proto.OrderService_ServiceDesc.ServiceName, status)
+	})
 
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, syscall.SIGINT, syscall.SIGTERM)
@@ -50,8 +89,40 @@ func Run(cfg *config.Config, logger *zap.Logger) error {
 	}()
 
 	<-done
+	cancel()
 	logger.Info("Order service stopped")
+	healthServer.Shutdown()
 	grpcServer.Stop()
 
 	return nil
 }
+
+// sweep обрабатывает до limit записей и возвращает, сколько обработал.
+type sweep func(ctx context.Context, limit int) (int, error)
+
+// runSweeper раз в interval по очереди вызывает sweeps, пока не отменён ctx.
+// Каждый разбирает пачки по batch подряд, пока очередная не окажется неполной.
+func runSweeper(ctx context.Context, logger *zap.Logger, name string, interval time.Duration, batch int, sweeps ...sweep) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, sweep := range sweeps {
+				for {
+					done, err := sweep(ctx, batch)
+					if err != nil {
+						logger.Error("sweeper failed", zap.String("sweeper", name), zap.Error(err))
+						break
+					}
+					if done < batch {
+						break
+					}
+				}
+			}
+		}
+	}
+}
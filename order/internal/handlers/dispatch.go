@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"order_service/internal/impl"
+	"order_service/internal/mapper"
+	pb "order_service/proto/order_service"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *OrderService) GetCurrentOffer(ctx context.Context, req *pb.GetCurrentOfferRequest) (*pb.GetCurrentOfferResponse, error) {
+	agentID, err := uuid.Parse(req.GetAgentId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid agent_id")
+	}
+
+	offer, err := s.service.GetCurrentOffer(ctx, agentID)
+	if err != nil {
+		return nil, offerStatus(err).Err()
+	}
+
+	return &pb.GetCurrentOfferResponse{Offer: mapper.ToPbOffer(offer)}, nil
+}
+
+func (s *OrderService) AcceptOffer(ctx context.Context, req *pb.AcceptOfferRequest) (*pb.AcceptOfferResponse, error) {
+	orderID, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	agentID, err := uuid.Parse(req.GetAgentId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid agent_id")
+	}
+
+	order, err := s.service.AcceptOffer(ctx, orderID, agentID)
+	if err != nil {
+		return nil, offerStatus(err).Err()
+	}
+
+	return &pb.AcceptOfferResponse{Order: mapper.ToPbOrder(order)}, nil
+}
+
+func (s *OrderService) DeclineOffer(ctx context.Context, req *pb.DeclineOfferRequest) (*pb.DeclineOfferResponse, error) {
+	orderID, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	agentID, err := uuid.Parse(req.GetAgentId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid agent_id")
+	}
+
+	if err := s.service.DeclineOffer(ctx, orderID, agentID); err != nil {
+		return nil, offerStatus(err).Err()
+	}
+
+	return &pb.DeclineOfferResponse{}, nil
+}
+
+// offerStatus переводит ошибки предложений заказа в статусы gRPC.
+func offerStatus(err error) *status.Status {
+	switch {
+	case errors.Is(err, impl.ErrOrderNotFound), errors.Is(err, impl.ErrNoOffer):
+		return status.New(codes.NotFound, err.Error())
+	case errors.Is(err, impl.ErrOfferExpired),
+		errors.Is(err, impl.ErrOrderNotMatching),
+		errors.Is(err, impl.ErrAgentBusy):
+		return status.New(codes.FailedPrecondition, err.Error())
+	}
+	return status.Newf(codes.Internal, "order offer failed: %v", err)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"order_service/internal/impl"
+	"order_service/internal/infra"
+	"order_service/internal/mapper"
+	pb "order_service/proto/order_service"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *OrderService) JoinOrderQueue(ctx context.Context, req *pb.JoinOrderQueueRequest) (*pb.JoinOrderQueueResponse, error) {
+	orderID, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	agentID, err := uuid.Parse(req.GetAgentId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid agent_id")
+	}
+
+	c := &infra.Candidate{OrderID: orderID, AgentID: agentID, Comment: req.GetComment()}
+	order, err := s.service.JoinOrderQueue(ctx, c)
+	if err != nil {
+		return nil, matchingStatus(err).Err()
+	}
+
+	return &pb.JoinOrderQueueResponse{Candidate: mapper.ToPbCandidate(c), Order: mapper.ToPbOrder(order)}, nil
+}
+
+func (s *OrderService) LeaveOrderQueue(ctx context.Context, req *pb.LeaveOrderQueueRequest) (*pb.LeaveOrderQueueResponse, error) {
+	orderID, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	agentID, err := uuid.Parse(req.GetAgentId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid agent_id")
+	}
+
+	order, err := s.service.LeaveOrderQueue(ctx, orderID, agentID)
+	if err != nil {
+		return nil, matchingStatus(err).Err()
+	}
+
+	return &pb.LeaveOrderQueueResponse{Order: mapper.ToPbOrder(order)}, nil
+}
+
+func (s *OrderService) ListCandidates(ctx context.Context, req *pb.ListCandidatesRequest) (*pb.ListCandidatesResponse, error) {
+	orderID, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	order, candidates, err := s.service.ListCandidates(ctx, orderID, userID)
+	if err != nil {
+		return nil, matchingStatus(err).Err()
+	}
+
+	resp := &pb.ListCandidatesResponse{Order: mapper.ToPbOrder(order)}
+	for _, c := range candidates {
+		resp.Candidates = append(resp.Candidates, mapper.ToPbCandidate(c))
+	}
+	return resp, nil
+}
+
+func (s *OrderService) SelectAgent(ctx context.Context, req *pb.SelectAgentRequest) (*pb.SelectAgentResponse, error) {
+	orderID, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+	agentID, err := uuid.Parse(req.GetAgentId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid agent_id")
+	}
+
+	order, err := s.service.SelectAgent(ctx, orderID, userID, agentID, req.GetVersion())
+	if err != nil {
+		return nil, matchingStatus(err).Err()
+	}
+
+	return &pb.SelectAgentResponse{Order: mapper.ToPbOrder(order)}, nil
+}
+
+// matchingStatus переводит ошибки очереди исполнителей в статусы gRPC.
+func matchingStatus(err error) *status.Status {
+	var verr *infra.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		return validationStatus("invalid queue request", verr)
+	case errors.Is(err, impl.ErrOrderNotFound), errors.Is(err, impl.ErrNotInQueue):
+		return status.New(codes.NotFound, err.Error())
+	case errors.Is(err, impl.ErrNotOrderOwner):
+		return status.New(codes.PermissionDenied, err.Error())
+	case errors.Is(err, impl.ErrAlreadyInQueue):
+		return status.New(codes.AlreadyExists, err.Error())
+	case errors.Is(err, impl.ErrOrderNotMatching),
+		errors.Is(err, impl.ErrMatchingClosed),
+		errors.Is(err, impl.ErrQueueFull),
+		errors.Is(err, impl.ErrAgentBusy):
+		return status.New(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, impl.ErrVersionConflict):
+		return status.New(codes.Aborted, err.Error())
+	}
+	return status.Newf(codes.Internal, "order queue failed: %v", err)
+}
@@ -2,12 +2,15 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"order_service/internal/impl"
 	"order_service/internal/infra"
 	"order_service/internal/interfaces"
 	"order_service/internal/mapper"
 	pb "order_service/proto/order_service"
 
 	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -23,20 +26,36 @@ func New(service interfaces.Service) *OrderService {
 
 func (s *OrderService) CreateOrder(ctx context.Context, req *pb.CreateOrderRequest) (*pb.CreateOrderResponse, error) {
 	order := &infra.Order{
-		UserID:        uuid.MustParse(req.GetUserId()),
-		OrderAddress:  req.GetOrderAddress(),
-		OrderLocation: req.GetOrderLocation(),
-		OrderDate:     req.GetOrderDate().AsTime(),
-		OrderTimeGap:  req.GetOrderTimeGap().AsDuration(),
-		OrderStatus:   "pending",
+		UserID:         uuid.MustParse(req.GetUserId()),
+		OrderAddress:   req.GetOrderAddress(),
+		OrderLocation:  req.GetOrderLocation(),
+		OrderTimeGap:   req.GetOrderTimeGap().AsDuration(),
+		OrderStatus:    "pending",
+		IdempotencyKey: req.GetIdempotencyKey(),
+		Notes:          req.GetNotes(),
+	}
+	order.Latitude, order.Longitude = mapper.FromPbPoint(req.GetPoint())
+	// Без даты AsTime вернул бы 1970-01-01, а не нулевое время
+	if req.GetOrderDate() != nil {
+		order.OrderDate = req.GetOrderDate().AsTime()
 	}
 
 	err := s.service.CreateOrder(ctx, order)
-	if err != nil {
+	var verr *infra.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		return &pb.CreateOrderResponse{Success: false}, validationStatus("invalid order", verr).Err()
+	case errors.Is(err, impl.ErrInvalidIdempotencyKey):
+		return &pb.CreateOrderResponse{Success: false}, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, impl.ErrActiveOrderExists):
+		return &pb.CreateOrderResponse{Success: false}, status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, impl.ErrIdempotencyKeyReused):
+		return &pb.CreateOrderResponse{Success: false}, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
 		return &pb.CreateOrderResponse{Success: false}, status.Errorf(codes.Internal, "create order failed: %v", err)
 	}
 
-	return &pb.CreateOrderResponse{Success: true}, nil
+	return &pb.CreateOrderResponse{Success: true, Order: mapper.ToPbOrder(order)}, nil
 }
 
 func (s *OrderService) GetUserOrders(ctx context.Context, req *pb.GetUserOrdersRequest) (*pb.GetUserOrdersResponse, error) {
@@ -48,6 +67,18 @@ func (s *OrderService) GetUserOrders(ctx context.Context, req *pb.GetUserOrdersR
 	return &pb.GetUserOrdersResponse{Orders: mapper.ToPbOrders(orders)}, nil
 }
 
+func (s *OrderService) GetCurrentOrder(ctx context.Context, req *pb.GetCurrentOrderRequest) (*pb.GetCurrentOrderResponse, error) {
+	order, err := s.service.GetCurrentOrder(ctx, uuid.MustParse(req.GetUserId()))
+	switch {
+	case errors.Is(err, impl.ErrNoActiveOrder):
+		return &pb.GetCurrentOrderResponse{Order: nil}, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return &pb.GetCurrentOrderResponse{Order: nil}, status.Errorf(codes.Internal, "get current order failed: %v", err)
+	}
+
+	return &pb.GetCurrentOrderResponse{Order: mapper.ToPbOrder(order)}, nil
+}
+
 func (s *OrderService) GetAvailableOrders(ctx context.Context, req *pb.GetAvailableOrdersRequest) (*pb.GetAvailableOrdersResponse, error) {
 	orders, err := s.service.GetAvailableOrders(ctx)
 	if err != nil {
@@ -59,6 +90,9 @@ func (s *OrderService) GetAvailableOrders(ctx context.Context, req *pb.GetAvaila
 
 func (s *OrderService) GetOrderById(ctx context.Context, req *pb.GetOrderByIdRequest) (*pb.GetOrderByIdResponse, error) {
 	order, err := s.service.GetOrderById(ctx, uuid.MustParse(req.GetOrderId()))
+	if errors.Is(err, impl.ErrOrderNotFound) {
+		return &pb.GetOrderByIdResponse{Order: nil}, status.Error(codes.NotFound, err.Error())
+	}
 	if err != nil {
 		return &pb.GetOrderByIdResponse{Order: nil}, status.Errorf(codes.Internal, "get order by id failed: %v", err)
 	}
@@ -66,20 +100,148 @@ func (s *OrderService) GetOrderById(ctx context.Context, req *pb.GetOrderByIdReq
 	return &pb.GetOrderByIdResponse{Order: mapper.ToPbOrder(order)}, nil
 }
 
+func (s *OrderService) UpdateOrder(ctx context.Context, req *pb.UpdateOrderRequest) (*pb.UpdateOrderResponse, error) {
+	orderID, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	patch := &infra.Order{
+		OrderID:       orderID,
+		OrderAddress:  req.GetOrder().GetOrderAddress(),
+		OrderLocation: req.GetOrder().GetOrderLocation(),
+		OrderTimeGap:  req.GetOrder().GetOrderTimeGap().AsDuration(),
+		Notes:         req.GetOrder().GetNotes(),
+		Version:       req.GetVersion(),
+	}
+	if req.GetOrder().GetOrderDate() != nil {
+		patch.OrderDate = req.GetOrder().GetOrderDate().AsTime()
+	}
+
+	order, err := s.service.UpdateOrder(ctx, userID, patch, req.GetUpdateMask().GetPaths())
+	var verr *infra.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		return nil, validationStatus("invalid order", verr).Err()
+	case errors.Is(err, impl.ErrInvalidUpdateMask):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, impl.ErrOrderNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, impl.ErrUpdateForbidden):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, impl.ErrOrderNotEditable):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, impl.ErrVersionConflict):
+		return nil, status.Error(codes.Aborted, err.Error())
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "update order failed: %v", err)
+	}
+
+	return &pb.UpdateOrderResponse{Order: mapper.ToPbOrder(order)}, nil
+}
+
+func (s *OrderService) GetCancellationTerms(ctx context.Context, req *pb.GetCancellationTermsRequest) (*pb.GetCancellationTermsResponse, error) {
+	orderID, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	actorID, err := uuid.Parse(req.GetActorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid actor_id")
+	}
+
+	terms, err := s.service.CancellationTerms(ctx, orderID, req.GetCancelledBy(), actorID)
+	if err != nil {
+		return nil, cancelStatus(err).Err()
+	}
+
+	return &pb.GetCancellationTermsResponse{
+		Terms:   mapper.ToPbCancellationTerms(*terms),
+		Reasons: infra.CancellationReasons,
+	}, nil
+}
+
 func (s *OrderService) CancelOrder(ctx context.Context, req *pb.CancelOrderRequest) (*pb.CancelOrderResponse, error) {
-	err := s.service.CancelOrder(ctx, uuid.MustParse(req.GetOrderId()))
+	orderID, err := uuid.Parse(req.GetOrderId())
 	if err != nil {
-		return &pb.CancelOrderResponse{Success: false}, status.Errorf(codes.Internal, "cancel order failed: %v", err)
+		return &pb.CancelOrderResponse{Success: false}, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	c := &infra.Cancellation{
+		OrderID:     orderID,
+		Reason:      req.GetReason(),
+		Comment:     req.GetComment(),
+		CancelledBy: req.GetCancelledBy(),
+	}
+	// Система отменяет заказ без actor_id
+	if req.GetActorId() != "" {
+		if c.ActorID, err = uuid.Parse(req.GetActorId()); err != nil {
+			return &pb.CancelOrderResponse{Success: false}, status.Error(codes.InvalidArgument, "invalid actor_id")
+		}
+	}
+
+	if err := s.service.CancelOrder(ctx, c, req.GetAcceptedPenalty(), req.GetVersion()); err != nil {
+		return &pb.CancelOrderResponse{Success: false}, cancelStatus(err).Err()
 	}
 
-	return &pb.CancelOrderResponse{Success: true}, nil
+	return &pb.CancelOrderResponse{Success: true, Cancellation: mapper.ToPbCancellation(c)}, nil
+}
+
+// cancelStatus переводит ошибки отмены заказа в статусы gRPC.
+func cancelStatus(err error) *status.Status {
+	var verr *infra.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		return validationStatus("invalid cancellation", verr)
+	case errors.Is(err, impl.ErrOrderNotFound):
+		return status.New(codes.NotFound, err.Error())
+	case errors.Is(err, impl.ErrCancelForbidden):
+		return status.New(codes.PermissionDenied, err.Error())
+	case errors.Is(err, impl.ErrOrderNotCancellable), errors.Is(err, impl.ErrCancellationTermsChanged):
+		return status.New(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, impl.ErrVersionConflict):
+		return status.New(codes.Aborted, err.Error())
+	}
+	return status.Newf(codes.Internal, "cancel order failed: %v", err)
 }
 
 func (s *OrderService) CompleteOrder(ctx context.Context, req *pb.CompleteOrderRequest) (*pb.CompleteOrderResponse, error) {
-	err := s.service.CompleteOrder(ctx, uuid.MustParse(req.GetOrderId()))
+	orderID, err := uuid.Parse(req.GetOrderId())
 	if err != nil {
+		return &pb.CompleteOrderResponse{Success: false}, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+
+	err = s.service.CompleteOrder(ctx, orderID, req.GetVersion())
+	switch {
+	case errors.Is(err, impl.ErrOrderNotFound):
+		return &pb.CompleteOrderResponse{Success: false}, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, impl.ErrOrderNotCompletable):
+		return &pb.CompleteOrderResponse{Success: false}, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, impl.ErrVersionConflict):
+		return &pb.CompleteOrderResponse{Success: false}, status.Error(codes.Aborted, err.Error())
+	case err != nil:
 		return &pb.CompleteOrderResponse{Success: false}, status.Errorf(codes.Internal, "complete order failed: %v", err)
 	}
 
 	return &pb.CompleteOrderResponse{Success: true}, nil
 }
+
+// validationStatus возвращает InvalidArgument с ошибками по полям в errdetails.BadRequest.
+func validationStatus(msg string, verr *infra.ValidationError) *status.Status {
+	br := &errdetails.BadRequest{}
+	for _, v := range verr.Violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	st := status.New(codes.InvalidArgument, msg)
+	if withDetails, err := st.WithDetails(br); err == nil {
+		return withDetails
+	}
+	return status.New(codes.InvalidArgument, verr.Error())
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"order_service/internal/impl"
+	"order_service/internal/infra"
+	"order_service/internal/mapper"
+	pb "order_service/proto/order_service"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *OrderService) StartSearch(ctx context.Context, req *pb.StartSearchRequest) (*pb.StartSearchResponse, error) {
+	agentID, err := uuid.Parse(req.GetAgentId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid agent_id")
+	}
+
+	session := &infra.AgentSession{
+		AgentID:        agentID,
+		RadiusKm:       req.RadiusKm,
+		AvailableFrom:  mapper.FromPbTimestamp(req.GetAvailableFrom()),
+		AvailableUntil: mapper.FromPbTimestamp(req.GetAvailableUntil()),
+	}
+	session.Latitude, session.Longitude = mapper.FromPbPoint(req.GetPoint())
+
+	session, err = s.service.StartSearch(ctx, session)
+	if err != nil {
+		return nil, sessionStatus(err).Err()
+	}
+
+	return &pb.StartSearchResponse{Session: mapper.ToPbAgentSession(session)}, nil
+}
+
+func (s *OrderService) StopSearch(ctx context.Context, req *pb.StopSearchRequest) (*pb.StopSearchResponse, error) {
+	agentID, err := uuid.Parse(req.GetAgentId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid agent_id")
+	}
+
+	session, err := s.service.StopSearch(ctx, agentID)
+	if err != nil {
+		return nil, sessionStatus(err).Err()
+	}
+
+	return &pb.StopSearchResponse{Session: mapper.ToPbAgentSession(session)}, nil
+}
+
+func (s *OrderService) Heartbeat(ctx context.Context, req *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
+	agentID, err := uuid.Parse(req.GetAgentId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid agent_id")
+	}
+
+	latitude, longitude := mapper.FromPbPoint(req.GetPoint())
+	session, err := s.service.Heartbeat(ctx, agentID, latitude, longitude)
+	if err != nil {
+		return nil, sessionStatus(err).Err()
+	}
+
+	return &pb.HeartbeatResponse{Session: mapper.ToPbAgentSession(session)}, nil
+}
+
+func (s *OrderService) ListAgentSessions(ctx context.Context, req *pb.ListAgentSessionsRequest) (*pb.ListAgentSessionsResponse, error) {
+	sessions, err := s.service.ListAgentSessions(ctx, req.GetStatus())
+	if err != nil {
+		return nil, sessionStatus(err).Err()
+	}
+
+	return &pb.ListAgentSessionsResponse{Sessions: mapper.ToPbAgentSessions(sessions)}, nil
+}
+
+// sessionStatus переводит ошибки сеансов исполнителей в статусы gRPC.
+func sessionStatus(err error) *status.Status {
+	var verr *infra.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		return validationStatus("invalid search preferences", verr)
+	case errors.Is(err, impl.ErrInvalidAgentStatus):
+		return status.New(codes.InvalidArgument, err.Error())
+	}
+	return status.Newf(codes.Internal, "agent session failed: %v", err)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"order_service/internal/impl"
+	"order_service/internal/infra"
+	"order_service/internal/mapper"
+	pb "order_service/proto/order_service"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ReportLocation принимает поток точек исполнителя. Неверные точки
+// пропускаются и считаются в rejected, чтобы одна плохая точка не обрывала поток.
+func (s *OrderService) ReportLocation(stream grpc.ClientStreamingServer[pb.ReportLocationRequest, pb.ReportLocationResponse]) error {
+	resp := &pb.ReportLocationResponse{}
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(resp)
+		}
+		if err != nil {
+			return err
+		}
+
+		agentID, err := uuid.Parse(req.GetAgentId())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "invalid agent_id")
+		}
+		if req.GetPoint() == nil {
+			resp.Rejected++
+			continue
+		}
+
+		location := &infra.AgentLocation{
+			AgentID:   agentID,
+			Latitude:  req.GetPoint().GetLatitude(),
+			Longitude: req.GetPoint().GetLongitude(),
+			AccuracyM: req.AccuracyM,
+			SpeedKmh:  req.SpeedKmh,
+		}
+		if req.GetRecordedAt() != nil {
+			location.RecordedAt = req.GetRecordedAt().AsTime()
+		}
+
+		err = s.service.ReportLocation(stream.Context(), location)
+		var verr *infra.ValidationError
+		switch {
+		case err == nil:
+			resp.Accepted++
+		case errors.As(err, &verr):
+			resp.Rejected++
+		default:
+			return status.Errorf(codes.Internal, "failed to report location: %v", err)
+		}
+	}
+}
+
+func (s *OrderService) GetAgentLocation(ctx context.Context, req *pb.GetAgentLocationRequest) (*pb.GetAgentLocationResponse, error) {
+	orderID, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	update, err := s.service.GetAgentLocation(ctx, orderID, userID)
+	if err != nil {
+		return nil, trackingStatus(err).Err()
+	}
+
+	resp := &pb.GetAgentLocationResponse{
+		Location:   mapper.ToPbAgentLocation(update.Location),
+		DistanceKm: update.DistanceKm,
+	}
+	if update.ETA != nil {
+		resp.Eta = durationpb.New(*update.ETA)
+	}
+	return resp, nil
+}
+
+// trackingStatus переводит ошибки слежения за исполнителем в статусы gRPC.
+func trackingStatus(err error) *status.Status {
+	switch {
+	case errors.Is(err, impl.ErrOrderNotFound), errors.Is(err, impl.ErrNoLocation):
+		return status.New(codes.NotFound, err.Error())
+	case errors.Is(err, impl.ErrNotOrderOwner):
+		return status.New(codes.PermissionDenied, err.Error())
+	case errors.Is(err, impl.ErrOrderNotInProgress):
+		return status.New(codes.FailedPrecondition, err.Error())
+	}
+	return status.Newf(codes.Internal, "failed to get agent location: %v", err)
+}
@@ -0,0 +1,143 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"order_service/internal/infra"
+	"order_service/internal/infra/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// CancellationTerms возвращает последствия отмены заказа для того, кто её
+// запрашивает, — их показывают пользователю до подтверждения.
+func (s *service) CancellationTerms(ctx context.Context, orderID uuid.UUID, cancelledBy string, actorID uuid.UUID) (*infra.CancellationTerms, error) {
+	order, err := s.cancellableOrder(ctx, orderID, cancelledBy, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := s.terms(order, cancelledBy)
+	return &terms, nil
+}
+
+// CancelOrder отменяет заказ по политике отмены. Если acceptedPenalty не пуст,
+// это последствия, которые видел пользователь: при расхождении с текущими
+// заказ не отменяется и возвращается ErrCancellationTermsChanged. Если version
+// не 0, это версия заказа, которую видел пользователь: при расхождении
+// возвращается ErrVersionConflict.
+func (s *service) CancelOrder(ctx context.Context, c *infra.Cancellation, acceptedPenalty string, version int64) error {
+	s.logger.Info("Cancelling order",
+		zap.String("orderID", c.OrderID.String()),
+		zap.String("reason", c.Reason),
+		zap.String("cancelledBy", c.CancelledBy),
+	)
+
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	order, err := s.cancellableOrder(ctx, c.OrderID, c.CancelledBy, c.ActorID)
+	if err != nil {
+		return err
+	}
+	if version != 0 && order.Version != version {
+		return ErrVersionConflict
+	}
+
+	c.CancellationTerms = s.terms(order, c.CancelledBy)
+	if acceptedPenalty != "" && acceptedPenalty != c.Penalty {
+		return ErrCancellationTermsChanged
+	}
+
+	var events []*infra.OrderEvent
+	order, err = s.db.CancelOrder(ctx, c, order.Version, func(order *infra.Order, released []uuid.UUID) ([]*infra.OrderEvent, error) {
+		event, err := s.broker.OrderCancelledEvent(ctx, order, c)
+		if err != nil {
+			return nil, err
+		}
+		// Исполнители из очереди узнают, что заказа больше нет
+		releasedEvents, err := s.releasedEvents(ctx, order, released)
+		if err != nil {
+			return nil, err
+		}
+		events = append([]*infra.OrderEvent{event}, releasedEvents...)
+		return events, nil
+	})
+	if err != nil {
+		if errors.Is(err, database.ErrVersionConflict) {
+			if version != 0 {
+				return ErrVersionConflict
+			}
+			// Заказ успели изменить: последствия отмены могли стать другими
+			return ErrCancellationTermsChanged
+		}
+		s.logger.Error("Failed to cancel order", zap.Error(err))
+		return err
+	}
+	s.publishEvents(ctx, events)
+
+	s.logger.Info("Order cancelled successfully", zap.String("penalty", c.Penalty))
+	return nil
+}
+
+// cancellableOrder возвращает активный заказ, который вправе отменить cancelledBy.
+func (s *service) cancellableOrder(ctx context.Context, orderID uuid.UUID, cancelledBy string, actorID uuid.UUID) (*infra.Order, error) {
+	order, err := s.db.GetOrderById(ctx, orderID)
+	if err != nil {
+		if !errors.Is(err, database.ErrOrderNotFound) {
+			s.logger.Error("Failed to get order by ID", zap.Error(err))
+		}
+		return nil, err
+	}
+
+	switch cancelledBy {
+	case infra.CancelledByClient:
+		if order.UserID != actorID {
+			return nil, ErrCancelForbidden
+		}
+	case infra.CancelledByAgent:
+		if order.AgentID == uuid.Nil || order.AgentID != actorID {
+			return nil, ErrCancelForbidden
+		}
+	}
+
+	if !order.Active() {
+		return nil, ErrOrderNotCancellable
+	}
+	return order, nil
+}
+
+// terms применяет политику отмены к заказу в его текущем статусе.
+func (s *service) terms(order *infra.Order, cancelledBy string) infra.CancellationTerms {
+	free := infra.CancellationTerms{Free: true, Penalty: infra.PenaltyNone}
+	if slices.Contains(s.cancellation.FreeStatuses, order.OrderStatus) {
+		return free
+	}
+
+	var penalty string
+	switch cancelledBy {
+	case infra.CancelledByClient:
+		penalty = s.cancellation.ClientPenalty
+	case infra.CancelledByAgent:
+		penalty = s.cancellation.AgentPenalty
+	default:
+		// Менеджер и система отменяют без последствий для участников
+		return free
+	}
+
+	switch penalty {
+	case infra.PenaltyFee:
+		return infra.CancellationTerms{
+			Penalty:     infra.PenaltyFee,
+			FeeAmount:   s.cancellation.FeeAmount,
+			FeeCurrency: s.cancellation.FeeCurrency,
+		}
+	case infra.PenaltyStrike:
+		return infra.CancellationTerms{Penalty: infra.PenaltyStrike}
+	}
+	return free
+}
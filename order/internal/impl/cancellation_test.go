@@ -0,0 +1,122 @@
+package impl
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"order_service/internal/config"
+	"order_service/internal/infra"
+
+	"github.com/google/uuid"
+)
+
+func TestTerms(t *testing.T) {
+	policy := &config.Cancellation{
+		FreeStatuses:  []string{"pending", "matching"},
+		ClientPenalty: infra.PenaltyFee,
+		AgentPenalty:  infra.PenaltyStrike,
+		FeeAmount:     10000,
+		FeeCurrency:   "RUB",
+	}
+	free := infra.CancellationTerms{Free: true, Penalty: infra.PenaltyNone}
+	fee := infra.CancellationTerms{Penalty: infra.PenaltyFee, FeeAmount: 10000, FeeCurrency: "RUB"}
+	strike := infra.CancellationTerms{Penalty: infra.PenaltyStrike}
+
+	tests := []struct {
+		name        string
+		policy      *config.Cancellation
+		status      string
+		cancelledBy string
+		want        infra.CancellationTerms
+	}{
+		{"client, free status", policy, "pending", infra.CancelledByClient, free},
+		{"agent, free status", policy, "matching", infra.CancelledByAgent, free},
+		{"client after assignment", policy, "signed", infra.CancelledByClient, fee},
+		{"agent after assignment", policy, "signed", infra.CancelledByAgent, strike},
+		{"manager", policy, "signed", infra.CancelledByManager, free},
+		{"system", policy, "signed", infra.CancelledBySystem, free},
+		{
+			"client strike",
+			&config.Cancellation{FreeStatuses: []string{"pending"}, ClientPenalty: infra.PenaltyStrike},
+			"signed", infra.CancelledByClient, strike,
+		},
+		{
+			"agent fee",
+			&config.Cancellation{FreeStatuses: []string{"pending"}, AgentPenalty: infra.PenaltyFee, FeeAmount: 10000, FeeCurrency: "RUB"},
+			"signed", infra.CancelledByAgent, fee,
+		},
+		{
+			"penalty none",
+			&config.Cancellation{FreeStatuses: []string{"pending"}, ClientPenalty: infra.PenaltyNone},
+			"signed", infra.CancelledByClient, free,
+		},
+		{
+			"no free statuses",
+			&config.Cancellation{ClientPenalty: infra.PenaltyFee, FeeAmount: 500, FeeCurrency: "USD"},
+			"pending", infra.CancelledByClient,
+			infra.CancellationTerms{Penalty: infra.PenaltyFee, FeeAmount: 500, FeeCurrency: "USD"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{cancellation: tt.policy}
+			got := s.terms(&infra.Order{OrderStatus: tt.status}, tt.cancelledBy)
+			if got != tt.want {
+				t.Errorf("terms = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCancelOrderReleasesQueue(t *testing.T) {
+	userID, agent1, agent2 := uuid.UUID{1}, uuid.UUID{2}, uuid.UUID{3}
+
+	tests := []struct {
+		name   string
+		status string
+		queue  []uuid.UUID
+		want   []string
+	}{
+		{
+			"queued agents", "matching", []uuid.UUID{agent1, agent2},
+			[]string{
+				"order.cancelled → " + userID.String(),
+				"order.candidate_released → " + agent1.String(),
+				"order.candidate_released → " + agent2.String(),
+			},
+		},
+		{"empty queue", "pending", nil, []string{"order.cancelled → " + userID.String()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &infra.Order{OrderID: uuid.UUID{9}, UserID: userID, OrderStatus: tt.status, Version: 1}
+			store := newFakeStore(order)
+			store.queues[order.OrderID] = tt.queue
+			s := newTestService(store)
+			s.cancellation = &config.Cancellation{FreeStatuses: []string{"pending", "matching"}}
+
+			err := s.CancelOrder(context.Background(), &infra.Cancellation{
+				OrderID:     order.OrderID,
+				Reason:      "changed_plans",
+				CancelledBy: infra.CancelledByClient,
+				ActorID:     userID,
+			}, "", 0)
+			if err != nil {
+				t.Fatalf("cancel: %v", err)
+			}
+
+			if got := sent(store.saved); !slices.Equal(got, tt.want) {
+				t.Errorf("saved events = %v, want %v", got, tt.want)
+			}
+			if len(store.published) != len(tt.want) {
+				t.Errorf("published %d events, want %d", len(store.published), len(tt.want))
+			}
+			if len(store.queues[order.OrderID]) != 0 {
+				t.Errorf("queue wasn't released")
+			}
+		})
+	}
+}
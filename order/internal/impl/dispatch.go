@@ -0,0 +1,140 @@
+package impl
+
+import (
+	"context"
+	"errors"
+
+	"order_service/internal/infra"
+	"order_service/internal/infra/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GetCurrentOffer возвращает заказ, который сейчас предложен исполнителю, или ErrNoOffer.
+func (s *service) GetCurrentOffer(ctx context.Context, agentID uuid.UUID) (*infra.Offer, error) {
+	offer, err := s.db.GetOpenOffer(ctx, agentID)
+	if err != nil {
+		if !errors.Is(err, database.ErrNoOffer) {
+			s.logger.Error("Failed to get open offer", zap.Error(err))
+		}
+		return nil, err
+	}
+	return offer, nil
+}
+
+// AcceptOffer назначает исполнителю предложенный ему заказ и публикует order.assigned.
+// Версию заказа исполнитель не видит, поэтому она не сравнивается: заказ
+// блокируется, и назначение проходит, только пока предложение открыто, а заказ
+// ждёт исполнителя.
+func (s *service) AcceptOffer(ctx context.Context, orderID, agentID uuid.UUID) (*infra.Order, error) {
+	s.logger.Info("Accepting offer",
+		zap.String("orderID", orderID.String()),
+		zap.String("agentID", agentID.String()),
+	)
+
+	var events []*infra.OrderEvent
+	order, err := s.db.AcceptOffer(ctx, orderID, agentID, s.matchEvents(ctx, &events))
+	if err != nil {
+		if !isMatchingError(err) && !isOfferError(err) {
+			s.logger.Error("Failed to accept offer", zap.Error(err))
+		}
+		return nil, err
+	}
+
+	s.publishEvents(ctx, events)
+
+	s.logger.Info("Offer accepted")
+	return order, nil
+}
+
+// DeclineOffer отклоняет предложение, и заказ сразу предлагается следующему исполнителю.
+func (s *service) DeclineOffer(ctx context.Context, orderID, agentID uuid.UUID) error {
+	s.logger.Info("Declining offer",
+		zap.String("orderID", orderID.String()),
+		zap.String("agentID", agentID.String()),
+	)
+
+	if err := s.db.DeclineOffer(ctx, orderID, agentID); err != nil {
+		if !errors.Is(err, database.ErrNoOffer) {
+			s.logger.Error("Failed to decline offer", zap.Error(err))
+		}
+		return err
+	}
+
+	s.dispatchOrder(ctx, orderID)
+	return nil
+}
+
+// CloseStaleOffers закрывает до limit просроченных предложений и предложений
+// заказов, которые уже не ждут исполнителя, и сообщает о них исполнителям.
+func (s *service) CloseStaleOffers(ctx context.Context, limit int) (int, error) {
+	var events []*infra.OrderEvent
+	closed, err := s.db.CloseStaleOffers(ctx, limit, func(offer *infra.Offer) ([]*infra.OrderEvent, error) {
+		event, err := s.broker.OfferExpiredEvent(ctx, offer)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+		return []*infra.OrderEvent{event}, nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to close stale offers", zap.Error(err))
+		return closed, err
+	}
+
+	s.publishEvents(ctx, events)
+	return closed, nil
+}
+
+// DispatchOrders предлагает до limit ожидающих исполнителя заказов лучшим
+// свободным исполнителям. Безопасно вызывать с нескольких реплик.
+func (s *service) DispatchOrders(ctx context.Context, limit int) (int, error) {
+	var events []*infra.OrderEvent
+	offered, err := s.db.DispatchOrders(ctx, nil, limit, s.dispatch, s.offerEvents(ctx, &events))
+	if err != nil {
+		s.logger.Error("Failed to dispatch orders", zap.Error(err))
+		return offered, err
+	}
+
+	s.publishEvents(ctx, events)
+	return offered, nil
+}
+
+// dispatchOrder сразу предлагает заказ следующему исполнителю. Ошибка только
+// логируется: заказ подберёт runDispatch.
+func (s *service) dispatchOrder(ctx context.Context, orderID uuid.UUID) {
+	if !s.dispatch.Enabled {
+		return
+	}
+	var events []*infra.OrderEvent
+	if _, err := s.db.DispatchOrders(ctx, &orderID, 1, s.dispatch, s.offerEvents(ctx, &events)); err != nil {
+		s.logger.Error("Failed to dispatch order", zap.String("orderID", orderID.String()), zap.Error(err))
+		return
+	}
+	s.publishEvents(ctx, events)
+}
+
+// offerEvents строит order.offered для исполнителя, которому предложен заказ,
+// и добавляет его в events.
+func (s *service) offerEvents(ctx context.Context, events *[]*infra.OrderEvent) database.OfferEvents {
+	return func(offer *infra.Offer) ([]*infra.OrderEvent, error) {
+		event, err := s.broker.OrderOfferedEvent(ctx, offer)
+		if err != nil {
+			return nil, err
+		}
+		*events = append(*events, event)
+
+		s.logger.Info("Order offered",
+			zap.String("orderID", offer.OrderID.String()),
+			zap.String("agentID", offer.AgentID.String()),
+			zap.Float64("score", offer.Score),
+		)
+		return []*infra.OrderEvent{event}, nil
+	}
+}
+
+// isOfferError сообщает, что err — ожидаемый отказ по предложению, а не сбой.
+func isOfferError(err error) bool {
+	return errors.Is(err, database.ErrNoOffer) || errors.Is(err, database.ErrOfferExpired)
+}
@@ -0,0 +1,59 @@
+package impl
+
+import (
+	"errors"
+	"order_service/internal/infra/database"
+)
+
+var (
+	// ErrIdempotencyKeyReused — ключ идемпотентности уже использован для заказа с другими параметрами.
+	ErrIdempotencyKeyReused = errors.New("idempotency key was used for a different order")
+	// ErrInvalidIdempotencyKey — ключ идемпотентности слишком длинный.
+	ErrInvalidIdempotencyKey = errors.New("idempotency key must be at most 255 characters")
+	// ErrActiveOrderExists — у клиента уже есть активный заказ, второй создать нельзя.
+	ErrActiveOrderExists = database.ErrActiveOrderExists
+	// ErrNoActiveOrder — у клиента нет активного заказа.
+	ErrNoActiveOrder = database.ErrNoActiveOrder
+	// ErrOrderNotFound — заказа с таким ID нет.
+	ErrOrderNotFound = database.ErrOrderNotFound
+	// ErrOrderNotCancellable — заказ уже завершён или отменён.
+	ErrOrderNotCancellable = errors.New("order is no longer active and can't be cancelled")
+	// ErrCancelForbidden — отменить заказ может только его клиент, назначенный исполнитель или менеджер.
+	ErrCancelForbidden = errors.New("not allowed to cancel this order")
+	// ErrCancellationTermsChanged — последствия отмены отличаются от тех, что видел пользователь.
+	ErrCancellationTermsChanged = errors.New("cancellation terms have changed, please review them again")
+	// ErrVersionConflict — заказ изменили после того, как клиент его прочитал.
+	ErrVersionConflict = database.ErrVersionConflict
+	// ErrUpdateForbidden — менять заказ может только его клиент.
+	ErrUpdateForbidden = errors.New("not allowed to update this order")
+	// ErrInvalidUpdateMask — маска обновления пуста или содержит неизвестное поле.
+	ErrInvalidUpdateMask = errors.New("invalid update mask")
+	// ErrOrderNotCompletable — заказ уже завершён, отменён или просрочен.
+	ErrOrderNotCompletable = errors.New("order is no longer active and can't be completed")
+	// ErrNotOrderOwner — смотреть очередь и выбирать исполнителя может только клиент заказа.
+	ErrNotOrderOwner = errors.New("only the client of the order can do this")
+	// ErrOrderNotMatching — заказ не ждёт исполнителя.
+	ErrOrderNotMatching = database.ErrOrderNotMatching
+	// ErrMatchingClosed — время, пока в очередь на заказ можно встать, истекло.
+	ErrMatchingClosed = database.ErrMatchingClosed
+	// ErrQueueFull — в очереди на заказ уже максимум исполнителей.
+	ErrQueueFull = database.ErrQueueFull
+	// ErrAlreadyInQueue — исполнитель уже стоит в очереди на заказ.
+	ErrAlreadyInQueue = database.ErrAlreadyInQueue
+	// ErrNotInQueue — исполнителя нет в очереди на заказ.
+	ErrNotInQueue = database.ErrNotInQueue
+	// ErrAgentBusy — исполнитель уже выполняет другой заказ.
+	ErrAgentBusy = database.ErrAgentBusy
+	// ErrNoOffer — исполнителю не предлагали этот заказ или предложение уже закрыто.
+	ErrNoOffer = database.ErrNoOffer
+	// ErrOfferExpired — исполнитель не ответил на предложение вовремя.
+	ErrOfferExpired = database.ErrOfferExpired
+	// ErrNoLocation — место исполнителя неизвестно или устарело.
+	ErrNoLocation = database.ErrNoLocation
+	// ErrOrderNotInProgress — место исполнителя видно, только пока заказ в работе.
+	ErrOrderNotInProgress = errors.New("order is not in progress")
+	// ErrInvalidAgentStatus — фильтр по неизвестному состоянию исполнителя.
+	ErrInvalidAgentStatus = errors.New("unknown agent status")
+	// ErrOrderNotEditable — поле нельзя менять в текущем статусе заказа.
+	ErrOrderNotEditable = errors.New("order can't be changed in its current status")
+)
@@ -0,0 +1,42 @@
+package impl
+
+import (
+	"context"
+
+	"order_service/internal/infra"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ExpireOverdueOrders переводит в expired до limit просроченных заказов и
+// публикует по каждому order.expired, а исполнителям из его очереди —
+// order.candidate_released. Безопасно вызывать с нескольких реплик.
+func (s *service) ExpireOverdueOrders(ctx context.Context, limit int) (int, error) {
+	var events []*infra.OrderEvent
+	expired, err := s.db.ExpireOverdueOrders(ctx, limit, func(order *infra.Order, released []uuid.UUID) ([]*infra.OrderEvent, error) {
+		event, err := s.broker.OrderExpiredEvent(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+		releasedEvents, err := s.releasedEvents(ctx, order, released)
+		if err != nil {
+			return nil, err
+		}
+		built := append([]*infra.OrderEvent{event}, releasedEvents...)
+		events = append(events, built...)
+
+		s.logger.Info("Order expired",
+			zap.String("orderID", order.OrderID.String()),
+			zap.Int("released", len(released)),
+		)
+		return built, nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to expire overdue orders", zap.Error(err))
+		return expired, err
+	}
+
+	s.publishEvents(ctx, events)
+	return expired, nil
+}
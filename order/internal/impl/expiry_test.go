@@ -0,0 +1,141 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"order_service/internal/infra"
+
+	"github.com/google/uuid"
+)
+
+func TestExpireOverdueOrdersReleasesQueue(t *testing.T) {
+	userID, agent := uuid.UUID{1}, uuid.UUID{2}
+	overdue := time.Now().Add(-2 * time.Hour)
+
+	tests := []struct {
+		name    string
+		order   *infra.Order
+		queue   []uuid.UUID
+		expired int
+		want    []string
+	}{
+		{
+			"queued agents",
+			&infra.Order{OrderStatus: "matching", OrderDate: overdue, OrderTimeGap: time.Hour},
+			[]uuid.UUID{agent},
+			1,
+			[]string{"order.expired → " + userID.String(), "order.candidate_released → " + agent.String()},
+		},
+		{
+			"empty queue",
+			&infra.Order{OrderStatus: "pending", OrderDate: overdue, OrderTimeGap: time.Hour},
+			nil,
+			1,
+			[]string{"order.expired → " + userID.String()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.order.OrderID, tt.order.UserID = uuid.UUID{9}, userID
+			store := newFakeStore(tt.order)
+			store.queues[tt.order.OrderID] = tt.queue
+
+			expired, err := newTestService(store).ExpireOverdueOrders(context.Background(), 10)
+			if err != nil {
+				t.Fatalf("expire: %v", err)
+			}
+			if expired != tt.expired {
+				t.Errorf("expired = %d, want %d", expired, tt.expired)
+			}
+			if got := sent(store.saved); !slices.Equal(got, tt.want) {
+				t.Errorf("saved events = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpireOverdueOrders(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		order   *infra.Order
+		expired bool
+	}{
+		{"overdue pending", &infra.Order{OrderStatus: "pending", OrderDate: now.Add(-2 * time.Hour), OrderTimeGap: time.Hour}, true},
+		{"overdue matching", &infra.Order{OrderStatus: "matching", OrderDate: now.Add(-2 * time.Hour), OrderTimeGap: time.Hour}, true},
+		{"within time gap", &infra.Order{OrderStatus: "pending", OrderDate: now.Add(-time.Hour), OrderTimeGap: 2 * time.Hour}, false},
+		{"in the future", &infra.Order{OrderStatus: "pending", OrderDate: now.Add(time.Hour), OrderTimeGap: time.Hour}, false},
+		{"signed", &infra.Order{OrderStatus: "signed", OrderDate: now.Add(-2 * time.Hour), OrderTimeGap: time.Hour}, false},
+		{"completed", &infra.Order{OrderStatus: "completed", OrderDate: now.Add(-2 * time.Hour), OrderTimeGap: time.Hour}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.order.OrderID, tt.order.UserID, tt.order.Version = uuid.UUID{9}, uuid.UUID{1}, 1
+			status := tt.order.OrderStatus
+			store := newFakeStore(tt.order)
+
+			expired, err := newTestService(store).ExpireOverdueOrders(context.Background(), 10)
+			if err != nil {
+				t.Fatalf("expire: %v", err)
+			}
+
+			order := store.orders[tt.order.OrderID]
+			if tt.expired {
+				if expired != 1 || order.OrderStatus != "expired" || order.Version != 2 {
+					t.Errorf("expired = %d, order %s v%d; want 1, expired v2", expired, order.OrderStatus, order.Version)
+				}
+				if got := sent(store.saved); !slices.Equal(got, []string{"order.expired → " + order.UserID.String()}) {
+					t.Errorf("saved events = %v", got)
+				}
+				return
+			}
+			if expired != 0 || order.OrderStatus != status || len(store.saved) != 0 {
+				t.Errorf("expired = %d, order %s, %d events; want it untouched", expired, order.OrderStatus, len(store.saved))
+			}
+		})
+	}
+}
+
+func TestExpireOverdueOrdersPublish(t *testing.T) {
+	tests := []struct {
+		name       string
+		publishErr error
+		published  int
+	}{
+		{"broker up", nil, 1},
+		// Событие остаётся в order_events, его опубликует runOutbox
+		{"broker down", errors.New("rabbitmq is not connected"), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeStore(&infra.Order{
+				OrderID:      uuid.UUID{9},
+				UserID:       uuid.UUID{1},
+				OrderStatus:  "pending",
+				OrderDate:    time.Now().Add(-2 * time.Hour),
+				OrderTimeGap: time.Hour,
+			})
+			s := newTestService(store)
+			s.broker = fakeBroker{publishErr: tt.publishErr}
+
+			expired, err := s.ExpireOverdueOrders(context.Background(), 10)
+			if err != nil || expired != 1 {
+				t.Fatalf("expire = %d, %v; want 1 without error", expired, err)
+			}
+			if len(store.saved) != 1 {
+				t.Errorf("saved %d events, want 1", len(store.saved))
+			}
+			if len(store.published) != tt.published {
+				t.Errorf("marked %d events published, want %d", len(store.published), tt.published)
+			}
+		})
+	}
+}
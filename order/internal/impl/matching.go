@@ -0,0 +1,192 @@
+package impl
+
+import (
+	"context"
+	"errors"
+
+	"order_service/internal/infra"
+	"order_service/internal/infra/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// JoinOrderQueue ставит исполнителя в очередь на заказ, который ждёт исполнителя.
+// Как и в AcceptOffer, версия заказа не сравнивается: заказ блокируется, и
+// решает его статус.
+func (s *service) JoinOrderQueue(ctx context.Context, c *infra.Candidate) (*infra.Order, error) {
+	s.logger.Info("Joining order queue",
+		zap.String("orderID", c.OrderID.String()),
+		zap.String("agentID", c.AgentID.String()),
+	)
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	order, err := s.db.JoinOrderQueue(ctx, c, s.matching.Window, s.matching.MaxCandidates)
+	if err != nil {
+		if !isMatchingError(err) {
+			s.logger.Error("Failed to join order queue", zap.Error(err))
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Agent joined order queue", zap.String("orderStatus", order.OrderStatus))
+	return order, nil
+}
+
+// LeaveOrderQueue убирает исполнителя из очереди на заказ.
+func (s *service) LeaveOrderQueue(ctx context.Context, orderID, agentID uuid.UUID) (*infra.Order, error) {
+	s.logger.Info("Leaving order queue",
+		zap.String("orderID", orderID.String()),
+		zap.String("agentID", agentID.String()),
+	)
+
+	order, err := s.db.LeaveOrderQueue(ctx, orderID, agentID)
+	if err != nil {
+		if !isMatchingError(err) {
+			s.logger.Error("Failed to leave order queue", zap.Error(err))
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// ListCandidates возвращает заказ клиента userID и исполнителей в очереди на него.
+func (s *service) ListCandidates(ctx context.Context, orderID, userID uuid.UUID) (*infra.Order, []*infra.Candidate, error) {
+	order, err := s.ownOrder(ctx, orderID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	candidates, err := s.db.ListCandidates(ctx, orderID)
+	if err != nil {
+		s.logger.Error("Failed to list candidates", zap.Error(err))
+		return nil, nil, err
+	}
+
+	return order, candidates, nil
+}
+
+// SelectAgent назначает заказу клиента userID исполнителя agentID из очереди.
+// Если version не 0, это версия заказа, которую видел клиент: при расхождении
+// возвращается ErrVersionConflict. Остальные исполнители из очереди
+// отпускаются и получают order.candidate_released.
+func (s *service) SelectAgent(ctx context.Context, orderID, userID, agentID uuid.UUID, version int64) (*infra.Order, error) {
+	s.logger.Info("Selecting agent",
+		zap.String("orderID", orderID.String()),
+		zap.String("agentID", agentID.String()),
+	)
+
+	if _, err := s.ownOrder(ctx, orderID, userID); err != nil {
+		return nil, err
+	}
+
+	var events []*infra.OrderEvent
+	order, released, err := s.db.SelectAgent(ctx, orderID, agentID, version, s.matchEvents(ctx, &events))
+	if err != nil {
+		if !isMatchingError(err) && !errors.Is(err, database.ErrVersionConflict) {
+			s.logger.Error("Failed to select agent", zap.Error(err))
+		}
+		return nil, err
+	}
+
+	s.publishEvents(ctx, events)
+
+	s.logger.Info("Agent selected", zap.Int("released", len(released)))
+	return order, nil
+}
+
+// CloseOverdueMatching назначает исполнителей до limit заказам, у которых
+// истекло время очереди. Безопасно вызывать с нескольких реплик.
+func (s *service) CloseOverdueMatching(ctx context.Context, limit int) (int, error) {
+	var events []*infra.OrderEvent
+	matchEvents := s.matchEvents(ctx, &events)
+	closed, err := s.db.CloseOverdueMatching(ctx, limit, func(order *infra.Order, released []uuid.UUID) ([]*infra.OrderEvent, error) {
+		s.logger.Info("Matching closed",
+			zap.String("orderID", order.OrderID.String()),
+			zap.String("orderStatus", order.OrderStatus),
+		)
+		return matchEvents(order, released)
+	})
+	if err != nil {
+		s.logger.Error("Failed to close overdue matching", zap.Error(err))
+		return closed, err
+	}
+
+	s.publishEvents(ctx, events)
+	return closed, nil
+}
+
+// matchEvents строит order.assigned, если заказу назначен исполнитель, и
+// order.candidate_released каждому отпущенному и добавляет их в events.
+func (s *service) matchEvents(ctx context.Context, events *[]*infra.OrderEvent) database.MatchEvents {
+	return func(order *infra.Order, released []uuid.UUID) ([]*infra.OrderEvent, error) {
+		var built []*infra.OrderEvent
+		if order.OrderStatus == "signed" {
+			event, err := s.broker.OrderAssignedEvent(ctx, order)
+			if err != nil {
+				return nil, err
+			}
+			built = append(built, event)
+		}
+
+		releasedEvents, err := s.releasedEvents(ctx, order, released)
+		if err != nil {
+			return nil, err
+		}
+		built = append(built, releasedEvents...)
+
+		*events = append(*events, built...)
+		return built, nil
+	}
+}
+
+// releasedEvents строит order.candidate_released каждому исполнителю,
+// которого отпустили из очереди на заказ.
+func (s *service) releasedEvents(ctx context.Context, order *infra.Order, released []uuid.UUID) ([]*infra.OrderEvent, error) {
+	built := make([]*infra.OrderEvent, 0, len(released))
+	for _, agentID := range released {
+		event, err := s.broker.CandidateReleasedEvent(ctx, order, agentID)
+		if err != nil {
+			return nil, err
+		}
+		built = append(built, event)
+	}
+	return built, nil
+}
+
+// ownOrder возвращает заказ, если его клиент — userID.
+func (s *service) ownOrder(ctx context.Context, orderID, userID uuid.UUID) (*infra.Order, error) {
+	order, err := s.db.GetOrderById(ctx, orderID)
+	if err != nil {
+		if !errors.Is(err, database.ErrOrderNotFound) {
+			s.logger.Error("Failed to get order by ID", zap.Error(err))
+		}
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, ErrNotOrderOwner
+	}
+	return order, nil
+}
+
+// isMatchingError сообщает, что err — ожидаемый отказ очереди, а не сбой.
+func isMatchingError(err error) bool {
+	for _, target := range []error{
+		database.ErrOrderNotFound,
+		database.ErrOrderNotMatching,
+		database.ErrMatchingClosed,
+		database.ErrQueueFull,
+		database.ErrAlreadyInQueue,
+		database.ErrNotInQueue,
+		database.ErrAgentBusy,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
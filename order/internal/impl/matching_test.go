@@ -0,0 +1,211 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"order_service/internal/config"
+	"order_service/internal/infra"
+
+	"github.com/google/uuid"
+)
+
+var (
+	queueClient = uuid.UUID{1}
+	queueAgent  = uuid.UUID{2}
+	otherAgent  = uuid.UUID{3}
+	busyAgent   = uuid.UUID{4}
+)
+
+// queueStore — заказ order в статусе status с очередью queue и заказ, который
+// уже выполняет busyAgent.
+func queueStore(status string, queue ...uuid.UUID) *fakeStore {
+	order := &infra.Order{OrderID: uuid.UUID{9}, UserID: queueClient, OrderStatus: status, Version: 3}
+	if status == "matching" {
+		deadline := time.Now().Add(time.Minute)
+		order.MatchingDeadline = &deadline
+	}
+	store := newFakeStore(order, &infra.Order{OrderID: uuid.UUID{8}, OrderStatus: "signed", AgentID: busyAgent})
+	store.queues[order.OrderID] = queue
+	return store
+}
+
+func queueService(store *fakeStore) *service {
+	s := newTestService(store)
+	s.matching = &config.Matching{Window: 5 * time.Minute, MaxCandidates: 2}
+	return s
+}
+
+func TestJoinOrderQueue(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   *fakeStore
+		agentID uuid.UUID
+		comment string
+		err     error
+		status  string
+	}{
+		{"first agent starts matching", queueStore("pending"), queueAgent, "буду через 10 минут", nil, "matching"},
+		{"second agent", queueStore("matching", otherAgent), queueAgent, "", nil, "matching"},
+		{"already queued", queueStore("matching", queueAgent), queueAgent, "", ErrAlreadyInQueue, ""},
+		{"queue full", queueStore("matching", otherAgent, uuid.UUID{5}), queueAgent, "", ErrQueueFull, ""},
+		{"agent busy", queueStore("pending"), busyAgent, "", ErrAgentBusy, ""},
+		{"assigned order", queueStore("signed"), queueAgent, "", ErrOrderNotMatching, ""},
+		{"long comment", queueStore("pending"), queueAgent, strings.Repeat("x", infra.MaxCandidateCommentLength+1), &infra.ValidationError{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &infra.Candidate{OrderID: uuid.UUID{9}, AgentID: tt.agentID, Comment: tt.comment}
+			order, err := queueService(tt.store).JoinOrderQueue(context.Background(), c)
+
+			var verr *infra.ValidationError
+			if errors.As(tt.err, &verr) {
+				if !errors.As(err, &verr) {
+					t.Fatalf("err = %v, want *ValidationError", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if order.OrderStatus != tt.status || order.MatchingDeadline == nil {
+				t.Errorf("order = %s, deadline %v; want %s with a deadline", order.OrderStatus, order.MatchingDeadline, tt.status)
+			}
+			if !slices.Contains(tt.store.queues[c.OrderID], tt.agentID) {
+				t.Errorf("agent isn't in the queue")
+			}
+		})
+	}
+}
+
+func TestJoinClosedQueue(t *testing.T) {
+	store := queueStore("matching", otherAgent)
+	deadline := time.Now().Add(-time.Second)
+	store.orders[uuid.UUID{9}].MatchingDeadline = &deadline
+
+	_, err := queueService(store).JoinOrderQueue(context.Background(), &infra.Candidate{OrderID: uuid.UUID{9}, AgentID: queueAgent})
+	if !errors.Is(err, ErrMatchingClosed) {
+		t.Errorf("err = %v, want %v", err, ErrMatchingClosed)
+	}
+}
+
+func TestLeaveOrderQueue(t *testing.T) {
+	tests := []struct {
+		name   string
+		store  *fakeStore
+		err    error
+		status string
+	}{
+		{"last agent stops matching", queueStore("matching", queueAgent), nil, "pending"},
+		{"others stay", queueStore("matching", queueAgent, otherAgent), nil, "matching"},
+		{"not in queue", queueStore("matching", otherAgent), ErrNotInQueue, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := queueService(tt.store).LeaveOrderQueue(context.Background(), uuid.UUID{9}, queueAgent)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if err == nil && order.OrderStatus != tt.status {
+				t.Errorf("order status = %s, want %s", order.OrderStatus, tt.status)
+			}
+			if slices.Contains(tt.store.queues[uuid.UUID{9}], queueAgent) {
+				t.Errorf("agent is still in the queue")
+			}
+		})
+	}
+}
+
+func TestSelectAgent(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   *fakeStore
+		userID  uuid.UUID
+		agentID uuid.UUID
+		err     error
+		events  []string
+	}{
+		{
+			"others are released", queueStore("matching", otherAgent, queueAgent), queueClient, queueAgent, nil,
+			[]string{"order.assigned → " + queueClient.String(), "order.candidate_released → " + otherAgent.String()},
+		},
+		{"only candidate", queueStore("matching", queueAgent), queueClient, queueAgent, nil, []string{"order.assigned → " + queueClient.String()}},
+		{"not the client", queueStore("matching", queueAgent), uuid.UUID{7}, queueAgent, ErrNotOrderOwner, nil},
+		{"not in queue", queueStore("matching", otherAgent), queueClient, queueAgent, ErrNotInQueue, nil},
+		{"agent busy", queueStore("matching", busyAgent), queueClient, busyAgent, ErrAgentBusy, nil},
+		{"pending order", queueStore("pending"), queueClient, queueAgent, ErrOrderNotMatching, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := queueService(tt.store).SelectAgent(context.Background(), uuid.UUID{9}, tt.userID, tt.agentID, 3)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if got := sent(tt.store.saved); !slices.Equal(got, tt.events) {
+				t.Errorf("saved events = %v, want %v", got, tt.events)
+			}
+			if err != nil {
+				return
+			}
+			if order.OrderStatus != "signed" || order.AgentID != tt.agentID {
+				t.Errorf("order = %s with agent %v, want signed with %v", order.OrderStatus, order.AgentID, tt.agentID)
+			}
+			if len(tt.store.published) != len(tt.events) {
+				t.Errorf("published %d events, want %d", len(tt.store.published), len(tt.events))
+			}
+		})
+	}
+}
+
+func TestCloseOverdueMatching(t *testing.T) {
+	tests := []struct {
+		name   string
+		queue  []uuid.UUID
+		status string
+		agent  uuid.UUID
+		events []string
+	}{
+		{
+			"first free agent is assigned", []uuid.UUID{busyAgent, queueAgent, otherAgent}, "signed", queueAgent,
+			[]string{
+				"order.assigned → " + queueClient.String(),
+				"order.candidate_released → " + busyAgent.String(),
+				"order.candidate_released → " + otherAgent.String(),
+			},
+		},
+		{
+			"everybody busy", []uuid.UUID{busyAgent}, "pending", uuid.Nil,
+			[]string{"order.candidate_released → " + busyAgent.String()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := queueStore("matching", tt.queue...)
+			deadline := time.Now().Add(-time.Second)
+			store.orders[uuid.UUID{9}].MatchingDeadline = &deadline
+
+			closed, err := queueService(store).CloseOverdueMatching(context.Background(), 10)
+			if err != nil || closed != 1 {
+				t.Fatalf("closed = %d, %v; want 1 without error", closed, err)
+			}
+			order := store.orders[uuid.UUID{9}]
+			if order.OrderStatus != tt.status || order.AgentID != tt.agent {
+				t.Errorf("order = %s with agent %v, want %s with %v", order.OrderStatus, order.AgentID, tt.status, tt.agent)
+			}
+			if got := sent(store.saved); !slices.Equal(got, tt.events) {
+				t.Errorf("saved events = %v, want %v", got, tt.events)
+			}
+		})
+	}
+}
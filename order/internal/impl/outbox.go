@@ -0,0 +1,55 @@
+package impl
+
+import (
+	"context"
+	"time"
+
+	"order_service/internal/infra"
+
+	"go.uber.org/zap"
+)
+
+// publishEvents публикует события, которые уже записаны в order_events вместе
+// с изменением заказа, и отмечает их опубликованными. Ошибка только
+// логируется: изменение зафиксировано, а неопубликованные события опубликует
+// runOutbox. Публикация останавливается на первой ошибке, чтобы события
+// заказа не обгоняли друг друга.
+func (s *service) publishEvents(ctx context.Context, events []*infra.OrderEvent) {
+	published := make([]string, 0, len(events))
+	for _, event := range events {
+		if err := s.broker.PublishEvent(ctx, event); err != nil {
+			s.logger.Warn("Failed to publish order event, leaving it to outbox",
+				zap.String("eventID", event.EventID),
+				zap.String("routingKey", event.RoutingKey),
+				zap.Error(err),
+			)
+			break
+		}
+		published = append(published, event.EventID)
+	}
+	if len(published) == 0 {
+		return
+	}
+
+	if err := s.db.MarkEventsPublished(ctx, published); err != nil {
+		s.logger.Error("Failed to mark order events published", zap.Error(err))
+	}
+}
+
+// PublishPendingEvents публикует до limit событий из order_events, которые не
+// удалось опубликовать сразу и которые записаны раньше чем olderThan назад.
+// Безопасно вызывать с нескольких реплик.
+func (s *service) PublishPendingEvents(ctx context.Context, olderThan time.Duration, limit int) (int, error) {
+	published, err := s.db.PublishPendingEvents(ctx, olderThan, limit, func(event *infra.OrderEvent) error {
+		return s.broker.PublishEvent(ctx, event)
+	})
+	if err != nil {
+		s.logger.Error("Failed to publish pending order events", zap.Int("published", published), zap.Error(err))
+		return published, err
+	}
+
+	if published > 0 {
+		s.logger.Info("Pending order events published", zap.Int("published", published))
+	}
+	return published, nil
+}
@@ -2,44 +2,170 @@ package impl
 
 import (
 	"context"
+	"errors"
+	"order_service/internal/config"
 	"order_service/internal/infra"
-	"order_service/internal/infra/broker"
 	"order_service/internal/infra/database"
 	"order_service/internal/interfaces"
+	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// Store — хранилище заказов; его реализует *database.PostgresDB.
+type Store interface {
+	CreateOrder(ctx context.Context, order *infra.Order, events database.OrderEvents) error
+	GetUserOrders(ctx context.Context, userID uuid.UUID) ([]*infra.Order, error)
+	GetCurrentOrder(ctx context.Context, userID uuid.UUID) (*infra.Order, error)
+	GetAvailableOrders(ctx context.Context) ([]*infra.Order, error)
+	GetOrderById(ctx context.Context, orderID uuid.UUID) (*infra.Order, error)
+	GetOrderByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*infra.Order, error)
+	UpdateOrder(ctx context.Context, order *infra.Order, fields []string, events database.OrderEvents) (*infra.Order, error)
+	CompleteOrder(ctx context.Context, orderID uuid.UUID, version int64, events database.OrderEvents) (*infra.Order, error)
+	CancelOrder(ctx context.Context, c *infra.Cancellation, version int64, events database.MatchEvents) (*infra.Order, error)
+	GetCancellation(ctx context.Context, orderID uuid.UUID) (*infra.Cancellation, error)
+	ExpireOverdueOrders(ctx context.Context, limit int, events database.MatchEvents) (int, error)
+
+	JoinOrderQueue(ctx context.Context, c *infra.Candidate, window time.Duration, maxCandidates int) (*infra.Order, error)
+	LeaveOrderQueue(ctx context.Context, orderID, agentID uuid.UUID) (*infra.Order, error)
+	ListCandidates(ctx context.Context, orderID uuid.UUID) ([]*infra.Candidate, error)
+	SelectAgent(ctx context.Context, orderID, agentID uuid.UUID, version int64, events database.MatchEvents) (*infra.Order, []uuid.UUID, error)
+	CloseOverdueMatching(ctx context.Context, limit int, events database.MatchEvents) (int, error)
+
+	GetOpenOffer(ctx context.Context, agentID uuid.UUID) (*infra.Offer, error)
+	AcceptOffer(ctx context.Context, orderID, agentID uuid.UUID, events database.MatchEvents) (*infra.Order, error)
+	DeclineOffer(ctx context.Context, orderID, agentID uuid.UUID) error
+	CloseStaleOffers(ctx context.Context, limit int, events database.OfferEvents) (int, error)
+	DispatchOrders(ctx context.Context, orderID *uuid.UUID, limit int, cfg *config.Dispatch, events database.OfferEvents) (int, error)
+
+	StartAgentSession(ctx context.Context, session *infra.AgentSession) (*infra.AgentSession, error)
+	TouchAgentSession(ctx context.Context, agentID uuid.UUID, latitude, longitude *float64) (*infra.AgentSession, error)
+	StopAgentSession(ctx context.Context, agentID uuid.UUID) (*infra.AgentSession, error)
+	ListAgentSessions(ctx context.Context, status string) ([]*infra.AgentSession, error)
+	CloseIdleSessions(ctx context.Context, timeout time.Duration, limit int) (int, error)
+
+	GetAgentOrder(ctx context.Context, agentID uuid.UUID) (*infra.Order, error)
+	SaveAgentLocation(ctx context.Context, location *infra.AgentLocation, ttl time.Duration) (bool, error)
+	GetAgentLocation(ctx context.Context, agentID uuid.UUID) (*infra.AgentLocation, error)
+	ClaimLocationPush(ctx context.Context, agentID uuid.UUID, interval time.Duration) (bool, error)
+	DeleteExpiredLocations(ctx context.Context, limit int) (int, error)
+
+	MarkEventsPublished(ctx context.Context, eventIDs []string) error
+	PublishPendingEvents(ctx context.Context, olderThan time.Duration, limit int, publish func(*infra.OrderEvent) error) (int, error)
+}
+
+// Broker собирает и публикует события заказов; его реализует *broker.RabbitMQ.
+type Broker interface {
+	OrderCreatedEvent(ctx context.Context, order *infra.Order) (*infra.OrderEvent, error)
+	OrderCancelledEvent(ctx context.Context, order *infra.Order, c *infra.Cancellation) (*infra.OrderEvent, error)
+	OrderCompletedEvent(ctx context.Context, order *infra.Order) (*infra.OrderEvent, error)
+	OrderUpdatedEvent(ctx context.Context, order *infra.Order, changedFields []string) (*infra.OrderEvent, error)
+	OrderExpiredEvent(ctx context.Context, order *infra.Order) (*infra.OrderEvent, error)
+	OrderAssignedEvent(ctx context.Context, order *infra.Order) (*infra.OrderEvent, error)
+	CandidateReleasedEvent(ctx context.Context, order *infra.Order, agentID uuid.UUID) (*infra.OrderEvent, error)
+	OrderOfferedEvent(ctx context.Context, offer *infra.Offer) (*infra.OrderEvent, error)
+	OfferExpiredEvent(ctx context.Context, offer *infra.Offer) (*infra.OrderEvent, error)
+	PublishAgentLocation(ctx context.Context, update *infra.LocationUpdate) error
+	PublishEvent(ctx context.Context, event *infra.OrderEvent) error
+}
+
 type service struct {
-	logger *zap.Logger
-	db     *database.PostgresDB
-	broker *broker.RabbitMQ
+	logger       *zap.Logger
+	db           Store
+	broker       Broker
+	cancellation *config.Cancellation
+	matching     *config.Matching
+	dispatch     *config.Dispatch
+	sessions     *config.AgentSessions
+	tracking     *config.Tracking
 }
 
-func New(logger *zap.Logger, db *database.PostgresDB, broker *broker.RabbitMQ) interfaces.Service {
-	return &service{logger: logger, db: db, broker: broker}
+func New(logger *zap.Logger, db Store, broker Broker, cancellation *config.Cancellation, matching *config.Matching, dispatch *config.Dispatch, sessions *config.AgentSessions, tracking *config.Tracking) interfaces.Service {
+	return &service{logger: logger, db: db, broker: broker, cancellation: cancellation, matching: matching, dispatch: dispatch, sessions: sessions, tracking: tracking}
 }
 
+// maxIdempotencyKeyLength ограничивает длину ключа идемпотентности от клиента.
+const maxIdempotencyKeyLength = 255
+
 func (s *service) CreateOrder(ctx context.Context, order *infra.Order) error {
 	s.logger.Info("Creating order", zap.Any("order", order))
 
-	s.logger.Info("Creating order")
-	if err := s.db.CreateOrder(ctx, order); err != nil {
-		s.logger.Error("Failed to create order", zap.Error(err))
+	if len(order.IdempotencyKey) > maxIdempotencyKeyLength {
+		return ErrInvalidIdempotencyKey
+	}
+
+	// Повтор запроса с тем же ключом возвращает уже созданный заказ
+	if order.IdempotencyKey != "" {
+		existing, err := s.db.GetOrderByIdempotencyKey(ctx, order.UserID, order.IdempotencyKey)
+		if err != nil {
+			s.logger.Error("Failed to get order by idempotency key", zap.Error(err))
+			return err
+		}
+		if existing != nil {
+			return s.replayCreate(order, existing)
+		}
+	}
+
+	if err := order.Validate(time.Now()); err != nil {
 		return err
 	}
 
-	s.logger.Info("Publishing created order")
-	if err := s.broker.PublishOrderCreated(ctx, order); err != nil {
-		s.logger.Error("Failed to publish created order", zap.Error(err))
+	s.logger.Info("Creating order")
+	var events []*infra.OrderEvent
+	err := s.db.CreateOrder(ctx, order, func(order *infra.Order) ([]*infra.OrderEvent, error) {
+		event, err := s.broker.OrderCreatedEvent(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+		events = []*infra.OrderEvent{event}
+		return events, nil
+	})
+	if err != nil {
+		if errors.Is(err, database.ErrIdempotencyKeyExists) {
+			// Параллельный запрос с тем же ключом успел создать заказ первым
+			existing, getErr := s.db.GetOrderByIdempotencyKey(ctx, order.UserID, order.IdempotencyKey)
+			if getErr != nil || existing == nil {
+				s.logger.Error("Failed to get order by idempotency key", zap.Error(getErr))
+				return err
+			}
+			return s.replayCreate(order, existing)
+		}
+		if errors.Is(err, database.ErrActiveOrderExists) {
+			s.logger.Info("User already has an active order", zap.String("userID", order.UserID.String()))
+			return err
+		}
+		s.logger.Error("Failed to create order", zap.Error(err))
 		return err
 	}
 
+	s.publishEvents(ctx, events)
+
+	// Заказ сразу предлагается лучшему исполнителю, не дожидаясь runDispatch
+	s.dispatchOrder(ctx, order.OrderID)
+
 	s.logger.Info("Order created successfully")
 	return nil
 }
 
+// replayCreate подставляет в order ранее созданный заказ с тем же ключом.
+// Если параметры запроса отличаются, ключ использован повторно по ошибке.
+// order.created записан в order_events вместе с заказом и будет опубликован
+// из него, поэтому повтор событие не публикует.
+func (s *service) replayCreate(order, existing *infra.Order) error {
+	if existing.OrderAddress != order.OrderAddress ||
+		existing.OrderLocation != order.OrderLocation ||
+		!existing.OrderDate.Equal(order.OrderDate) ||
+		existing.OrderTimeGap != order.OrderTimeGap ||
+		existing.Notes != order.Notes {
+		return ErrIdempotencyKeyReused
+	}
+
+	s.logger.Info("Order already created with this idempotency key", zap.String("orderID", existing.OrderID.String()))
+	*order = *existing
+	return nil
+}
+
 func (s *service) GetUserOrders(ctx context.Context, userID uuid.UUID) ([]*infra.Order, error) {
 	s.logger.Info("Getting orders", zap.String("userID", userID.String()))
 	orders, err := s.db.GetUserOrders(ctx, userID)
@@ -52,6 +178,20 @@ func (s *service) GetUserOrders(ctx context.Context, userID uuid.UUID) ([]*infra
 	return orders, nil
 }
 
+// GetCurrentOrder возвращает активный заказ клиента или ErrNoActiveOrder.
+func (s *service) GetCurrentOrder(ctx context.Context, userID uuid.UUID) (*infra.Order, error) {
+	s.logger.Info("Getting current order", zap.String("userID", userID.String()))
+	order, err := s.db.GetCurrentOrder(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, database.ErrNoActiveOrder) {
+			s.logger.Error("Failed to get current order", zap.Error(err))
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
 func (s *service) GetAvailableOrders(ctx context.Context) ([]*infra.Order, error) {
 	s.logger.Info("Getting available orders")
 	orders, err := s.db.GetAvailableOrders(ctx)
@@ -73,52 +213,54 @@ func (s *service) GetOrderById(ctx context.Context, orderID uuid.UUID) (*infra.O
 	}
 	s.logger.Info("Order found", zap.Any("order", order))
 
+	if order.OrderStatus == "cancelled" {
+		if order.Cancellation, err = s.db.GetCancellation(ctx, orderID); err != nil {
+			s.logger.Error("Failed to get order cancellation", zap.Error(err))
+			return nil, err
+		}
+	}
+
 	return order, nil
 }
 
-func (s *service) CancelOrder(ctx context.Context, orderID uuid.UUID) error {
-	s.logger.Info("Cancelling order", zap.String("orderID", orderID.String()))
-
-	if err := s.db.CancelOrder(ctx, orderID); err != nil {
-		s.logger.Error("Failed to cancel order", zap.Error(err))
-		return err
-	}
+// CompleteOrder завершает активный заказ. Если version не 0, это версия заказа,
+// которую видел пользователь: при расхождении возвращается ErrVersionConflict.
+func (s *service) CompleteOrder(ctx context.Context, orderID uuid.UUID, version int64) error {
+	s.logger.Info("Completing order", zap.String("orderID", orderID.String()))
 
-	s.logger.Info("Publishing cancelled order")
 	order, err := s.db.GetOrderById(ctx, orderID)
 	if err != nil {
-		s.logger.Error("Failed to get order by ID", zap.Error(err))
+		if !errors.Is(err, database.ErrOrderNotFound) {
+			s.logger.Error("Failed to get order by ID", zap.Error(err))
+		}
 		return err
 	}
-
-	if err := s.broker.PublishOrderCancelled(ctx, order); err != nil {
-		s.logger.Error("Failed to publish cancelled order", zap.Error(err))
-		return err
+	if !order.Active() {
+		return ErrOrderNotCompletable
 	}
-
-	s.logger.Info("Order cancelled successfully")
-	return nil
-}
-
-func (s *service) CompleteOrder(ctx context.Context, orderID uuid.UUID) error {
-	s.logger.Info("Completing order", zap.String("orderID", orderID.String()))
-
-	if err := s.db.CompleteOrder(ctx, orderID); err != nil {
-		s.logger.Error("Failed to complete order", zap.Error(err))
-		return err
+	if version != 0 && order.Version != version {
+		return ErrVersionConflict
 	}
 
-	s.logger.Info("Publishing completed order")
-	order, err := s.db.GetOrderById(ctx, orderID)
+	// Обновление проходит, только если заказ не изменили после чтения:
+	// иначе его могли успеть отменить
+	var events []*infra.OrderEvent
+	order, err = s.db.CompleteOrder(ctx, orderID, order.Version, func(order *infra.Order) ([]*infra.OrderEvent, error) {
+		event, err := s.broker.OrderCompletedEvent(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+		events = []*infra.OrderEvent{event}
+		return events, nil
+	})
 	if err != nil {
-		s.logger.Error("Failed to get order by ID", zap.Error(err))
+		if !errors.Is(err, database.ErrVersionConflict) {
+			s.logger.Error("Failed to complete order", zap.Error(err))
+		}
 		return err
 	}
 
-	if err := s.broker.PublishOrderCompleted(ctx, order); err != nil {
-		s.logger.Error("Failed to publish completed order", zap.Error(err))
-		return err
-	}
+	s.publishEvents(ctx, events)
 
 	s.logger.Info("Order finished successfully")
 	return nil
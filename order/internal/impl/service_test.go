@@ -0,0 +1,418 @@
+package impl
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"order_service/internal/infra"
+	"order_service/internal/infra/database"
+
+	"orderq/pkg/events"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// fakeStore — хранилище заказов в памяти. Методы, которые тестам не нужны,
+// достаются от nil Store и паникуют при вызове.
+type fakeStore struct {
+	Store
+	orders map[uuid.UUID]*infra.Order
+	// queues — исполнители в очереди на заказ
+	queues map[uuid.UUID][]uuid.UUID
+	// saved — события, записанные вместе с изменениями
+	saved     []*infra.OrderEvent
+	published []string
+	// updated — поля, которые записал последний UpdateOrder
+	updated []string
+	// agents — сеансы исполнителей, как они хранятся в agents
+	agents map[uuid.UUID]*infra.AgentSession
+}
+
+func newFakeStore(orders ...*infra.Order) *fakeStore {
+	f := &fakeStore{
+		orders: map[uuid.UUID]*infra.Order{},
+		queues: map[uuid.UUID][]uuid.UUID{},
+		agents: map[uuid.UUID]*infra.AgentSession{},
+	}
+	for _, o := range orders {
+		f.orders[o.OrderID] = o
+	}
+	return f
+}
+
+func (f *fakeStore) GetOrderById(ctx context.Context, orderID uuid.UUID) (*infra.Order, error) {
+	order, ok := f.orders[orderID]
+	if !ok {
+		return nil, database.ErrOrderNotFound
+	}
+	copied := *order
+	return &copied, nil
+}
+
+// release отпускает очередь на заказ, как releaseCandidates.
+func (f *fakeStore) release(orderID uuid.UUID) []uuid.UUID {
+	released := f.queues[orderID]
+	delete(f.queues, orderID)
+	return released
+}
+
+// change применяет изменение к заказу и сохраняет события по нему.
+func (f *fakeStore) change(order *infra.Order, released []uuid.UUID, events database.MatchEvents) error {
+	order.Version++
+	built, err := events(order, released)
+	if err != nil {
+		return err
+	}
+	f.saved = append(f.saved, built...)
+	f.orders[order.OrderID] = order
+	return nil
+}
+
+func (f *fakeStore) CancelOrder(ctx context.Context, c *infra.Cancellation, version int64, events database.MatchEvents) (*infra.Order, error) {
+	order, err := f.GetOrderById(ctx, c.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Version != version {
+		return nil, database.ErrVersionConflict
+	}
+	order.OrderStatus = "cancelled"
+	order.Cancellation = c
+	if err := f.change(order, f.release(order.OrderID), events); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (f *fakeStore) ExpireOverdueOrders(ctx context.Context, limit int, events database.MatchEvents) (int, error) {
+	expired := 0
+	for _, overdue := range f.orders {
+		if expired == limit {
+			break
+		}
+		if overdue.OrderStatus != "pending" && overdue.OrderStatus != "matching" ||
+			overdue.OrderDate.Add(overdue.OrderTimeGap).After(time.Now()) {
+			continue
+		}
+		order := *overdue
+		order.OrderStatus = "expired"
+		if err := f.change(&order, f.release(order.OrderID), events); err != nil {
+			return 0, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+func (f *fakeStore) CompleteOrder(ctx context.Context, orderID uuid.UUID, version int64, events database.OrderEvents) (*infra.Order, error) {
+	order, err := f.GetOrderById(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Version != version {
+		return nil, database.ErrVersionConflict
+	}
+	order.OrderStatus = "completed"
+	if err := f.change(order, nil, func(order *infra.Order, _ []uuid.UUID) ([]*infra.OrderEvent, error) {
+		return events(order)
+	}); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// busy сообщает, что у исполнителя уже есть заказ в работе.
+func (f *fakeStore) busy(agentID uuid.UUID) bool {
+	for _, o := range f.orders {
+		if o.OrderStatus == "signed" && o.AgentID == agentID {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeStore) JoinOrderQueue(ctx context.Context, c *infra.Candidate, window time.Duration, maxCandidates int) (*infra.Order, error) {
+	order, err := f.GetOrderById(ctx, c.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	switch order.OrderStatus {
+	case "pending":
+	case "matching":
+		if order.MatchingDeadline != nil && !time.Now().Before(*order.MatchingDeadline) {
+			return nil, database.ErrMatchingClosed
+		}
+	default:
+		return nil, database.ErrOrderNotMatching
+	}
+	if f.busy(c.AgentID) {
+		return nil, database.ErrAgentBusy
+	}
+	queue := f.queues[c.OrderID]
+	if slices.Contains(queue, c.AgentID) {
+		return nil, database.ErrAlreadyInQueue
+	}
+	if len(queue) >= maxCandidates {
+		return nil, database.ErrQueueFull
+	}
+
+	f.queues[c.OrderID] = append(queue, c.AgentID)
+	c.Status = "waiting"
+	if order.OrderStatus == "pending" {
+		deadline := time.Now().Add(window)
+		order.OrderStatus, order.MatchingDeadline = "matching", &deadline
+		order.Version++
+		f.orders[order.OrderID] = order
+	}
+	return order, nil
+}
+
+func (f *fakeStore) LeaveOrderQueue(ctx context.Context, orderID, agentID uuid.UUID) (*infra.Order, error) {
+	order, err := f.GetOrderById(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	queue := f.queues[orderID]
+	if !slices.Contains(queue, agentID) {
+		return nil, database.ErrNotInQueue
+	}
+	f.queues[orderID] = slices.DeleteFunc(queue, func(id uuid.UUID) bool { return id == agentID })
+	if order.OrderStatus == "matching" && len(f.queues[orderID]) == 0 {
+		order.OrderStatus, order.MatchingDeadline = "pending", nil
+		order.Version++
+		f.orders[order.OrderID] = order
+	}
+	return order, nil
+}
+
+func (f *fakeStore) CloseOverdueMatching(ctx context.Context, limit int, events database.MatchEvents) (int, error) {
+	closed := 0
+	for _, overdue := range f.orders {
+		if closed == limit {
+			break
+		}
+		if overdue.OrderStatus != "matching" || overdue.MatchingDeadline == nil || overdue.MatchingDeadline.After(time.Now()) {
+			continue
+		}
+		order := *overdue
+		order.MatchingDeadline = nil
+
+		// Первый в очереди свободный исполнитель, как waitingCandidates
+		queue := f.queues[order.OrderID]
+		i := slices.IndexFunc(queue, func(id uuid.UUID) bool { return !f.busy(id) })
+		if i >= 0 {
+			order.OrderStatus, order.AgentID = "signed", queue[i]
+			queue = slices.Delete(slices.Clone(queue), i, i+1)
+		} else {
+			order.OrderStatus = "pending"
+		}
+		delete(f.queues, order.OrderID)
+		if err := f.change(&order, queue, events); err != nil {
+			return 0, err
+		}
+		closed++
+	}
+	return closed, nil
+}
+
+func (f *fakeStore) SelectAgent(ctx context.Context, orderID, agentID uuid.UUID, version int64, events database.MatchEvents) (*infra.Order, []uuid.UUID, error) {
+	order, err := f.GetOrderById(ctx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version != 0 && order.Version != version {
+		return nil, nil, database.ErrVersionConflict
+	}
+	if order.OrderStatus != "matching" {
+		return nil, nil, database.ErrOrderNotMatching
+	}
+	queue := f.queues[orderID]
+	if !slices.Contains(queue, agentID) {
+		return nil, nil, database.ErrNotInQueue
+	}
+	if f.busy(agentID) {
+		return nil, nil, database.ErrAgentBusy
+	}
+
+	released := slices.DeleteFunc(slices.Clone(queue), func(id uuid.UUID) bool { return id == agentID })
+	delete(f.queues, orderID)
+	order.OrderStatus, order.AgentID = "signed", agentID
+	if err := f.change(order, released, events); err != nil {
+		return nil, nil, err
+	}
+	return order, released, nil
+}
+
+func (f *fakeStore) UpdateOrder(ctx context.Context, order *infra.Order, fields []string, events database.OrderEvents) (*infra.Order, error) {
+	stored, err := f.GetOrderById(ctx, order.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if stored.Version != order.Version {
+		return nil, database.ErrVersionConflict
+	}
+	updated := *order
+	f.updated = fields
+	if err := f.change(&updated, nil, func(order *infra.Order, _ []uuid.UUID) ([]*infra.OrderEvent, error) {
+		return events(order)
+	}); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// session возвращает сеанс исполнителя; busy вычисляется, как в sessionStatus.
+func (f *fakeStore) session(agentID uuid.UUID) *infra.AgentSession {
+	session := *f.agents[agentID]
+	for _, o := range f.orders {
+		if o.OrderStatus == "signed" && o.AgentID == agentID && session.Status != infra.AgentOffline {
+			session.Status, session.CurrentOrderID = infra.AgentBusy, &o.OrderID
+		}
+	}
+	return &session
+}
+
+// agent возвращает сохранённый сеанс исполнителя, заводя offline, если его нет.
+func (f *fakeStore) agent(agentID uuid.UUID) *infra.AgentSession {
+	if f.agents[agentID] == nil {
+		f.agents[agentID] = &infra.AgentSession{AgentID: agentID, Status: infra.AgentOffline}
+	}
+	return f.agents[agentID]
+}
+
+// clearSearch забывает пожелания, заданные на время поиска.
+func clearSearch(a *infra.AgentSession) {
+	a.RadiusKm, a.AvailableFrom, a.AvailableUntil, a.StartedAt = nil, nil, nil, nil
+}
+
+func (f *fakeStore) StartAgentSession(ctx context.Context, session *infra.AgentSession) (*infra.AgentSession, error) {
+	a := f.agent(session.AgentID)
+	if a.Status != infra.AgentSearching {
+		now := time.Now()
+		a.StartedAt = &now
+	}
+	a.Status = infra.AgentSearching
+	if session.Latitude != nil {
+		a.Latitude, a.Longitude = session.Latitude, session.Longitude
+	}
+	a.RadiusKm, a.AvailableFrom, a.AvailableUntil = session.RadiusKm, session.AvailableFrom, session.AvailableUntil
+	a.LastSeenAt = time.Now()
+	return f.session(a.AgentID), nil
+}
+
+func (f *fakeStore) StopAgentSession(ctx context.Context, agentID uuid.UUID) (*infra.AgentSession, error) {
+	a := f.agent(agentID)
+	a.Status = infra.AgentOnline
+	clearSearch(a)
+	a.LastSeenAt = time.Now()
+	return f.session(agentID), nil
+}
+
+func (f *fakeStore) TouchAgentSession(ctx context.Context, agentID uuid.UUID, latitude, longitude *float64) (*infra.AgentSession, error) {
+	a := f.agent(agentID)
+	if a.Status == infra.AgentOffline {
+		a.Status = infra.AgentOnline
+	}
+	if latitude != nil {
+		a.Latitude, a.Longitude = latitude, longitude
+	}
+	a.LastSeenAt = time.Now()
+	return f.session(agentID), nil
+}
+
+func (f *fakeStore) ListAgentSessions(ctx context.Context, status string) ([]*infra.AgentSession, error) {
+	var sessions []*infra.AgentSession
+	for id := range f.agents {
+		if session := f.session(id); status == "" || session.Status == status {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (f *fakeStore) CloseIdleSessions(ctx context.Context, timeout time.Duration, limit int) (int, error) {
+	closed := 0
+	for _, a := range f.agents {
+		if closed == limit {
+			break
+		}
+		if a.Status == infra.AgentOffline || !a.LastSeenAt.Before(time.Now().Add(-timeout)) {
+			continue
+		}
+		a.Status = infra.AgentOffline
+		clearSearch(a)
+		closed++
+	}
+	return closed, nil
+}
+
+func (f *fakeStore) MarkEventsPublished(ctx context.Context, eventIDs []string) error {
+	f.published = append(f.published, eventIDs...)
+	return nil
+}
+
+// fakeBroker собирает события без брокера. Payload события — id получателя:
+// исполнителя для order.candidate_released, иначе клиента.
+type fakeBroker struct {
+	Broker
+	// publishErr — ошибка публикации, пока брокер недоступен
+	publishErr error
+}
+
+func (fakeBroker) event(order *infra.Order, routingKey string, recipient uuid.UUID) *infra.OrderEvent {
+	return &infra.OrderEvent{
+		EventID:    uuid.NewString(),
+		OrderID:    order.OrderID,
+		RoutingKey: routingKey,
+		Payload:    []byte(recipient.String()),
+	}
+}
+
+func (b fakeBroker) OrderCancelledEvent(ctx context.Context, order *infra.Order, c *infra.Cancellation) (*infra.OrderEvent, error) {
+	return b.event(order, events.OrderCancelled, order.UserID), nil
+}
+
+func (b fakeBroker) OrderCompletedEvent(ctx context.Context, order *infra.Order) (*infra.OrderEvent, error) {
+	return b.event(order, events.OrderCompleted, order.UserID), nil
+}
+
+func (b fakeBroker) OrderUpdatedEvent(ctx context.Context, order *infra.Order, changedFields []string) (*infra.OrderEvent, error) {
+	return b.event(order, events.OrderUpdated, order.UserID), nil
+}
+
+func (b fakeBroker) OrderExpiredEvent(ctx context.Context, order *infra.Order) (*infra.OrderEvent, error) {
+	return b.event(order, events.OrderExpired, order.UserID), nil
+}
+
+func (b fakeBroker) OrderAssignedEvent(ctx context.Context, order *infra.Order) (*infra.OrderEvent, error) {
+	return b.event(order, events.OrderAssigned, order.UserID), nil
+}
+
+func (b fakeBroker) CandidateReleasedEvent(ctx context.Context, order *infra.Order, agentID uuid.UUID) (*infra.OrderEvent, error) {
+	return b.event(order, events.OrderCandidateReleased, agentID), nil
+}
+
+func (b fakeBroker) PublishEvent(ctx context.Context, event *infra.OrderEvent) error {
+	return b.publishErr
+}
+
+func newTestService(store *fakeStore) *service {
+	return &service{
+		logger: zap.NewNop(),
+		db:     store,
+		broker: fakeBroker{},
+	}
+}
+
+// sent перечисляет события как "routing key → получатель".
+func sent(saved []*infra.OrderEvent) []string {
+	out := make([]string, len(saved))
+	for i, e := range saved {
+		out[i] = e.RoutingKey + " → " + string(e.Payload)
+	}
+	return out
+}
+
+func ptr[T any](v T) *T { return &v }
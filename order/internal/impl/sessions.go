@@ -0,0 +1,90 @@
+package impl
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"order_service/internal/infra"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// StartSearch переводит исполнителя в searching: ему начинают предлагать
+// заказы, подходящие под его радиус и время.
+func (s *service) StartSearch(ctx context.Context, session *infra.AgentSession) (*infra.AgentSession, error) {
+	s.logger.Info("Starting agent search", zap.String("agentID", session.AgentID.String()))
+
+	if err := session.Validate(time.Now()); err != nil {
+		return nil, err
+	}
+
+	session, err := s.db.StartAgentSession(ctx, session)
+	if err != nil {
+		s.logger.Error("Failed to start agent session", zap.Error(err))
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// StopSearch заканчивает поиск заказов; исполнитель остаётся на линии.
+func (s *service) StopSearch(ctx context.Context, agentID uuid.UUID) (*infra.AgentSession, error) {
+	s.logger.Info("Stopping agent search", zap.String("agentID", agentID.String()))
+
+	session, err := s.db.StopAgentSession(ctx, agentID)
+	if err != nil {
+		s.logger.Error("Failed to stop agent session", zap.Error(err))
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Heartbeat продлевает сеанс исполнителя и запоминает, где он сейчас.
+func (s *service) Heartbeat(ctx context.Context, agentID uuid.UUID, latitude, longitude *float64) (*infra.AgentSession, error) {
+	presence := &infra.AgentSession{AgentID: agentID, Latitude: latitude, Longitude: longitude}
+	if err := presence.Validate(time.Now()); err != nil {
+		return nil, err
+	}
+
+	session, err := s.db.TouchAgentSession(ctx, agentID, latitude, longitude)
+	if err != nil {
+		s.logger.Error("Failed to touch agent session", zap.Error(err))
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// ListAgentSessions возвращает сеансы исполнителей для менеджеров; status
+// необязателен.
+func (s *service) ListAgentSessions(ctx context.Context, status string) ([]*infra.AgentSession, error) {
+	if status != "" && !slices.Contains(infra.AgentStatuses, status) {
+		return nil, ErrInvalidAgentStatus
+	}
+
+	sessions, err := s.db.ListAgentSessions(ctx, status)
+	if err != nil {
+		s.logger.Error("Failed to list agent sessions", zap.Error(err))
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// CloseIdleSessions переводит в offline до limit исполнителей, которые
+// перестали присылать heartbeat. Безопасно вызывать с нескольких реплик.
+func (s *service) CloseIdleSessions(ctx context.Context, limit int) (int, error) {
+	closed, err := s.db.CloseIdleSessions(ctx, s.sessions.HeartbeatTimeout, limit)
+	if err != nil {
+		s.logger.Error("Failed to close idle agent sessions", zap.Error(err))
+		return 0, err
+	}
+	if closed > 0 {
+		s.logger.Info("Agents went offline", zap.Int("count", closed))
+	}
+
+	return closed, nil
+}
@@ -0,0 +1,170 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"order_service/internal/config"
+	"order_service/internal/infra"
+
+	"github.com/google/uuid"
+)
+
+func sessionService(store *fakeStore) *service {
+	s := newTestService(store)
+	s.sessions = &config.AgentSessions{HeartbeatTimeout: time.Minute}
+	return s
+}
+
+// Сеанс проходит шаги по порядку; после каждого проверяется, в каком
+// состоянии исполнитель и что осталось от его пожеланий.
+func TestAgentSessionTransitions(t *testing.T) {
+	agentID := uuid.UUID{2}
+	store := newFakeStore()
+	s := sessionService(store)
+	ctx := context.Background()
+
+	heartbeat := func() (*infra.AgentSession, error) {
+		return s.Heartbeat(ctx, agentID, ptr(55.75), ptr(37.62))
+	}
+	search := func(radius float64) func() (*infra.AgentSession, error) {
+		return func() (*infra.AgentSession, error) {
+			return s.StartSearch(ctx, &infra.AgentSession{AgentID: agentID, RadiusKm: ptr(radius)})
+		}
+	}
+	idle := func() (*infra.AgentSession, error) {
+		store.agents[agentID].LastSeenAt = time.Now().Add(-2 * time.Minute)
+		if _, err := s.CloseIdleSessions(ctx, 10); err != nil {
+			return nil, err
+		}
+		return store.session(agentID), nil
+	}
+
+	var startedAt *time.Time
+	steps := []struct {
+		name   string
+		do     func() (*infra.AgentSession, error)
+		status string
+		radius *float64
+	}{
+		{"heartbeat comes online", heartbeat, infra.AgentOnline, nil},
+		{"start search", search(5), infra.AgentSearching, ptr(5.0)},
+		{"search again keeps the session", search(10), infra.AgentSearching, ptr(10.0)},
+		{"heartbeat keeps searching", heartbeat, infra.AgentSearching, ptr(10.0)},
+		{"stop search", func() (*infra.AgentSession, error) { return s.StopSearch(ctx, agentID) }, infra.AgentOnline, nil},
+		{"search after stop", search(3), infra.AgentSearching, ptr(3.0)},
+		{"no heartbeats", idle, infra.AgentOffline, nil},
+		{"heartbeat after offline", heartbeat, infra.AgentOnline, nil},
+	}
+
+	for _, step := range steps {
+		session, err := step.do()
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if session.Status != step.status {
+			t.Errorf("%s: status = %s, want %s", step.name, session.Status, step.status)
+		}
+		switch {
+		case step.radius == nil && session.RadiusKm != nil:
+			t.Errorf("%s: radius = %v, want none", step.name, *session.RadiusKm)
+		case step.radius != nil && (session.RadiusKm == nil || *session.RadiusKm != *step.radius):
+			t.Errorf("%s: radius = %v, want %v", step.name, session.RadiusKm, *step.radius)
+		}
+
+		switch step.name {
+		case "start search":
+			startedAt = session.StartedAt
+		case "search again keeps the session":
+			if session.StartedAt == nil || startedAt == nil || !session.StartedAt.Equal(*startedAt) {
+				t.Errorf("%s: started_at = %v, want %v", step.name, session.StartedAt, startedAt)
+			}
+		}
+	}
+}
+
+func TestAgentSessionBusy(t *testing.T) {
+	agentID := uuid.UUID{2}
+	order := &infra.Order{OrderID: uuid.UUID{9}, OrderStatus: "signed", AgentID: agentID}
+	store := newFakeStore(order)
+	s := sessionService(store)
+
+	session, err := s.Heartbeat(context.Background(), agentID, nil, nil)
+	if err != nil {
+		t.Fatalf("heartbeat: %v", err)
+	}
+	if session.Status != infra.AgentBusy || session.CurrentOrderID == nil || *session.CurrentOrderID != order.OrderID {
+		t.Errorf("session = %s with order %v, want busy with %v", session.Status, session.CurrentOrderID, order.OrderID)
+	}
+
+	busy, err := s.ListAgentSessions(context.Background(), infra.AgentBusy)
+	if err != nil || len(busy) != 1 {
+		t.Errorf("busy agents = %d, %v; want 1", len(busy), err)
+	}
+}
+
+func TestStartSearchValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		session infra.AgentSession
+		valid   bool
+	}{
+		{"no preferences", infra.AgentSession{}, true},
+		{"radius", infra.AgentSession{RadiusKm: ptr(15.0)}, true},
+		{"zero radius", infra.AgentSession{RadiusKm: ptr(0.0)}, false},
+		{"radius too large", infra.AgentSession{RadiusKm: ptr(float64(infra.MaxAgentRadiusKm + 1))}, false},
+		{"half a point", infra.AgentSession{Latitude: ptr(55.75)}, false},
+		{"available until in the past", infra.AgentSession{AvailableUntil: ptr(time.Now().Add(-time.Hour))}, false},
+		{
+			"available until before from",
+			infra.AgentSession{AvailableFrom: ptr(time.Now().Add(2 * time.Hour)), AvailableUntil: ptr(time.Now().Add(time.Hour))},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeStore()
+			session := tt.session
+			session.AgentID = uuid.UUID{2}
+
+			_, err := sessionService(store).StartSearch(context.Background(), &session)
+
+			var verr *infra.ValidationError
+			switch {
+			case tt.valid && err != nil:
+				t.Errorf("unexpected error %v", err)
+			case !tt.valid && !errors.As(err, &verr):
+				t.Errorf("err = %v, want *ValidationError", err)
+			}
+			if stored := store.agents[session.AgentID] != nil; stored != tt.valid {
+				t.Errorf("session stored = %v, want %v", stored, tt.valid)
+			}
+		})
+	}
+}
+
+func TestListAgentSessionsStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		err    error
+	}{
+		{"", nil},
+		{infra.AgentOffline, nil},
+		{infra.AgentOnline, nil},
+		{infra.AgentSearching, nil},
+		{infra.AgentBusy, nil},
+		{"away", ErrInvalidAgentStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			_, err := sessionService(newFakeStore()).ListAgentSessions(context.Background(), tt.status)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("err = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
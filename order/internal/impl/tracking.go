@@ -0,0 +1,102 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"order_service/internal/infra"
+	"order_service/internal/infra/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ReportLocation запоминает место исполнителя. Пока у исполнителя есть заказ
+// в работе, владелец заказа получает место и ETA, но не чаще раза в PushInterval.
+func (s *service) ReportLocation(ctx context.Context, location *infra.AgentLocation) error {
+	now := time.Now()
+	if location.RecordedAt.IsZero() {
+		location.RecordedAt = now
+	}
+	if err := location.Validate(now); err != nil {
+		return err
+	}
+
+	saved, err := s.db.SaveAgentLocation(ctx, location, s.tracking.LocationTTL)
+	if err != nil {
+		s.logger.Error("Failed to save agent location", zap.Error(err))
+		return err
+	}
+	// Пришла точка старше сохранённой или уже устаревшая: владельцу она не нужна
+	if !saved || !now.Before(location.ExpiresAt) {
+		return nil
+	}
+
+	order, err := s.db.GetAgentOrder(ctx, location.AgentID)
+	if errors.Is(err, database.ErrNoActiveOrder) {
+		return nil
+	}
+	if err != nil {
+		s.logger.Error("Failed to get agent order", zap.Error(err))
+		return err
+	}
+
+	claimed, err := s.db.ClaimLocationPush(ctx, location.AgentID, s.tracking.PushInterval)
+	if err != nil {
+		s.logger.Error("Failed to claim location push", zap.Error(err))
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	// Место уже сохранено, поэтому сбой публикации только логируется: владелец
+	// получит следующее. В историю order_events места не пишутся — через
+	// несколько минут они никому не нужны
+	update := infra.EstimateArrival(order, location, s.tracking.AverageSpeedKmh)
+	if err := s.broker.PublishAgentLocation(ctx, update); err != nil {
+		s.logger.Error("Failed to publish agent location", zap.Error(err))
+	}
+	return nil
+}
+
+// GetAgentLocation возвращает клиенту место исполнителя его заказа и ETA,
+// пока заказ в работе.
+func (s *service) GetAgentLocation(ctx context.Context, orderID, userID uuid.UUID) (*infra.LocationUpdate, error) {
+	order, err := s.db.GetOrderById(ctx, orderID)
+	if err != nil {
+		if !errors.Is(err, database.ErrOrderNotFound) {
+			s.logger.Error("Failed to get order by ID", zap.Error(err))
+		}
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, ErrNotOrderOwner
+	}
+	if order.OrderStatus != "signed" {
+		return nil, ErrOrderNotInProgress
+	}
+
+	location, err := s.db.GetAgentLocation(ctx, order.AgentID)
+	if err != nil {
+		if !errors.Is(err, database.ErrNoLocation) {
+			s.logger.Error("Failed to get agent location", zap.Error(err))
+		}
+		return nil, err
+	}
+
+	return infra.EstimateArrival(order, location, s.tracking.AverageSpeedKmh), nil
+}
+
+// DeleteExpiredLocations удаляет до limit устаревших мест исполнителей.
+// Безопасно вызывать с нескольких реплик.
+func (s *service) DeleteExpiredLocations(ctx context.Context, limit int) (int, error) {
+	deleted, err := s.db.DeleteExpiredLocations(ctx, limit)
+	if err != nil {
+		s.logger.Error("Failed to delete expired locations", zap.Error(err))
+		return 0, err
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,122 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"order_service/internal/infra"
+	"order_service/internal/infra/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// editableIn — статусы, в которых клиент может менять поле. Место и время
+// меняются только пока исполнитель не приступил к заказу, заметки — до конца.
+var editableIn = map[string][]string{
+	"order_address":  {"pending", "matching"},
+	"order_location": {"pending", "matching"},
+	"order_date":     {"pending", "matching"},
+	"order_time_gap": {"pending", "matching"},
+	"notes":          {"pending", "matching", "signed"},
+}
+
+// UpdateOrder меняет поля fields заказа patch.OrderID на значения из patch.
+// patch.Version — версия, которую видел клиент: если заказ с тех пор изменился,
+// возвращается ErrVersionConflict.
+func (s *service) UpdateOrder(ctx context.Context, userID uuid.UUID, patch *infra.Order, fields []string) (*infra.Order, error) {
+	s.logger.Info("Updating order", zap.String("orderID", patch.OrderID.String()), zap.Strings("fields", fields))
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: no fields to update", ErrInvalidUpdateMask)
+	}
+	for _, field := range fields {
+		if _, ok := editableIn[field]; !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidUpdateMask, field)
+		}
+	}
+
+	order, err := s.db.GetOrderById(ctx, patch.OrderID)
+	if err != nil {
+		if !errors.Is(err, database.ErrOrderNotFound) {
+			s.logger.Error("Failed to get order by ID", zap.Error(err))
+		}
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, ErrUpdateForbidden
+	}
+	if order.Version != patch.Version {
+		return nil, ErrVersionConflict
+	}
+
+	updated := *order
+	var changed []string
+	for _, field := range fields {
+		if !slices.Contains(editableIn[field], order.OrderStatus) {
+			return nil, fmt.Errorf("%w: %s can't be changed in status %q", ErrOrderNotEditable, field, order.OrderStatus)
+		}
+		if applyField(&updated, patch, field) && !slices.Contains(changed, field) {
+			changed = append(changed, field)
+		}
+	}
+	if err := updated.ValidateFields(time.Now(), changed); err != nil {
+		return nil, err
+	}
+
+	// Ничего не изменилось: версия остаётся прежней, событие не нужно
+	if len(changed) == 0 {
+		return order, nil
+	}
+
+	var events []*infra.OrderEvent
+	result, err := s.db.UpdateOrder(ctx, &updated, changed, func(order *infra.Order) ([]*infra.OrderEvent, error) {
+		event, err := s.broker.OrderUpdatedEvent(ctx, order, changed)
+		if err != nil {
+			return nil, err
+		}
+		events = []*infra.OrderEvent{event}
+		return events, nil
+	})
+	if err != nil {
+		if !errors.Is(err, database.ErrVersionConflict) {
+			s.logger.Error("Failed to update order", zap.Error(err))
+		}
+		return nil, err
+	}
+
+	s.publishEvents(ctx, events)
+
+	s.logger.Info("Order updated successfully", zap.Int64("version", result.Version))
+	return result, nil
+}
+
+// applyField копирует поле field из patch в order и сообщает, изменилось ли оно.
+func applyField(order, patch *infra.Order, field string) bool {
+	switch field {
+	case "order_address":
+		changed := order.OrderAddress != patch.OrderAddress
+		order.OrderAddress = patch.OrderAddress
+		return changed
+	case "order_location":
+		changed := order.OrderLocation != patch.OrderLocation
+		order.OrderLocation = patch.OrderLocation
+		return changed
+	case "order_date":
+		changed := !order.OrderDate.Equal(patch.OrderDate)
+		order.OrderDate = patch.OrderDate
+		return changed
+	case "order_time_gap":
+		changed := order.OrderTimeGap != patch.OrderTimeGap
+		order.OrderTimeGap = patch.OrderTimeGap
+		return changed
+	case "notes":
+		changed := order.Notes != patch.Notes
+		order.Notes = patch.Notes
+		return changed
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"order_service/internal/infra"
+
+	"github.com/google/uuid"
+)
+
+func TestUpdateOrder(t *testing.T) {
+	userID := uuid.UUID{1}
+	date := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name    string
+		status  string
+		userID  uuid.UUID
+		version int64
+		patch   infra.Order
+		fields  []string
+		err     error
+		changed []string
+	}{
+		{"address", "pending", userID, 3, infra.Order{OrderAddress: "Арбат, 2"}, []string{"order_address"}, nil, []string{"order_address"}},
+		{
+			"date and notes while matching", "matching", userID, 3,
+			infra.Order{OrderDate: date.Add(time.Hour), Notes: "домофон 12"},
+			[]string{"order_date", "notes"}, nil, []string{"order_date", "notes"},
+		},
+		{"notes of a signed order", "signed", userID, 3, infra.Order{Notes: "позвонить"}, []string{"notes"}, nil, []string{"notes"}},
+		{"repeated field", "pending", userID, 3, infra.Order{Notes: "x"}, []string{"notes", "notes"}, nil, []string{"notes"}},
+		{"nothing changed", "pending", userID, 3, infra.Order{OrderAddress: "Тверская, 1"}, []string{"order_address"}, nil, nil},
+		{"empty mask", "pending", userID, 3, infra.Order{}, nil, ErrInvalidUpdateMask, nil},
+		{"unknown field", "pending", userID, 3, infra.Order{}, []string{"order_status"}, ErrInvalidUpdateMask, nil},
+		{"another client", "pending", uuid.UUID{2}, 3, infra.Order{Notes: "x"}, []string{"notes"}, ErrUpdateForbidden, nil},
+		{"stale version", "pending", userID, 2, infra.Order{Notes: "x"}, []string{"notes"}, ErrVersionConflict, nil},
+		{"address of a signed order", "signed", userID, 3, infra.Order{OrderAddress: "Арбат, 2"}, []string{"order_address"}, ErrOrderNotEditable, nil},
+		{"finished order", "completed", userID, 3, infra.Order{Notes: "x"}, []string{"notes"}, ErrOrderNotEditable, nil},
+		{"invalid value", "pending", userID, 3, infra.Order{OrderAddress: " "}, []string{"order_address"}, &infra.ValidationError{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &infra.Order{
+				OrderID:       uuid.UUID{9},
+				UserID:        userID,
+				OrderStatus:   tt.status,
+				OrderAddress:  "Тверская, 1",
+				OrderLocation: "Москва",
+				// В прошлом: правка других полей не должна упираться в дату
+				OrderDate:    time.Now().Add(-time.Hour),
+				OrderTimeGap: 30 * time.Minute,
+				Version:      3,
+			}
+			store := newFakeStore(order)
+			patch := tt.patch
+			patch.OrderID, patch.Version = order.OrderID, tt.version
+
+			result, err := newTestService(store).UpdateOrder(context.Background(), tt.userID, &patch, tt.fields)
+
+			var verr *infra.ValidationError
+			switch {
+			case errors.As(tt.err, &verr):
+				if !errors.As(err, &verr) {
+					t.Fatalf("err = %v, want *ValidationError", err)
+				}
+			case !errors.Is(err, tt.err):
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if err != nil || tt.changed == nil {
+				if len(store.saved) != 0 || store.orders[order.OrderID].Version != 3 {
+					t.Errorf("order was written: %d events, version %d", len(store.saved), store.orders[order.OrderID].Version)
+				}
+				return
+			}
+
+			if !slices.Equal(store.updated, tt.changed) {
+				t.Errorf("updated fields = %v, want %v", store.updated, tt.changed)
+			}
+			if result.Version != 4 {
+				t.Errorf("version = %d, want 4", result.Version)
+			}
+			if got := sent(store.saved); !slices.Equal(got, []string{"order.updated → " + userID.String()}) {
+				t.Errorf("saved events = %v", got)
+			}
+		})
+	}
+}
+
+func TestApplyField(t *testing.T) {
+	date := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	order := infra.Order{
+		OrderAddress:  "Тверская, 1",
+		OrderLocation: "Москва",
+		OrderDate:     date,
+		OrderTimeGap:  time.Hour,
+		Notes:         "домофон",
+	}
+
+	tests := []struct {
+		field   string
+		patch   infra.Order
+		changed bool
+	}{
+		{"order_address", infra.Order{OrderAddress: "Арбат, 2"}, true},
+		{"order_address", infra.Order{OrderAddress: "Тверская, 1"}, false},
+		{"order_location", infra.Order{OrderLocation: "Казань"}, true},
+		{"order_date", infra.Order{OrderDate: date.In(time.FixedZone("MSK", 3*60*60))}, false},
+		{"order_date", infra.Order{OrderDate: date.Add(time.Minute)}, true},
+		{"order_time_gap", infra.Order{OrderTimeGap: time.Hour}, false},
+		{"notes", infra.Order{Notes: ""}, true},
+		{"order_status", infra.Order{OrderStatus: "completed"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			updated := order
+			if changed := applyField(&updated, &tt.patch, tt.field); changed != tt.changed {
+				t.Errorf("changed = %v, want %v", changed, tt.changed)
+			}
+			if updated.OrderStatus != "" {
+				t.Errorf("a field outside the mask was copied")
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"order_service/internal/config"
+	"order_service/internal/infra"
+
+	"github.com/google/uuid"
+)
+
+// Записи клиента по версии из If-Match проходят, только если заказ с тех пор
+// не менялся; версия 0 — запись без If-Match.
+func TestOrderVersion(t *testing.T) {
+	userID, agentID := uuid.UUID{1}, uuid.UUID{2}
+
+	writes := map[string]func(s *service, orderID uuid.UUID, version int64) error{
+		"complete": func(s *service, orderID uuid.UUID, version int64) error {
+			return s.CompleteOrder(context.Background(), orderID, version)
+		},
+		"cancel": func(s *service, orderID uuid.UUID, version int64) error {
+			return s.CancelOrder(context.Background(), &infra.Cancellation{
+				OrderID:     orderID,
+				Reason:      "changed_plans",
+				CancelledBy: infra.CancelledByClient,
+				ActorID:     userID,
+			}, "", version)
+		},
+		"select agent": func(s *service, orderID uuid.UUID, version int64) error {
+			_, err := s.SelectAgent(context.Background(), orderID, userID, agentID, version)
+			return err
+		},
+	}
+	versions := []struct {
+		name    string
+		version int64
+		err     error
+	}{
+		{"current version", 3, nil},
+		{"stale version", 2, ErrVersionConflict},
+		{"newer version", 4, ErrVersionConflict},
+		{"no version", 0, nil},
+	}
+
+	for write, do := range writes {
+		for _, v := range versions {
+			t.Run(write+"/"+v.name, func(t *testing.T) {
+				order := &infra.Order{OrderID: uuid.UUID{9}, UserID: userID, OrderStatus: "matching", Version: 3}
+				store := newFakeStore(order)
+				store.queues[order.OrderID] = []uuid.UUID{agentID}
+				s := newTestService(store)
+				s.cancellation = &config.Cancellation{FreeStatuses: []string{"pending", "matching"}}
+
+				if err := do(s, order.OrderID, v.version); !errors.Is(err, v.err) {
+					t.Fatalf("err = %v, want %v", err, v.err)
+				}
+
+				stored := store.orders[order.OrderID]
+				if v.err != nil {
+					if stored.Version != 3 || stored.OrderStatus != "matching" || len(store.saved) != 0 {
+						t.Errorf("order was written: %s v%d, %d events", stored.OrderStatus, stored.Version, len(store.saved))
+					}
+					return
+				}
+				if stored.Version != 4 || len(store.saved) == 0 {
+					t.Errorf("order = %s v%d with %d events, want v4 with events", stored.OrderStatus, stored.Version, len(store.saved))
+				}
+			})
+		}
+	}
+}
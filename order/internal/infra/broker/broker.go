@@ -2,140 +2,369 @@ package broker
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"order_service/internal/config"
 	"order_service/internal/infra"
+	"orderq/pkg/events"
+	eventsv1 "orderq/pkg/events/proto/v1"
 
+	"github.com/google/uuid"
 	"github.com/streadway/amqp"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// State — состояние подключения к RabbitMQ, отдаётся в health check.
+type State string
+
+const (
+	StateConnecting State = "connecting"
+	StateConnected  State = "connected"
+	StateClosed     State = "closed"
+)
+
+var (
+	ErrNotConnected = errors.New("rabbitmq is not connected")
+	ErrClosed       = errors.New("rabbitmq connection is closed")
+)
+
+// RabbitMQ держит подключение к брокеру и восстанавливает его при обрыве:
+// переподключается с экспоненциальной задержкой и заново объявляет топологию.
+// Пока подключения нет, публикация сразу возвращает ErrNotConnected.
 type RabbitMQ struct {
-	logger *zap.Logger
-	conn   *amqp.Connection
-	ch     *amqp.Channel
+	logger    *zap.Logger
+	cfg       *config.RabbitMQ
+	mu        sync.RWMutex
+	conn      *amqp.Connection
+	ch        *amqp.Channel
+	publisher *publisher
+	pubMu     sync.Mutex // публикации идут по одной, чтобы сопоставлять подтверждения
+	state     State
+	listeners []func(State)
+	closed    chan struct{}
+	closeOnce sync.Once
 }
 
-const OrderEventExchange = "order.events"
+// eventSource — атрибут source CloudEvents для событий этого сервиса.
+const eventSource = "/orderq/order_service"
 
 func New(log *zap.Logger, cfg *config.RabbitMQ) (*RabbitMQ, error) {
-	conn, err := amqp.Dial(cfg.URL)
+	r := &RabbitMQ{
+		logger: log,
+		cfg:    cfg,
+		state:  StateConnecting,
+		closed: make(chan struct{}),
+	}
+
+	if err := r.connect(); err != nil {
+		return nil, err
+	}
+	go r.watch()
+
+	log.Info("Connect to RabbitMQ success")
+
+	return r, nil
+}
+
+func (r *RabbitMQ) connect() error {
+	conn, err := amqp.Dial(r.cfg.URL)
 	if err != nil {
-		return nil, fmt.Errorf("amqp.Dial: %w", err)
+		return fmt.Errorf("amqp.Dial: %w", err)
 	}
 
 	ch, err := conn.Channel()
 	if err != nil {
-		return nil, fmt.Errorf("conn.Channel: %w", err)
-	}
-
-	if err := ch.ExchangeDeclare(
-		OrderEventExchange,
-		"topic",
-		true,  // durable
-		false, // auto-delete
-		false,
-		false,
-		nil,
-	); err != nil {
-		return nil, fmt.Errorf("ch.ExchangeDeclare: %w", err)
-	}
-
-	var orderQueues = map[string][]string{
-		"order.created":   {"queue_order_created"},
-		"order.assigned":  {"queue_order_assigned"},
-		"order.accepted":  {"queue_order_accepted"},
-		"order.cancelled": {"queue_order_cancelled"},
-		"order.completed": {"queue_order_completed"},
-		"order.updated":   {"queue_order_updated"},
-	}
-
-	for routingKey, queues := range orderQueues {
-		for _, queueName := range queues {
-			_, err := ch.QueueDeclare(
-				queueName,
-				true,  // durable
-				false, // auto-delete
-				false,
-				false,
-				nil,
-			)
-			if err != nil {
-				return nil, err
-			}
-
-			// Привязка очереди к exchange и routing key
-			if err := ch.QueueBind(
-				queueName,
-				routingKey,
-				OrderEventExchange,
-				false,
-				nil,
-			); err != nil {
-				return nil, err
-			}
+		conn.Close()
+		return fmt.Errorf("conn.Channel: %w", err)
+	}
+
+	if err := declareTopology(ch); err != nil {
+		conn.Close()
+		return err
+	}
+
+	publisher, err := newPublisher(ch)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	r.mu.Lock()
+	r.conn = conn
+	r.ch = ch
+	r.publisher = publisher
+	r.mu.Unlock()
+
+	r.setState(StateConnected)
+	return nil
+}
+
+// declareTopology объявляет только exchange: очереди и привязки объявляют
+// сервисы-потребители.
+func declareTopology(ch *amqp.Channel) error {
+	return events.OrderProducer.Declare(ch)
+}
+
+// watch ждёт обрыва подключения или канала и переподключается, пока не вызван Close.
+func (r *RabbitMQ) watch() {
+	for {
+		r.mu.RLock()
+		conn, ch := r.conn, r.ch
+		r.mu.RUnlock()
+
+		var cause *amqp.Error
+		select {
+		case <-r.closed:
+			return
+		case cause = <-conn.NotifyClose(make(chan *amqp.Error, 1)):
+		case cause = <-ch.NotifyClose(make(chan *amqp.Error, 1)):
+			// Канал закрыт сервером, а соединение живо — пересоздаём всё целиком
+			conn.Close()
 		}
+
+		select {
+		case <-r.closed:
+			return
+		default:
+		}
+
+		r.logger.Warn("RabbitMQ connection lost, reconnecting", zap.Any("cause", cause))
+		r.setState(StateConnecting)
+		r.reconnect()
 	}
+}
 
-	log.Info("Connect to RabbitMQ success")
+func (r *RabbitMQ) reconnect() {
+	delay := r.cfg.ReconnectMin
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-r.closed:
+			return
+		case <-time.After(delay):
+		}
 
-	return &RabbitMQ{
-		conn:   conn,
-		ch:     ch,
-		logger: log,
-	}, nil
+		err := r.connect()
+		if err == nil {
+			r.logger.Info("Reconnect to RabbitMQ success", zap.Int("attempt", attempt))
+			return
+		}
+
+		r.logger.Warn("failed to reconnect to RabbitMQ", zap.Int("attempt", attempt), zap.Duration("delay", delay), zap.Error(err))
+		delay = min(delay*2, r.cfg.ReconnectMax)
+	}
+}
+
+func (r *RabbitMQ) setState(state State) {
+	r.mu.Lock()
+	if r.state == state || r.state == StateClosed {
+		r.mu.Unlock()
+		return
+	}
+	r.state = state
+	listeners := r.listeners
+	r.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(state)
+	}
+}
+
+// State возвращает текущее состояние подключения.
+func (r *RabbitMQ) State() State {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.state
+}
+
+// OnStateChange регистрирует fn, которая вызывается при каждой смене состояния
+// и сразу — с текущим.
+func (r *RabbitMQ) OnStateChange(fn func(State)) {
+	r.mu.Lock()
+	r.listeners = append(r.listeners, fn)
+	state := r.state
+	r.mu.Unlock()
+
+	fn(state)
+}
+
+// currentPublisher возвращает издателя текущего подключения.
+func (r *RabbitMQ) currentPublisher() (*publisher, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.state != StateConnected {
+		return nil, ErrNotConnected
+	}
+	return r.publisher, nil
 }
 
 func (r *RabbitMQ) Close() error {
+	r.closeOnce.Do(func() { close(r.closed) })
+	r.setState(StateClosed)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	return r.conn.Close()
 }
 
-func (r *RabbitMQ) PublishOrderCreated(ctx context.Context, order *infra.Order) error {
-	body, err := json.Marshal(order)
+func (r *RabbitMQ) OrderCreatedEvent(ctx context.Context, order *infra.Order) (*infra.OrderEvent, error) {
+	return r.newEvent(ctx, events.OrderCreated, order, &eventsv1.OrderCreated{Order: orderEvent(order)})
+}
+
+func (r *RabbitMQ) OrderCancelledEvent(ctx context.Context, order *infra.Order, c *infra.Cancellation) (*infra.OrderEvent, error) {
+	return r.newEvent(ctx, events.OrderCancelled, order, &eventsv1.OrderCancelled{
+		Order:       orderEvent(order),
+		Reason:      c.Reason,
+		Comment:     c.Comment,
+		CancelledBy: c.CancelledBy,
+		Penalty:     c.Penalty,
+	})
+}
+
+func (r *RabbitMQ) OrderCompletedEvent(ctx context.Context, order *infra.Order) (*infra.OrderEvent, error) {
+	return r.newEvent(ctx, events.OrderCompleted, order, &eventsv1.OrderCompleted{Order: orderEvent(order)})
+}
+
+func (r *RabbitMQ) OrderUpdatedEvent(ctx context.Context, order *infra.Order, changedFields []string) (*infra.OrderEvent, error) {
+	return r.newEvent(ctx, events.OrderUpdated, order, &eventsv1.OrderUpdated{
+		Order:         orderEvent(order),
+		ChangedFields: changedFields,
+	})
+}
+
+func (r *RabbitMQ) OrderExpiredEvent(ctx context.Context, order *infra.Order) (*infra.OrderEvent, error) {
+	return r.newEvent(ctx, events.OrderExpired, order, &eventsv1.OrderExpired{
+		Order:    orderEvent(order),
+		Deadline: timestamppb.New(order.OrderDate.Add(order.OrderTimeGap)),
+	})
+}
+
+func (r *RabbitMQ) OrderAssignedEvent(ctx context.Context, order *infra.Order) (*infra.OrderEvent, error) {
+	return r.newEvent(ctx, events.OrderAssigned, order, &eventsv1.OrderAssigned{
+		Order:   orderEvent(order),
+		AgentId: order.AgentID.String(),
+	})
+}
+
+func (r *RabbitMQ) CandidateReleasedEvent(ctx context.Context, order *infra.Order, agentID uuid.UUID) (*infra.OrderEvent, error) {
+	return r.newEvent(ctx, events.OrderCandidateReleased, order, &eventsv1.OrderCandidateReleased{
+		Order:   orderEvent(order),
+		AgentId: agentID.String(),
+	})
+}
+
+func (r *RabbitMQ) OrderOfferedEvent(ctx context.Context, offer *infra.Offer) (*infra.OrderEvent, error) {
+	return r.newEvent(ctx, events.OrderOffered, offer.Order, &eventsv1.OrderOffered{
+		Order:     orderEvent(offer.Order),
+		AgentId:   offer.AgentID.String(),
+		ExpiresAt: timestamppb.New(offer.ExpiresAt),
+	})
+}
+
+// OfferExpiredEvent сообщает исполнителю, что предложение закрыто; причина —
+// статус предложения (expired или withdrawn).
+func (r *RabbitMQ) OfferExpiredEvent(ctx context.Context, offer *infra.Offer) (*infra.OrderEvent, error) {
+	return r.newEvent(ctx, events.OrderOfferExpired, offer.Order, &eventsv1.OrderOfferExpired{
+		Order:   orderEvent(offer.Order),
+		AgentId: offer.AgentID.String(),
+		Reason:  offer.Status,
+	})
+}
+
+// PublishAgentLocation сообщает владельцу заказа, где исполнитель и когда
+// доберётся. Место быстро устаревает, поэтому событие публикуется сразу и не
+// записывается в order_events.
+func (r *RabbitMQ) PublishAgentLocation(ctx context.Context, update *infra.LocationUpdate) error {
+	event := &eventsv1.OrderAgentLocation{
+		Order:      orderEvent(update.Order),
+		AgentId:    update.Location.AgentID.String(),
+		Latitude:   update.Location.Latitude,
+		Longitude:  update.Location.Longitude,
+		RecordedAt: timestamppb.New(update.Location.RecordedAt),
+	}
+	if update.Location.AccuracyM != nil {
+		event.AccuracyM = *update.Location.AccuracyM
+	}
+	if update.DistanceKm != nil {
+		event.DistanceKm = *update.DistanceKm
+	}
+	if update.ETA != nil {
+		event.Eta = durationpb.New(*update.ETA)
+	}
+	ce, err := r.newEvent(ctx, events.OrderAgentLocation, update.Order, event)
 	if err != nil {
-		return fmt.Errorf("json.Marshal: %w", err)
+		return err
 	}
-
-	return r.ch.Publish(
-		OrderEventExchange,
-		"order.created",
-		false,
-		false,
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-		},
-	)
+	return r.PublishEvent(ctx, ce)
 }
 
-func (r *RabbitMQ) PublishOrderCancelled(ctx context.Context, order *infra.Order) error {
-	body, err := json.Marshal(order)
+// newEvent собирает событие заказа в виде записи order_events; в брокер его
+// отправляет PublishEvent.
+func (r *RabbitMQ) newEvent(ctx context.Context, routingKey string, order *infra.Order, event events.OrderEvent) (*infra.OrderEvent, error) {
+	body, err := events.Marshal(event, r.cfg.EventContentType)
 	if err != nil {
-		return fmt.Errorf("json.Marshal: %w", err)
+		return nil, fmt.Errorf("events.Marshal: %w", err)
 	}
 
-	return r.ch.Publish(
-		OrderEventExchange,
-		"order.cancelled",
-		false,
-		false,
-		amqp.Publishing{Body: body},
-	)
+	return &infra.OrderEvent{
+		EventID:       uuid.NewString(),
+		OrderID:       order.OrderID,
+		RoutingKey:    routingKey,
+		EventType:     events.Schema(event),
+		Source:        eventSource,
+		ContentType:   r.cfg.EventContentType,
+		CorrelationID: correlationID(ctx, order),
+		Payload:       body,
+		OccurredAt:    time.Now().UTC(),
+	}, nil
 }
 
-func (r *RabbitMQ) PublishOrderCompleted(ctx context.Context, order *infra.Order) error {
-	body, err := json.Marshal(order)
-	if err != nil {
-		return fmt.Errorf("json.Marshal: %w", err)
+// PublishEvent публикует событие из order_events. Его event_id становится
+// ce-id, поэтому повторная публикация того же события — дубль, который
+// потребители отбрасывают.
+func (r *RabbitMQ) PublishEvent(ctx context.Context, event *infra.OrderEvent) error {
+	ce := events.CloudEvent{
+		ID:      event.EventID,
+		Source:  event.Source,
+		Type:    event.EventType,
+		Time:    event.OccurredAt,
+		Subject: event.OrderID.String(),
 	}
 
-	return r.ch.Publish(
-		OrderEventExchange,
-		"order.completed",
-		false,
-		false,
-		amqp.Publishing{Body: body},
-	)
+	// Атрибуты CloudEvents дублируются в стандартных свойствах AMQP для
+	// потребителей, которые читают только их
+	msg := amqp.Publishing{
+		Headers:       ce.Headers(),
+		ContentType:   event.ContentType,
+		DeliveryMode:  amqp.Persistent,
+		MessageId:     ce.ID,
+		CorrelationId: event.CorrelationID,
+		Timestamp:     ce.Time,
+		Type:          ce.Type,
+		AppId:         "order_service",
+		Body:          event.Payload,
+	}
+
+	if err := r.publish(ctx, event.RoutingKey, msg); err != nil {
+		return fmt.Errorf("publish %s: %w", event.RoutingKey, err)
+	}
+	return nil
+}
+
+func orderEvent(order *infra.Order) *eventsv1.Order {
+	return events.OrderToProto(events.Order{
+		OrderID:       order.OrderID,
+		UserID:        order.UserID,
+		AgentID:       order.AgentID,
+		OrderAddress:  order.OrderAddress,
+		OrderLocation: order.OrderLocation,
+		OrderDate:     order.OrderDate,
+		OrderTimeGap:  order.OrderTimeGap,
+		OrderStatus:   order.OrderStatus,
+	})
 }
@@ -0,0 +1,143 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"order_service/internal/infra"
+	"orderq/pkg/events"
+
+	"github.com/streadway/amqp"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	// ErrUnroutable — сообщение опубликовано с mandatory, но ни одна очередь его не приняла.
+	ErrUnroutable = errors.New("message is unroutable")
+	// ErrNacked — брокер не смог принять сообщение.
+	ErrNacked = errors.New("message was nacked by broker")
+)
+
+// Размер буферов уведомлений: подтверждение, пришедшее после таймаута,
+// не должно блокировать чтение соединения до следующей публикации.
+const notifyBuffer = 64
+
+// publisher публикует в канал в режиме confirm. Номер публикации в канале
+// совпадает с DeliveryTag подтверждения, поэтому публикации должны идти по одной.
+type publisher struct {
+	ch       *amqp.Channel
+	confirms chan amqp.Confirmation
+	returns  chan amqp.Return
+	seq      uint64
+}
+
+func newPublisher(ch *amqp.Channel) (*publisher, error) {
+	if err := ch.Confirm(false); err != nil {
+		return nil, fmt.Errorf("ch.Confirm: %w", err)
+	}
+
+	return &publisher{
+		ch:       ch,
+		confirms: ch.NotifyPublish(make(chan amqp.Confirmation, notifyBuffer)),
+		returns:  ch.NotifyReturn(make(chan amqp.Return, notifyBuffer)),
+	}, nil
+}
+
+// publish отправляет сообщение с mandatory и ждёт подтверждения брокера.
+// Время ожидания ограничено ConfirmTimeout и дедлайном ctx.
+func (r *RabbitMQ) publish(ctx context.Context, routingKey string, msg amqp.Publishing) error {
+	r.pubMu.Lock()
+	defer r.pubMu.Unlock()
+
+	p, err := r.currentPublisher()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.ConfirmTimeout)
+	defer cancel()
+
+	if err := p.ch.Publish(
+		events.OrderExchange,
+		routingKey,
+		true,  // mandatory
+		false, // immediate
+		msg,
+	); err != nil {
+		return fmt.Errorf("ch.Publish: %w", err)
+	}
+	p.seq++
+
+	var returned *amqp.Return
+	for {
+		select {
+		case ret, ok := <-p.returns:
+			if !ok {
+				return ErrNotConnected
+			}
+			if ret.MessageId != msg.MessageId {
+				// Возврат сообщения, подтверждения которого уже не дождались
+				continue
+			}
+			// Брокер присылает basic.return раньше подтверждения того же сообщения
+			returned = &ret
+		case confirm, ok := <-p.confirms:
+			if !ok {
+				return ErrNotConnected
+			}
+			if confirm.DeliveryTag < p.seq {
+				continue
+			}
+			if !confirm.Ack {
+				return ErrNacked
+			}
+			if returned == nil {
+				returned = p.pendingReturn(msg.MessageId)
+			}
+			if returned != nil {
+				r.logger.Error("order event is unroutable",
+					zap.String("routingKey", routingKey),
+					zap.String("messageID", msg.MessageId),
+					zap.Uint16("replyCode", returned.ReplyCode),
+					zap.String("replyText", returned.ReplyText),
+				)
+				return fmt.Errorf("%w: %s", ErrUnroutable, returned.ReplyText)
+			}
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for confirm: %w", ctx.Err())
+		}
+	}
+}
+
+// pendingReturn забирает уже пришедший возврат сообщения. Брокер шлёт basic.return
+// до подтверждения, но select мог выбрать подтверждение первым.
+func (p *publisher) pendingReturn(messageID string) *amqp.Return {
+	for {
+		select {
+		case ret, ok := <-p.returns:
+			if !ok {
+				return nil
+			}
+			if ret.MessageId == messageID {
+				return &ret
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// correlationID берёт идентификатор запроса из gRPC-метаданных, а если его нет — ID заказа.
+func correlationID(ctx context.Context, order *infra.Order) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, key := range []string{"x-correlation-id", "x-request-id"} {
+			if values := md.Get(key); len(values) > 0 && values[0] != "" {
+				return values[0]
+			}
+		}
+	}
+	return order.OrderID.String()
+}
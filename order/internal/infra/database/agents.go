@@ -0,0 +1,183 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"order_service/internal/infra"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// sessionStatus — состояние исполнителя: busy вычисляется по заказу в работе,
+// пока исполнитель на линии.
+const sessionStatus = `CASE WHEN a.status <> 'offline' AND o.order_id IS NOT NULL THEN 'busy' ELSE a.status END`
+
+// sessionColumns — поля сеанса исполнителя в порядке scanSession.
+const sessionColumns = `
+		a.agent_id,
+		` + sessionStatus + `,
+		a.latitude,
+		a.longitude,
+		a.radius_km,
+		a.available_from,
+		a.available_until,
+		a.started_at,
+		a.last_seen_at,
+		o.order_id
+	FROM agents a
+	LEFT JOIN orders o ON o.agent_id = a.agent_id AND o.order_status = 'signed'`
+
+func scanSession(row pgx.Row) (*infra.AgentSession, error) {
+	var session infra.AgentSession
+	err := row.Scan(&session.AgentID,
+		&session.Status,
+		&session.Latitude,
+		&session.Longitude,
+		&session.RadiusKm,
+		&session.AvailableFrom,
+		&session.AvailableUntil,
+		&session.StartedAt,
+		&session.LastSeenAt,
+		&session.CurrentOrderID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// StartAgentSession переводит исполнителя в searching с пожеланиями из
+// session. Если координаты не переданы, остаются последние известные.
+func (p *PostgresDB) StartAgentSession(ctx context.Context, session *infra.AgentSession) (*infra.AgentSession, error) {
+	_, err := p.Db.Exec(ctx, `
+	INSERT INTO agents (agent_id, status, latitude, longitude, radius_km, available_from, available_until, started_at)
+	VALUES ($1, 'searching', $2, $3, $4, $5, $6, NOW())
+	ON CONFLICT (agent_id) DO UPDATE
+	SET status = 'searching',
+		latitude = COALESCE(EXCLUDED.latitude, agents.latitude),
+		longitude = COALESCE(EXCLUDED.longitude, agents.longitude),
+		radius_km = EXCLUDED.radius_km,
+		available_from = EXCLUDED.available_from,
+		available_until = EXCLUDED.available_until,
+		-- Повторный поиск продолжает начатый сеанс
+		started_at = CASE WHEN agents.status = 'searching' THEN agents.started_at ELSE NOW() END,
+		last_seen_at = NOW()
+	`, session.AgentID, session.Latitude, session.Longitude, session.RadiusKm, session.AvailableFrom, session.AvailableUntil)
+	if err != nil {
+		p.Logger.Error("failed to start agent session", zap.Error(err))
+		return nil, fmt.Errorf("failed to start agent session: %w", err)
+	}
+	return p.GetAgentSession(ctx, session.AgentID)
+}
+
+// StopAgentSession заканчивает поиск заказов: исполнитель остаётся online, а
+// его пожелания сбрасываются. Открытое предложение закроет CloseStaleOffers.
+func (p *PostgresDB) StopAgentSession(ctx context.Context, agentID uuid.UUID) (*infra.AgentSession, error) {
+	_, err := p.Db.Exec(ctx, `
+	INSERT INTO agents (agent_id, status)
+	VALUES ($1, 'online')
+	ON CONFLICT (agent_id) DO UPDATE
+	SET status = 'online',
+		radius_km = NULL,
+		available_from = NULL,
+		available_until = NULL,
+		started_at = NULL,
+		last_seen_at = NOW()
+	`, agentID)
+	if err != nil {
+		p.Logger.Error("failed to stop agent session", zap.Error(err))
+		return nil, fmt.Errorf("failed to stop agent session: %w", err)
+	}
+	return p.GetAgentSession(ctx, agentID)
+}
+
+// TouchAgentSession отмечает heartbeat исполнителя. Исполнитель в offline
+// становится online; искать заказы он снова начинает через StartAgentSession.
+func (p *PostgresDB) TouchAgentSession(ctx context.Context, agentID uuid.UUID, latitude, longitude *float64) (*infra.AgentSession, error) {
+	_, err := p.Db.Exec(ctx, `
+	INSERT INTO agents (agent_id, status, latitude, longitude)
+	VALUES ($1, 'online', $2, $3)
+	ON CONFLICT (agent_id) DO UPDATE
+	SET status = CASE WHEN agents.status = 'offline' THEN 'online' ELSE agents.status END,
+		latitude = COALESCE(EXCLUDED.latitude, agents.latitude),
+		longitude = COALESCE(EXCLUDED.longitude, agents.longitude),
+		last_seen_at = NOW()
+	`, agentID, latitude, longitude)
+	if err != nil {
+		p.Logger.Error("failed to touch agent session", zap.Error(err))
+		return nil, fmt.Errorf("failed to touch agent session: %w", err)
+	}
+	return p.GetAgentSession(ctx, agentID)
+}
+
+// GetAgentSession возвращает сеанс исполнителя. Исполнитель, который ни разу
+// не выходил на линию, считается offline.
+func (p *PostgresDB) GetAgentSession(ctx context.Context, agentID uuid.UUID) (*infra.AgentSession, error) {
+	rows, err := p.Db.Query(ctx, `SELECT`+sessionColumns+`
+	WHERE a.agent_id = $1
+	`, agentID)
+	if err != nil {
+		p.Logger.Error("failed to get agent session", zap.Error(err))
+		return nil, fmt.Errorf("failed to get agent session: %w", err)
+	}
+	sessions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*infra.AgentSession, error) {
+		return scanSession(row)
+	})
+	if err != nil {
+		p.Logger.Error("failed to scan agent session", zap.Error(err))
+		return nil, fmt.Errorf("failed to scan agent session: %w", err)
+	}
+	if len(sessions) == 0 {
+		return &infra.AgentSession{AgentID: agentID, Status: infra.AgentOffline}, nil
+	}
+	return sessions[0], nil
+}
+
+// ListAgentSessions возвращает сеансы исполнителей, начиная с последних
+// активных. Если status не пуст, только в этом состоянии.
+func (p *PostgresDB) ListAgentSessions(ctx context.Context, status string) ([]*infra.AgentSession, error) {
+	rows, err := p.Db.Query(ctx, `SELECT`+sessionColumns+`
+	WHERE $1::text = '' OR `+sessionStatus+` = $1
+	ORDER BY a.last_seen_at DESC
+	`, status)
+	if err != nil {
+		p.Logger.Error("failed to list agent sessions", zap.Error(err))
+		return nil, fmt.Errorf("failed to list agent sessions: %w", err)
+	}
+	sessions, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*infra.AgentSession, error) {
+		return scanSession(row)
+	})
+	if err != nil {
+		p.Logger.Error("failed to scan agent session", zap.Error(err))
+		return nil, fmt.Errorf("failed to scan agent session: %w", err)
+	}
+	return sessions, nil
+}
+
+// CloseIdleSessions переводит в offline до limit исполнителей, от которых не
+// было heartbeat дольше timeout. Возвращает число закрытых сеансов.
+func (p *PostgresDB) CloseIdleSessions(ctx context.Context, timeout time.Duration, limit int) (int, error) {
+	tag, err := p.Db.Exec(ctx, `
+	UPDATE agents
+	SET status = 'offline',
+		radius_km = NULL,
+		available_from = NULL,
+		available_until = NULL,
+		started_at = NULL
+	WHERE agent_id IN (
+		SELECT agent_id FROM agents
+		WHERE status <> 'offline' AND last_seen_at < NOW() - $1::interval
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	)
+	`, timeout, limit)
+	if err != nil {
+		p.Logger.Error("failed to close idle agent sessions", zap.Error(err))
+		return 0, fmt.Errorf("failed to close idle agent sessions: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
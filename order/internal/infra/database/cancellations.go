@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"order_service/internal/infra"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// CancelOrder отменяет заказ, отпускает исполнителей из очереди на него,
+// сохраняет запись об отмене и события, которые строит events, в одной
+// транзакции и возвращает заказ в новом состоянии.
+// Заказ отменяется, только если его версия всё ещё равна version — по этому
+// состоянию посчитаны последствия отмены; иначе возвращается ErrVersionConflict.
+func (p *PostgresDB) CancelOrder(ctx context.Context, c *infra.Cancellation, version int64, events MatchEvents) (*infra.Order, error) {
+	tx, err := p.Db.Begin(ctx)
+	if err != nil {
+		p.Logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	order, err := scanOrder(tx.QueryRow(ctx, `
+	UPDATE orders
+	SET order_status = 'cancelled', matching_deadline = NULL, updated_at = NOW(), version = version + 1
+	WHERE order_id = $1 AND version = $2
+	RETURNING`+orderColumns, c.OrderID, version))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrVersionConflict
+	}
+	if err != nil {
+		p.Logger.Error("failed to cancel order", zap.Error(err))
+		return nil, fmt.Errorf("failed to cancel order: %w", err)
+	}
+	// Исполнители в очереди на заказ больше его не ждут
+	released, err := p.releaseCandidates(ctx, tx, c.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var actorID *uuid.UUID
+	if c.ActorID != uuid.Nil {
+		actorID = &c.ActorID
+	}
+	err = tx.QueryRow(ctx, `
+	INSERT INTO order_cancellations (
+		order_id,
+		reason,
+		comment,
+		cancelled_by,
+		actor_id,
+		penalty,
+		fee_amount,
+		fee_currency
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING cancelled_at
+	`,
+		c.OrderID,
+		c.Reason,
+		c.Comment,
+		c.CancelledBy,
+		actorID,
+		c.Penalty,
+		c.FeeAmount,
+		c.FeeCurrency,
+	).Scan(&c.CancelledAt)
+	if err != nil {
+		p.Logger.Error("failed to save order cancellation", zap.Error(err))
+		return nil, fmt.Errorf("failed to save order cancellation: %w", err)
+	}
+	order.Cancellation = c
+
+	built, err := events(order, released)
+	if err != nil {
+		return nil, fmt.Errorf("build events: %w", err)
+	}
+	if err := p.saveEvents(ctx, tx, built); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.Logger.Error("failed to commit order cancellation", zap.Error(err))
+		return nil, fmt.Errorf("failed to commit order cancellation: %w", err)
+	}
+
+	return order, nil
+}
+
+// GetCancellation возвращает запись об отмене заказа или nil, если заказ не отменялся.
+func (p *PostgresDB) GetCancellation(ctx context.Context, orderID uuid.UUID) (*infra.Cancellation, error) {
+	var (
+		c       infra.Cancellation
+		actorID *uuid.UUID
+	)
+	err := p.Db.QueryRow(ctx, `
+	SELECT
+		order_id,
+		reason,
+		comment,
+		cancelled_by,
+		actor_id,
+		penalty,
+		fee_amount,
+		fee_currency,
+		cancelled_at
+	FROM order_cancellations
+	WHERE order_id = $1
+	`, orderID).Scan(
+		&c.OrderID,
+		&c.Reason,
+		&c.Comment,
+		&c.CancelledBy,
+		&actorID,
+		&c.Penalty,
+		&c.FeeAmount,
+		&c.FeeCurrency,
+		&c.CancelledAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		p.Logger.Error("failed to get order cancellation", zap.Error(err))
+		return nil, fmt.Errorf("failed to get order cancellation: %w", err)
+	}
+	if actorID != nil {
+		c.ActorID = *actorID
+	}
+	c.Free = c.Penalty == infra.PenaltyNone
+
+	return &c, nil
+}
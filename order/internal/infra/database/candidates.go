@@ -0,0 +1,488 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"order_service/internal/infra"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+// orderColumns — поля заказа в порядке scanOrder.
+const orderColumns = `
+		order_id,
+		user_id,
+		agent_id,
+		order_address,
+		order_location,
+		order_date,
+		order_time_gap,
+		order_status,
+		created_at,
+		updated_at,
+		notes,
+		version,
+		matching_deadline,
+		latitude,
+		longitude`
+
+func scanOrder(row pgx.Row) (*infra.Order, error) {
+	var order infra.Order
+	err := row.Scan(&order.OrderID,
+		&order.UserID,
+		&order.AgentID,
+		&order.OrderAddress,
+		&order.OrderLocation,
+		&order.OrderDate,
+		&order.OrderTimeGap,
+		&order.OrderStatus,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+		&order.Notes,
+		&order.Version,
+		&order.MatchingDeadline,
+		&order.Latitude,
+		&order.Longitude,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// lockOrder читает заказ и блокирует его до конца транзакции: заявки в очередь
+// на один заказ обрабатываются по одной.
+func (p *PostgresDB) lockOrder(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) (*infra.Order, error) {
+	order, err := scanOrder(tx.QueryRow(ctx, `SELECT`+orderColumns+`
+	FROM orders
+	WHERE order_id = $1
+	FOR UPDATE
+	`, orderID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		p.Logger.Error("failed to lock order", zap.Error(err))
+		return nil, fmt.Errorf("failed to lock order: %w", err)
+	}
+	return order, nil
+}
+
+// agentBusy сообщает, что исполнитель уже выполняет заказ.
+func agentBusy(ctx context.Context, tx pgx.Tx, agentID uuid.UUID) (bool, error) {
+	var busy bool
+	err := tx.QueryRow(ctx, `
+	SELECT EXISTS (SELECT 1 FROM orders WHERE agent_id = $1 AND order_status = 'signed')
+	`, agentID).Scan(&busy)
+	if err != nil {
+		return false, fmt.Errorf("failed to check agent orders: %w", err)
+	}
+	return busy, nil
+}
+
+// JoinOrderQueue ставит исполнителя c.AgentID в очередь на заказ. Первый
+// исполнитель переводит заказ из pending в matching и открывает очередь на
+// window; после этого встать в неё нельзя (ErrMatchingClosed), как и когда
+// в ней уже maxCandidates исполнителей (ErrQueueFull). Возвращает заказ
+// в новом состоянии; c дополняется временем заявки.
+func (p *PostgresDB) JoinOrderQueue(ctx context.Context, c *infra.Candidate, window time.Duration, maxCandidates int) (*infra.Order, error) {
+	tx, err := p.Db.Begin(ctx)
+	if err != nil {
+		p.Logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	order, err := p.lockOrder(ctx, tx, c.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	switch order.OrderStatus {
+	case "pending":
+	case "matching":
+		if order.MatchingDeadline != nil && !time.Now().Before(*order.MatchingDeadline) {
+			return nil, ErrMatchingClosed
+		}
+	default:
+		return nil, ErrOrderNotMatching
+	}
+
+	busy, err := agentBusy(ctx, tx, c.AgentID)
+	if err != nil {
+		p.Logger.Error("failed to check agent", zap.Error(err))
+		return nil, err
+	}
+	if busy {
+		return nil, ErrAgentBusy
+	}
+
+	var waiting int
+	if err := tx.QueryRow(ctx, `
+	SELECT COUNT(*) FROM order_candidates WHERE order_id = $1 AND status = 'waiting'
+	`, c.OrderID).Scan(&waiting); err != nil {
+		p.Logger.Error("failed to count candidates", zap.Error(err))
+		return nil, fmt.Errorf("failed to count candidates: %w", err)
+	}
+	if waiting >= maxCandidates {
+		return nil, ErrQueueFull
+	}
+
+	// Ушедший или отпущенный исполнитель может встать в очередь снова
+	err = tx.QueryRow(ctx, `
+	INSERT INTO order_candidates (order_id, agent_id, comment)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (order_id, agent_id) DO UPDATE
+	SET comment = EXCLUDED.comment, status = 'waiting', joined_at = NOW(), updated_at = NOW()
+	WHERE order_candidates.status IN ('left', 'released')
+	RETURNING status, joined_at
+	`, c.OrderID, c.AgentID, c.Comment).Scan(&c.Status, &c.JoinedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAlreadyInQueue
+	}
+	if err != nil {
+		p.Logger.Error("failed to join order queue", zap.Error(err))
+		return nil, fmt.Errorf("failed to join order queue: %w", err)
+	}
+
+	if order.OrderStatus == "pending" {
+		order, err = scanOrder(tx.QueryRow(ctx, `
+		UPDATE orders
+		SET order_status = 'matching', matching_deadline = NOW() + $2::interval,
+			updated_at = NOW(), version = version + 1
+		WHERE order_id = $1
+		RETURNING`+orderColumns, c.OrderID, window))
+		if err != nil {
+			p.Logger.Error("failed to start matching", zap.Error(err))
+			return nil, fmt.Errorf("failed to start matching: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.Logger.Error("failed to commit order queue", zap.Error(err))
+		return nil, fmt.Errorf("failed to commit order queue: %w", err)
+	}
+
+	return order, nil
+}
+
+// LeaveOrderQueue убирает исполнителя из очереди на заказ. Если в очереди
+// больше никого нет, заказ возвращается в pending. Возвращает заказ в новом
+// состоянии или ErrNotInQueue.
+func (p *PostgresDB) LeaveOrderQueue(ctx context.Context, orderID, agentID uuid.UUID) (*infra.Order, error) {
+	tx, err := p.Db.Begin(ctx)
+	if err != nil {
+		p.Logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	order, err := p.lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	tag, err := tx.Exec(ctx, `
+	UPDATE order_candidates
+	SET status = 'left', updated_at = NOW()
+	WHERE order_id = $1 AND agent_id = $2 AND status = 'waiting'
+	`, orderID, agentID)
+	if err != nil {
+		p.Logger.Error("failed to leave order queue", zap.Error(err))
+		return nil, fmt.Errorf("failed to leave order queue: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrNotInQueue
+	}
+
+	if order.OrderStatus == "matching" {
+		order, err = p.stopMatchingIfEmpty(ctx, tx, order)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.Logger.Error("failed to commit order queue", zap.Error(err))
+		return nil, fmt.Errorf("failed to commit order queue: %w", err)
+	}
+
+	return order, nil
+}
+
+// stopMatchingIfEmpty возвращает заказ в pending, если в его очереди никого не осталось.
+func (p *PostgresDB) stopMatchingIfEmpty(ctx context.Context, tx pgx.Tx, order *infra.Order) (*infra.Order, error) {
+	updated, err := scanOrder(tx.QueryRow(ctx, `
+	UPDATE orders
+	SET order_status = 'pending', matching_deadline = NULL, updated_at = NOW(), version = version + 1
+	WHERE order_id = $1
+	AND NOT EXISTS (SELECT 1 FROM order_candidates WHERE order_id = $1 AND status = 'waiting')
+	RETURNING`+orderColumns, order.OrderID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return order, nil
+	}
+	if err != nil {
+		p.Logger.Error("failed to stop matching", zap.Error(err))
+		return nil, fmt.Errorf("failed to stop matching: %w", err)
+	}
+	return updated, nil
+}
+
+// ListCandidates возвращает исполнителей, которые ждут решения по заказу,
+// в порядке заявок.
+func (p *PostgresDB) ListCandidates(ctx context.Context, orderID uuid.UUID) ([]*infra.Candidate, error) {
+	rows, err := p.Db.Query(ctx, `
+	SELECT order_id, agent_id, comment, status, joined_at
+	FROM order_candidates
+	WHERE order_id = $1 AND status = 'waiting'
+	ORDER BY joined_at
+	`, orderID)
+	if err != nil {
+		p.Logger.Error("failed to get candidates", zap.Error(err))
+		return nil, fmt.Errorf("failed to get candidates: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := []*infra.Candidate{}
+	for rows.Next() {
+		var c infra.Candidate
+		if err := rows.Scan(&c.OrderID, &c.AgentID, &c.Comment, &c.Status, &c.JoinedAt); err != nil {
+			p.Logger.Error("failed to scan candidate", zap.Error(err))
+			return nil, fmt.Errorf("failed to scan candidate: %w", err)
+		}
+		candidates = append(candidates, &c)
+	}
+
+	if err := rows.Err(); err != nil {
+		p.Logger.Error("failed to iterate over candidates", zap.Error(err))
+		return nil, fmt.Errorf("failed to iterate over candidates: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// SelectAgent назначает заказу исполнителя agentID из очереди и отпускает
+// остальных. Если version не 0, заказ должен быть всё ещё в этой версии
+// (иначе ErrVersionConflict). События, которые строит events, записываются
+// в той же транзакции. Возвращает заказ в новом состоянии и отпущенных
+// исполнителей.
+func (p *PostgresDB) SelectAgent(ctx context.Context, orderID, agentID uuid.UUID, version int64, events MatchEvents) (*infra.Order, []uuid.UUID, error) {
+	tx, err := p.Db.Begin(ctx)
+	if err != nil {
+		p.Logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	order, err := p.lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if version != 0 && order.Version != version {
+		return nil, nil, ErrVersionConflict
+	}
+	if order.OrderStatus != "matching" {
+		return nil, nil, ErrOrderNotMatching
+	}
+
+	order, released, err := p.assignCandidate(ctx, tx, orderID, agentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	built, err := events(order, released)
+	if err != nil {
+		return nil, nil, fmt.Errorf("build events: %w", err)
+	}
+	if err := p.saveEvents(ctx, tx, built); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.Logger.Error("failed to commit agent selection", zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to commit agent selection: %w", err)
+	}
+
+	return order, released, nil
+}
+
+// assignCandidate отмечает исполнителя выбранным, остальных ожидающих —
+// отпущенными и назначает исполнителя заказу.
+func (p *PostgresDB) assignCandidate(ctx context.Context, tx pgx.Tx, orderID, agentID uuid.UUID) (*infra.Order, []uuid.UUID, error) {
+	tag, err := tx.Exec(ctx, `
+	UPDATE order_candidates
+	SET status = 'selected', updated_at = NOW()
+	WHERE order_id = $1 AND agent_id = $2 AND status = 'waiting'
+	`, orderID, agentID)
+	if err != nil {
+		p.Logger.Error("failed to select candidate", zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to select candidate: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, nil, ErrNotInQueue
+	}
+
+	released, err := p.releaseCandidates(ctx, tx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	order, err := scanOrder(tx.QueryRow(ctx, `
+	UPDATE orders
+	SET order_status = 'signed', agent_id = $2, matching_deadline = NULL,
+		updated_at = NOW(), version = version + 1
+	WHERE order_id = $1
+	RETURNING`+orderColumns, orderID, agentID))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == agentSignedIndex {
+		return nil, nil, ErrAgentBusy
+	}
+	if err != nil {
+		p.Logger.Error("failed to assign agent", zap.Error(err))
+		return nil, nil, fmt.Errorf("failed to assign agent: %w", err)
+	}
+
+	return order, released, nil
+}
+
+// releaseCandidates отпускает всех ожидающих исполнителей заказа и возвращает их.
+func (p *PostgresDB) releaseCandidates(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := tx.Query(ctx, `
+	UPDATE order_candidates
+	SET status = 'released', updated_at = NOW()
+	WHERE order_id = $1 AND status = 'waiting'
+	RETURNING agent_id
+	`, orderID)
+	if err != nil {
+		p.Logger.Error("failed to release candidates", zap.Error(err))
+		return nil, fmt.Errorf("failed to release candidates: %w", err)
+	}
+
+	released, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		p.Logger.Error("failed to release candidates", zap.Error(err))
+		return nil, fmt.Errorf("failed to release candidates: %w", err)
+	}
+	return released, nil
+}
+
+// CloseOverdueMatching разбирает до limit заказов, у которых истекло время
+// очереди: каждому назначается вставший раньше всех свободный исполнитель,
+// остальные отпускаются. Если свободных нет, заказ возвращается в pending.
+//
+// Как и ExpireOverdueOrders, заказы блокируются FOR UPDATE SKIP LOCKED, а
+// события, которые events строит по заказу в новом состоянии и отпущенным
+// исполнителям, записываются в той же транзакции. Возвращает число
+// разобранных заказов.
+func (p *PostgresDB) CloseOverdueMatching(ctx context.Context, limit int, events MatchEvents) (int, error) {
+	tx, err := p.Db.Begin(ctx)
+	if err != nil {
+		p.Logger.Error("failed to begin transaction", zap.Error(err))
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT`+orderColumns+`
+	FROM orders
+	WHERE order_status = 'matching'
+	AND matching_deadline < NOW()
+	ORDER BY matching_deadline
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		p.Logger.Error("failed to get overdue matching orders", zap.Error(err))
+		return 0, fmt.Errorf("failed to get overdue matching orders: %w", err)
+	}
+	orders, err := p.collectOrders(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	// Исполнитель, назначенный на заказ в этой пачке, уже занят для следующих
+	assigned := map[uuid.UUID]bool{}
+	for _, order := range orders {
+		candidates, err := p.waitingCandidates(ctx, tx, order.OrderID)
+		if err != nil {
+			return 0, err
+		}
+
+		agentID := uuid.Nil
+		for _, c := range candidates {
+			if !c.busy && !assigned[c.agentID] {
+				agentID = c.agentID
+				break
+			}
+		}
+
+		var released []uuid.UUID
+		if agentID != uuid.Nil {
+			if order, released, err = p.assignCandidate(ctx, tx, order.OrderID, agentID); err != nil {
+				return 0, err
+			}
+			assigned[agentID] = true
+		} else {
+			if released, err = p.releaseCandidates(ctx, tx, order.OrderID); err != nil {
+				return 0, err
+			}
+			if order, err = p.stopMatchingIfEmpty(ctx, tx, order); err != nil {
+				return 0, err
+			}
+		}
+
+		built, err := events(order, released)
+		if err != nil {
+			return 0, fmt.Errorf("build events: %w", err)
+		}
+		if err := p.saveEvents(ctx, tx, built); err != nil {
+			return 0, err
+		}
+		closed++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.Logger.Error("failed to commit matching", zap.Error(err))
+		return 0, fmt.Errorf("failed to commit matching: %w", err)
+	}
+
+	return closed, nil
+}
+
+// waitingCandidate — исполнитель в очереди; busy — он уже выполняет другой заказ.
+type waitingCandidate struct {
+	agentID uuid.UUID
+	busy    bool
+}
+
+// waitingCandidates возвращает ожидающих исполнителей заказа в порядке заявок.
+func (p *PostgresDB) waitingCandidates(ctx context.Context, tx pgx.Tx, orderID uuid.UUID) ([]waitingCandidate, error) {
+	rows, err := tx.Query(ctx, `
+	SELECT
+		c.agent_id,
+		EXISTS (SELECT 1 FROM orders o WHERE o.agent_id = c.agent_id AND o.order_status = 'signed')
+	FROM order_candidates c
+	WHERE c.order_id = $1 AND c.status = 'waiting'
+	ORDER BY c.joined_at
+	`, orderID)
+	if err != nil {
+		p.Logger.Error("failed to get candidates", zap.Error(err))
+		return nil, fmt.Errorf("failed to get candidates: %w", err)
+	}
+
+	candidates, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (waitingCandidate, error) {
+		var c waitingCandidate
+		err := row.Scan(&c.agentID, &c.busy)
+		return c, err
+	})
+	if err != nil {
+		p.Logger.Error("failed to scan candidate", zap.Error(err))
+		return nil, fmt.Errorf("failed to scan candidate: %w", err)
+	}
+	return candidates, nil
+}
@@ -2,13 +2,14 @@ package database
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
 	"order_service/internal/config"
 	"order_service/internal/infra"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/google/uuid"
@@ -54,40 +55,34 @@ func (p *PostgresDB) Close() error {
 	return nil
 }
 
+// GetCurrentOrder возвращает активный заказ пользователя или ErrNoActiveOrder.
 func (p *PostgresDB) GetCurrentOrder(ctx context.Context, userID uuid.UUID) (*infra.Order, error) {
-	query := `
-	SELECT
-		order_id,
-		user_id,
-		agent_id,
-		order_address,
-		order_location,
-		order_date,
-		order_time_gap,
-		order_status
+	order, err := scanOrder(p.Db.QueryRow(ctx, `SELECT`+orderColumns+`
 	FROM orders
 	WHERE user_id = $1
 	AND (order_status = 'pending' OR order_status = 'matching' OR order_status = 'signed')
-	`
-	var order infra.Order
-	if err := p.Db.QueryRow(ctx, query, userID).Scan(&order.OrderID,
-		&order.UserID,
-		&order.AgentID,
-		&order.OrderAddress,
-		&order.OrderLocation,
-		&order.OrderDate,
-		&order.OrderTimeGap,
-		&order.OrderStatus,
-	); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("no active order found")
+	`, userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNoActiveOrder
 		}
 		return nil, fmt.Errorf("failed to get current order: %w", err)
 	}
-	return &order, nil
+	return order, nil
 }
 
-func (p *PostgresDB) CreateOrder(ctx context.Context, order *infra.Order) error {
+// CreateOrder создаёт заказ и записывает события, которые строит events,
+// в той же транзакции. Если у пользователя уже есть заказ с тем же
+// ключом идемпотентности, заказ не создаётся и возвращается ErrIdempotencyKeyExists;
+// если есть другой активный заказ — ErrActiveOrderExists.
+func (p *PostgresDB) CreateOrder(ctx context.Context, order *infra.Order, events OrderEvents) error {
+	tx, err := p.Db.Begin(ctx)
+	if err != nil {
+		p.Logger.Error("failed to begin transaction", zap.Error(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
 	INSERT INTO orders (
 		user_id, 
@@ -96,12 +91,17 @@ func (p *PostgresDB) CreateOrder(ctx context.Context, order *infra.Order) error
 		order_location, 
 		order_date, 
 		order_time_gap, 
-		order_status
-	) VALUES ($1, $2, $3, $4, $5, $6, $7)
-	RETURNING order_id
+		order_status,
+		idempotency_key,
+		notes,
+		latitude,
+		longitude
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10, $11)
+	ON CONFLICT (user_id, idempotency_key) WHERE idempotency_key IS NOT NULL DO NOTHING
+	RETURNING order_id, order_status, created_at, updated_at, version
 	`
 
-	err := p.Db.QueryRow(
+	err = tx.QueryRow(
 		ctx,
 		query,
 		order.UserID,
@@ -111,171 +111,147 @@ func (p *PostgresDB) CreateOrder(ctx context.Context, order *infra.Order) error
 		order.OrderDate,
 		order.OrderTimeGap,
 		order.OrderStatus,
-	).Scan(&order.OrderID)
+		order.IdempotencyKey,
+		order.Notes,
+		order.Latitude,
+		order.Longitude,
+	).Scan(&order.OrderID, &order.OrderStatus, &order.CreatedAt, &order.UpdatedAt, &order.Version)
 
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrIdempotencyKeyExists
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == activeOrderIndex {
+		return ErrActiveOrderExists
+	}
 	if err != nil {
 		p.Logger.Error("failed to create order", zap.Error(err))
 		return fmt.Errorf("failed to create order: %w", err)
 	}
 
+	built, err := events(order)
+	if err != nil {
+		return fmt.Errorf("build events: %w", err)
+	}
+	if err := p.saveEvents(ctx, tx, built); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.Logger.Error("failed to commit order", zap.Error(err))
+		return fmt.Errorf("failed to commit order: %w", err)
+	}
+
 	return nil
 }
 
+// GetOrderByIdempotencyKey возвращает заказ пользователя, созданный с ключом key,
+// или nil, если такого нет.
+func (p *PostgresDB) GetOrderByIdempotencyKey(ctx context.Context, userID uuid.UUID, key string) (*infra.Order, error) {
+	order, err := scanOrder(p.Db.QueryRow(ctx, `SELECT`+orderColumns+`
+	FROM orders
+	WHERE user_id = $1 AND idempotency_key = $2
+	`, userID, key))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		p.Logger.Error("failed to get order by idempotency key", zap.Error(err))
+		return nil, fmt.Errorf("failed to get order by idempotency key: %w", err)
+	}
+	order.IdempotencyKey = key
+
+	return order, nil
+}
+
 func (p *PostgresDB) GetUserOrders(ctx context.Context, userID uuid.UUID) ([]*infra.Order, error) {
-	query := `
-	SELECT
-		order_id,
-		user_id,
-		agent_id,
-		order_address,
-		order_location,
-		order_date,
-		order_time_gap,
-		order_status
+	rows, err := p.Db.Query(ctx, `SELECT`+orderColumns+`
 	FROM orders
 	WHERE user_id = $1
-	`
-	rows, err := p.Db.Query(ctx, query, userID)
+	`, userID)
 	if err != nil {
 		p.Logger.Error("failed to get orders", zap.Error(err))
 		return nil, fmt.Errorf("failed to get orders: %w", err)
 	}
-	defer rows.Close()
-
-	orders := []*infra.Order{}
-	for rows.Next() {
-		var order infra.Order
-		if err := rows.Scan(&order.OrderID,
-			&order.UserID,
-			&order.AgentID,
-			&order.OrderAddress,
-			&order.OrderLocation,
-			&order.OrderDate,
-			&order.OrderTimeGap,
-			&order.OrderStatus,
-		); err != nil {
-			p.Logger.Error("failed to scan order", zap.Error(err))
-			return nil, fmt.Errorf("failed to scan order: %w", err)
-		}
-		orders = append(orders, &order)
-	}
-
-	if err := rows.Err(); err != nil {
-		p.Logger.Error("failed to iterate over orders", zap.Error(err))
-		return nil, fmt.Errorf("failed to iterate over orders: %w", err)
-	}
-
-	return orders, nil
+	return p.collectOrders(rows)
 }
 
 func (p *PostgresDB) GetAvailableOrders(ctx context.Context) ([]*infra.Order, error) {
-	query := `
-	SELECT
-		order_id,
-		user_id,
-		agent_id,
-		order_address,
-		order_location,
-		order_date,
-		order_time_gap,
-		order_status
+	rows, err := p.Db.Query(ctx, `SELECT`+orderColumns+`
 	FROM orders
 	WHERE order_status = 'pending'
-	`
-	rows, err := p.Db.Query(ctx, query)
+	OR (order_status = 'matching' AND matching_deadline > NOW())
+	`)
 	if err != nil {
 		p.Logger.Error("failed to get available orders", zap.Error(err))
 		return nil, fmt.Errorf("failed to get available orders: %w", err)
 	}
-	defer rows.Close()
-
-	orders := []*infra.Order{}
-	for rows.Next() {
-		var order infra.Order
-		if err := rows.Scan(&order.OrderID,
-			&order.UserID,
-			&order.AgentID,
-			&order.OrderAddress,
-			&order.OrderLocation,
-			&order.OrderDate,
-			&order.OrderTimeGap,
-			&order.OrderStatus,
-		); err != nil {
-			p.Logger.Error("failed to scan order", zap.Error(err))
-			return nil, fmt.Errorf("failed to scan order: %w", err)
-		}
-		orders = append(orders, &order)
-	}
+	return p.collectOrders(rows)
+}
 
-	if err := rows.Err(); err != nil {
-		p.Logger.Error("failed to iterate over orders", zap.Error(err))
-		return nil, fmt.Errorf("failed to iterate over orders: %w", err)
+// collectOrders читает заказы из rows и закрывает их.
+func (p *PostgresDB) collectOrders(rows pgx.Rows) ([]*infra.Order, error) {
+	orders, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*infra.Order, error) {
+		return scanOrder(row)
+	})
+	if err != nil {
+		p.Logger.Error("failed to scan order", zap.Error(err))
+		return nil, fmt.Errorf("failed to scan order: %w", err)
 	}
-
 	return orders, nil
 }
 
 func (p *PostgresDB) GetOrderById(ctx context.Context, orderID uuid.UUID) (*infra.Order, error) {
-	query := `
-	SELECT
-		order_id,
-		user_id,
-		agent_id,
-		order_address,
-		order_location,
-		order_date,
-		order_time_gap,
-		order_status
+	order, err := scanOrder(p.Db.QueryRow(ctx, `SELECT`+orderColumns+`
 	FROM orders
 	WHERE order_id = $1
-	`
-	var order infra.Order
-	if err := p.Db.QueryRow(ctx, query, orderID).Scan(&order.OrderID,
-		&order.UserID,
-		&order.AgentID,
-		&order.OrderAddress,
-		&order.OrderLocation,
-		&order.OrderDate,
-		&order.OrderTimeGap,
-		&order.OrderStatus,
-	); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("order not found")
+	`, orderID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOrderNotFound
 		}
 		return nil, fmt.Errorf("failed to get order by id: %w", err)
 	}
 
-	return &order, nil
+	return order, nil
 }
 
-func (p *PostgresDB) CancelOrder(ctx context.Context, orderID uuid.UUID) error {
-	query := `
-	UPDATE orders
-	SET order_status = 'cancelled'
-	WHERE order_id = $1
-	`
-
-	_, err := p.Db.Exec(ctx, query, orderID)
+// CompleteOrder завершает заказ, если его версия всё ещё равна version, и
+// возвращает заказ в новом состоянии; иначе возвращается ErrVersionConflict.
+// События, которые строит events, записываются в той же транзакции.
+func (p *PostgresDB) CompleteOrder(ctx context.Context, orderID uuid.UUID, version int64, events OrderEvents) (*infra.Order, error) {
+	tx, err := p.Db.Begin(ctx)
 	if err != nil {
-		p.Logger.Error("failed to cancel order", zap.Error(err))
-		return fmt.Errorf("failed to cancel order: %w", err)
+		p.Logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback(ctx)
 
-	return nil
-}
-
-func (p *PostgresDB) CompleteOrder(ctx context.Context, orderID uuid.UUID) error {
-	query := `
+	order, err := scanOrder(tx.QueryRow(ctx, `
 	UPDATE orders
-	SET order_status = 'completed'
-	WHERE order_id = $1
-	`
-
-	_, err := p.Db.Exec(ctx, query, orderID)
+	SET order_status = 'completed', updated_at = NOW(), version = version + 1
+	WHERE order_id = $1 AND version = $2
+	RETURNING`+orderColumns, orderID, version))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrVersionConflict
+	}
 	if err != nil {
 		p.Logger.Error("failed to complete order", zap.Error(err))
-		return fmt.Errorf("failed to complete order: %w", err)
+		return nil, fmt.Errorf("failed to complete order: %w", err)
 	}
 
-	return nil
+	built, err := events(order)
+	if err != nil {
+		return nil, fmt.Errorf("build events: %w", err)
+	}
+	if err := p.saveEvents(ctx, tx, built); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.Logger.Error("failed to commit completed order", zap.Error(err))
+		return nil, fmt.Errorf("failed to commit completed order: %w", err)
+	}
+
+	return order, nil
 }
@@ -0,0 +1,43 @@
+package database
+
+import "errors"
+
+var (
+	// ErrIdempotencyKeyExists — у пользователя уже есть заказ с этим ключом идемпотентности.
+	ErrIdempotencyKeyExists = errors.New("order with this idempotency key already exists")
+	// ErrActiveOrderExists — у пользователя уже есть активный заказ (idx_orders_user_active).
+	ErrActiveOrderExists = errors.New("user already has an active order")
+	// ErrNoActiveOrder — у пользователя нет активного заказа.
+	ErrNoActiveOrder = errors.New("no active order found")
+	// ErrOrderNotFound — заказа с таким ID нет.
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrVersionConflict — заказ изменили после того, как клиент прочитал его версию.
+	ErrVersionConflict = errors.New("order has been modified by someone else")
+	// ErrOrderNotMatching — заказ не ждёт исполнителя: его уже назначили, отменили или завершили.
+	ErrOrderNotMatching = errors.New("order is not waiting for an agent")
+	// ErrMatchingClosed — время, пока в очередь на заказ можно встать, истекло.
+	ErrMatchingClosed = errors.New("the queue for this order is closed")
+	// ErrQueueFull — в очереди на заказ уже максимум исполнителей.
+	ErrQueueFull = errors.New("the queue for this order is full")
+	// ErrAlreadyInQueue — исполнитель уже стоит в очереди на заказ.
+	ErrAlreadyInQueue = errors.New("agent is already in the queue for this order")
+	// ErrNotInQueue — исполнитель не стоит в очереди на заказ.
+	ErrNotInQueue = errors.New("agent is not in the queue for this order")
+	// ErrAgentBusy — исполнитель уже выполняет другой заказ (idx_orders_agent_signed).
+	ErrAgentBusy = errors.New("agent is busy with another order")
+	// ErrNoOffer — у исполнителя нет открытого предложения этого заказа.
+	ErrNoOffer = errors.New("no open offer of this order for the agent")
+	// ErrOfferExpired — время на ответ на предложение истекло.
+	ErrOfferExpired = errors.New("the offer has expired")
+	// ErrNoLocation — исполнитель не присылал своё место или оно устарело.
+	ErrNoLocation = errors.New("agent location is unknown")
+)
+
+// uniqueViolation — SQLSTATE нарушения уникального индекса.
+const uniqueViolation = "23505"
+
+// activeOrderIndex — частичный уникальный индекс «один активный заказ на клиента».
+const activeOrderIndex = "idx_orders_user_active"
+
+// agentSignedIndex — частичный уникальный индекс «один заказ в работе на исполнителя».
+const agentSignedIndex = "idx_orders_agent_signed"
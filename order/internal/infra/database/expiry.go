@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// ExpireOverdueOrders переводит в expired до limit заказов, которые всё ещё ждут
+// исполнителя, хотя order_date + order_time_gap уже прошёл.
+//
+// Заказы блокируются FOR UPDATE SKIP LOCKED, поэтому реплики сервиса разбирают
+// разные заказы и не ждут друг друга. По каждому заказу в новом состоянии и
+// исполнителям, отпущенным из очереди на него, events строит события, и они
+// записываются в order_events в той же транзакции: если она не
+// зафиксирована, не остаётся ни изменений, ни событий.
+// Возвращает число просроченных заказов.
+func (p *PostgresDB) ExpireOverdueOrders(ctx context.Context, limit int, events MatchEvents) (int, error) {
+	tx, err := p.Db.Begin(ctx)
+	if err != nil {
+		p.Logger.Error("failed to begin transaction", zap.Error(err))
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT`+orderColumns+`
+	FROM orders
+	WHERE order_status IN ('pending', 'matching')
+	AND order_date + order_time_gap < NOW()
+	ORDER BY order_date
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		p.Logger.Error("failed to get overdue orders", zap.Error(err))
+		return 0, fmt.Errorf("failed to get overdue orders: %w", err)
+	}
+	orders, err := p.collectOrders(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, overdue := range orders {
+		order, err := scanOrder(tx.QueryRow(ctx, `
+		UPDATE orders
+		SET order_status = 'expired', matching_deadline = NULL, updated_at = NOW(), version = version + 1
+		WHERE order_id = $1
+		RETURNING`+orderColumns, overdue.OrderID))
+		if err != nil {
+			p.Logger.Error("failed to expire order", zap.Error(err))
+			return 0, fmt.Errorf("failed to expire order: %w", err)
+		}
+		released, err := p.releaseCandidates(ctx, tx, order.OrderID)
+		if err != nil {
+			return 0, err
+		}
+
+		built, err := events(order, released)
+		if err != nil {
+			return 0, fmt.Errorf("build events: %w", err)
+		}
+		if err := p.saveEvents(ctx, tx, built); err != nil {
+			return 0, err
+		}
+		expired++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.Logger.Error("failed to commit expired orders", zap.Error(err))
+		return 0, fmt.Errorf("failed to commit expired orders: %w", err)
+	}
+
+	return expired, nil
+}
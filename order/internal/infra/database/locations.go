@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"order_service/internal/infra"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// SaveAgentLocation запоминает место исполнителя на ttl от момента замера и
+// заполняет location.ExpiresAt. Возвращает false, если уже сохранено место,
+// замеренное позже: точки из разных потоков могут прийти не по порядку.
+func (p *PostgresDB) SaveAgentLocation(ctx context.Context, location *infra.AgentLocation, ttl time.Duration) (bool, error) {
+	err := p.Db.QueryRow(ctx, `
+	INSERT INTO agent_locations (agent_id, latitude, longitude, accuracy_m, speed_kmh, recorded_at, expires_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $6::timestamptz + $7::interval)
+	ON CONFLICT (agent_id) DO UPDATE
+	SET latitude = EXCLUDED.latitude,
+		longitude = EXCLUDED.longitude,
+		accuracy_m = EXCLUDED.accuracy_m,
+		speed_kmh = EXCLUDED.speed_kmh,
+		recorded_at = EXCLUDED.recorded_at,
+		expires_at = EXCLUDED.expires_at
+	WHERE agent_locations.recorded_at < EXCLUDED.recorded_at
+	RETURNING expires_at
+	`, location.AgentID, location.Latitude, location.Longitude, location.AccuracyM, location.SpeedKmh,
+		location.RecordedAt, ttl).Scan(&location.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		p.Logger.Error("failed to save agent location", zap.Error(err))
+		return false, fmt.Errorf("failed to save agent location: %w", err)
+	}
+	return true, nil
+}
+
+// GetAgentLocation возвращает последнее место исполнителя или ErrNoLocation,
+// если оно неизвестно или устарело.
+func (p *PostgresDB) GetAgentLocation(ctx context.Context, agentID uuid.UUID) (*infra.AgentLocation, error) {
+	location := infra.AgentLocation{AgentID: agentID}
+	err := p.Db.QueryRow(ctx, `
+	SELECT latitude, longitude, accuracy_m, speed_kmh, recorded_at, expires_at
+	FROM agent_locations
+	WHERE agent_id = $1 AND expires_at > NOW()
+	`, agentID).Scan(&location.Latitude,
+		&location.Longitude,
+		&location.AccuracyM,
+		&location.SpeedKmh,
+		&location.RecordedAt,
+		&location.ExpiresAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNoLocation
+	}
+	if err != nil {
+		p.Logger.Error("failed to get agent location", zap.Error(err))
+		return nil, fmt.Errorf("failed to get agent location: %w", err)
+	}
+	return &location, nil
+}
+
+// ClaimLocationPush отмечает, что место исполнителя отправляется владельцу
+// заказа. Возвращает false, если с прошлой отправки не прошло interval: тогда
+// отправлять не нужно. Так места не уходят чаще, даже если исполнитель
+// присылает их в нескольких потоках или через разные реплики.
+func (p *PostgresDB) ClaimLocationPush(ctx context.Context, agentID uuid.UUID, interval time.Duration) (bool, error) {
+	tag, err := p.Db.Exec(ctx, `
+	UPDATE agent_locations SET pushed_at = NOW()
+	WHERE agent_id = $1 AND (pushed_at IS NULL OR pushed_at <= NOW() - $2::interval)
+	`, agentID, interval)
+	if err != nil {
+		p.Logger.Error("failed to claim location push", zap.Error(err))
+		return false, fmt.Errorf("failed to claim location push: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetAgentOrder возвращает заказ, который исполнитель выполняет, или ErrNoActiveOrder.
+func (p *PostgresDB) GetAgentOrder(ctx context.Context, agentID uuid.UUID) (*infra.Order, error) {
+	order, err := scanOrder(p.Db.QueryRow(ctx, `SELECT`+orderColumns+`
+	FROM orders
+	WHERE agent_id = $1 AND order_status = 'signed'
+	`, agentID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNoActiveOrder
+	}
+	if err != nil {
+		p.Logger.Error("failed to get agent order", zap.Error(err))
+		return nil, fmt.Errorf("failed to get agent order: %w", err)
+	}
+	return order, nil
+}
+
+// DeleteExpiredLocations удаляет до limit устаревших мест исполнителей.
+// Возвращает число удалённых.
+func (p *PostgresDB) DeleteExpiredLocations(ctx context.Context, limit int) (int, error) {
+	tag, err := p.Db.Exec(ctx, `
+	DELETE FROM agent_locations
+	WHERE agent_id IN (
+		SELECT agent_id FROM agent_locations
+		WHERE expires_at <= NOW()
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	`, limit)
+	if err != nil {
+		p.Logger.Error("failed to delete expired locations", zap.Error(err))
+		return 0, fmt.Errorf("failed to delete expired locations: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,386 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"order_service/internal/config"
+	"order_service/internal/infra"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+// DispatchOrders предлагает до limit ожидающих исполнителя заказов
+// лучшим по infra.RankAgents свободным исполнителям на линии. Заказ
+// предлагается, только если у него нет открытого предложения, и каждому
+// исполнителю — не больше одного раза. Если orderID не nil, разбирается
+// только этот заказ.
+//
+// Как и CloseOverdueMatching, заказы блокируются FOR UPDATE SKIP LOCKED, а
+// события, которые events строит по записанному предложению, сохраняются в
+// той же транзакции. Возвращает число предложений.
+func (p *PostgresDB) DispatchOrders(ctx context.Context, orderID *uuid.UUID, limit int, cfg *config.Dispatch, events OfferEvents) (int, error) {
+	tx, err := p.Db.Begin(ctx)
+	if err != nil {
+		p.Logger.Error("failed to begin transaction", zap.Error(err))
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT`+orderColumns+`
+	FROM orders o
+	WHERE order_status = 'pending'
+	AND ($1::uuid IS NULL OR order_id = $1)
+	AND order_date + order_time_gap > NOW()
+	AND NOT EXISTS (SELECT 1 FROM order_offers f WHERE f.order_id = o.order_id AND f.status = 'offered')
+	ORDER BY created_at
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+	`, orderID, limit)
+	if err != nil {
+		p.Logger.Error("failed to get orders to dispatch", zap.Error(err))
+		return 0, fmt.Errorf("failed to get orders to dispatch: %w", err)
+	}
+	orders, err := p.collectOrders(rows)
+	if err != nil {
+		return 0, err
+	}
+
+	weights := infra.DispatchWeights{
+		MaxDistanceKm: cfg.MaxDistanceKm,
+		RatingKm:      cfg.RatingWeightKm,
+		LoadKm:        cfg.LoadWeightKm,
+	}
+
+	offered := 0
+	for _, order := range orders {
+		agents, err := p.agentCandidates(ctx, tx, order, cfg)
+		if err != nil {
+			return 0, err
+		}
+
+		offer, err := p.offerOrder(ctx, tx, order, infra.RankAgents(order, agents, weights), cfg.OfferTimeout)
+		if err != nil {
+			return 0, err
+		}
+		// Никто из исполнителей на линии не подходит: заказ подождёт следующего раза
+		if offer == nil {
+			continue
+		}
+
+		built, err := events(offer)
+		if err != nil {
+			return 0, fmt.Errorf("build events: %w", err)
+		}
+		if err := p.saveEvents(ctx, tx, built); err != nil {
+			return 0, err
+		}
+		offered++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.Logger.Error("failed to commit dispatch", zap.Error(err))
+		return 0, fmt.Errorf("failed to commit dispatch: %w", err)
+	}
+
+	return offered, nil
+}
+
+// offerOrder записывает предложение заказа первому из ranked, у кого ещё нет
+// открытого предложения. Возвращает nil, если таких нет.
+func (p *PostgresDB) offerOrder(ctx context.Context, tx pgx.Tx, order *infra.Order, ranked []*infra.AgentCandidate, timeout time.Duration) (*infra.Offer, error) {
+	for _, a := range ranked {
+		offer := &infra.Offer{OrderID: order.OrderID, AgentID: a.AgentID, Score: a.Score, Order: order}
+		// Другая реплика могла только что предложить исполнителю другой заказ
+		// (idx_order_offers_open_agent): тогда пробуем следующего
+		err := tx.QueryRow(ctx, `
+		INSERT INTO order_offers (order_id, agent_id, score, expires_at)
+		VALUES ($1, $2, $3, NOW() + $4::interval)
+		ON CONFLICT DO NOTHING
+		RETURNING status, offered_at, expires_at
+		`, offer.OrderID, offer.AgentID, offer.Score, timeout).Scan(&offer.Status, &offer.OfferedAt, &offer.ExpiresAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			p.Logger.Error("failed to create offer", zap.Error(err))
+			return nil, fmt.Errorf("failed to create offer: %w", err)
+		}
+		return offer, nil
+	}
+	return nil, nil
+}
+
+// agentCandidates возвращает свободных исполнителей, которые ищут заказы на
+// время order_date, которым заказ ещё не предлагали и у которых нет другого
+// открытого предложения. Радиус исполнителя проверяет infra.RankAgents.
+//
+// Рейтинг — доля завершённых заказов среди завершённых и брошенных
+// исполнителем, со сглаживанием: у новичка он равен 1. Нагрузка — заказы,
+// завершённые за cfg.LoadWindow, и очереди, в которых исполнитель стоит.
+func (p *PostgresDB) agentCandidates(ctx context.Context, tx pgx.Tx, order *infra.Order, cfg *config.Dispatch) ([]*infra.AgentCandidate, error) {
+	rows, err := tx.Query(ctx, `
+	SELECT
+		a.agent_id,
+		a.latitude,
+		a.longitude,
+		a.radius_km,
+		(1 + done.n)::float8 / (1 + done.n + dropped.n),
+		recent.n + queued.n
+	FROM agents a
+	CROSS JOIN LATERAL (
+		SELECT COUNT(*) AS n FROM orders o WHERE o.agent_id = a.agent_id AND o.order_status = 'completed'
+	) done
+	CROSS JOIN LATERAL (
+		SELECT COUNT(*) AS n FROM order_cancellations c WHERE c.actor_id = a.agent_id AND c.cancelled_by = 'agent'
+	) dropped
+	CROSS JOIN LATERAL (
+		SELECT COUNT(*) AS n FROM orders o
+		WHERE o.agent_id = a.agent_id AND o.order_status = 'completed' AND o.updated_at > NOW() - $3::interval
+	) recent
+	CROSS JOIN LATERAL (
+		SELECT COUNT(*) AS n FROM order_candidates c WHERE c.agent_id = a.agent_id AND c.status = 'waiting'
+	) queued
+	WHERE a.status = 'searching'
+	AND (a.available_from IS NULL OR a.available_from <= $2)
+	AND (a.available_until IS NULL OR a.available_until >= $2)
+	AND NOT EXISTS (SELECT 1 FROM orders o WHERE o.agent_id = a.agent_id AND o.order_status = 'signed')
+	AND NOT EXISTS (
+		SELECT 1 FROM order_offers f
+		WHERE f.agent_id = a.agent_id AND (f.status = 'offered' OR f.order_id = $1)
+	)
+	`, order.OrderID, order.OrderDate, cfg.LoadWindow)
+	if err != nil {
+		p.Logger.Error("failed to get agent candidates", zap.Error(err))
+		return nil, fmt.Errorf("failed to get agent candidates: %w", err)
+	}
+
+	agents, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*infra.AgentCandidate, error) {
+		var a infra.AgentCandidate
+		err := row.Scan(&a.AgentID, &a.Latitude, &a.Longitude, &a.RadiusKm, &a.Rating, &a.Load)
+		return &a, err
+	})
+	if err != nil {
+		p.Logger.Error("failed to scan agent candidate", zap.Error(err))
+		return nil, fmt.Errorf("failed to scan agent candidate: %w", err)
+	}
+	return agents, nil
+}
+
+// offerColumns — поля предложения в порядке scanOffer.
+const offerColumns = `
+		order_id,
+		agent_id,
+		status,
+		score,
+		offered_at,
+		expires_at`
+
+func scanOffer(row pgx.Row) (*infra.Offer, error) {
+	var offer infra.Offer
+	err := row.Scan(&offer.OrderID,
+		&offer.AgentID,
+		&offer.Status,
+		&offer.Score,
+		&offer.OfferedAt,
+		&offer.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// CloseStaleOffers закрывает до limit открытых предложений: просроченные
+// становятся expired, а предложения заказов, которые уже не ждут исполнителя,
+// и исполнителям, которые перестали искать заказы, — withdrawn. Как и в
+// CloseOverdueMatching, события, которые events строит по предложению в
+// новом статусе, записываются в той же транзакции. Возвращает число
+// закрытых предложений.
+func (p *PostgresDB) CloseStaleOffers(ctx context.Context, limit int, events OfferEvents) (int, error) {
+	tx, err := p.Db.Begin(ctx)
+	if err != nil {
+		p.Logger.Error("failed to begin transaction", zap.Error(err))
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT`+offerColumns+`
+	FROM order_offers f
+	WHERE status = 'offered'
+	AND (expires_at < NOW()
+		OR NOT EXISTS (SELECT 1 FROM orders o WHERE o.order_id = f.order_id AND o.order_status = 'pending')
+		OR NOT EXISTS (SELECT 1 FROM agents a WHERE a.agent_id = f.agent_id AND a.status = 'searching'))
+	ORDER BY expires_at
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED
+	`, limit)
+	if err != nil {
+		p.Logger.Error("failed to get stale offers", zap.Error(err))
+		return 0, fmt.Errorf("failed to get stale offers: %w", err)
+	}
+	offers, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*infra.Offer, error) {
+		return scanOffer(row)
+	})
+	if err != nil {
+		p.Logger.Error("failed to scan offer", zap.Error(err))
+		return 0, fmt.Errorf("failed to scan offer: %w", err)
+	}
+
+	closed := 0
+	for _, offer := range offers {
+		offer.Order, err = scanOrder(tx.QueryRow(ctx, `SELECT`+orderColumns+`
+		FROM orders
+		WHERE order_id = $1
+		`, offer.OrderID))
+		if err != nil {
+			p.Logger.Error("failed to get offered order", zap.Error(err))
+			return 0, fmt.Errorf("failed to get offered order: %w", err)
+		}
+
+		// Время на ответ ещё не вышло — значит, заказ или исполнитель уже не ищут друг друга
+		offer.Status = infra.OfferExpired
+		if offer.Order.OrderStatus != "pending" || time.Now().Before(offer.ExpiresAt) {
+			offer.Status = infra.OfferWithdrawn
+		}
+		if _, err := tx.Exec(ctx, `
+		UPDATE order_offers SET status = $3 WHERE order_id = $1 AND agent_id = $2
+		`, offer.OrderID, offer.AgentID, offer.Status); err != nil {
+			p.Logger.Error("failed to close offer", zap.Error(err))
+			return 0, fmt.Errorf("failed to close offer: %w", err)
+		}
+
+		built, err := events(offer)
+		if err != nil {
+			return 0, fmt.Errorf("build events: %w", err)
+		}
+		if err := p.saveEvents(ctx, tx, built); err != nil {
+			return 0, err
+		}
+		closed++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.Logger.Error("failed to commit offers", zap.Error(err))
+		return 0, fmt.Errorf("failed to commit offers: %w", err)
+	}
+
+	return closed, nil
+}
+
+// GetOpenOffer возвращает открытое предложение исполнителю вместе с заказом
+// или ErrNoOffer.
+func (p *PostgresDB) GetOpenOffer(ctx context.Context, agentID uuid.UUID) (*infra.Offer, error) {
+	offer, err := scanOffer(p.Db.QueryRow(ctx, `SELECT`+offerColumns+`
+	FROM order_offers
+	WHERE agent_id = $1 AND status = 'offered' AND expires_at > NOW()
+	`, agentID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNoOffer
+	}
+	if err != nil {
+		p.Logger.Error("failed to get open offer", zap.Error(err))
+		return nil, fmt.Errorf("failed to get open offer: %w", err)
+	}
+
+	if offer.Order, err = p.GetOrderById(ctx, offer.OrderID); err != nil {
+		return nil, err
+	}
+	return offer, nil
+}
+
+// AcceptOffer назначает заказ исполнителю, которому он предложен, и
+// записывает события, которые строит events, в той же транзакции. Возвращает
+// заказ в новом состоянии; ErrNoOffer, если предложения нет или оно закрыто;
+// ErrOfferExpired, если время на ответ вышло.
+func (p *PostgresDB) AcceptOffer(ctx context.Context, orderID, agentID uuid.UUID, events MatchEvents) (*infra.Order, error) {
+	tx, err := p.Db.Begin(ctx)
+	if err != nil {
+		p.Logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	order, err := p.lockOrder(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	offer, err := scanOffer(tx.QueryRow(ctx, `SELECT`+offerColumns+`
+	FROM order_offers
+	WHERE order_id = $1 AND agent_id = $2 AND status = 'offered'
+	FOR UPDATE
+	`, orderID, agentID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNoOffer
+	}
+	if err != nil {
+		p.Logger.Error("failed to get offer", zap.Error(err))
+		return nil, fmt.Errorf("failed to get offer: %w", err)
+	}
+	if !time.Now().Before(offer.ExpiresAt) {
+		return nil, ErrOfferExpired
+	}
+	if order.OrderStatus != "pending" {
+		return nil, ErrOrderNotMatching
+	}
+
+	order, err = scanOrder(tx.QueryRow(ctx, `
+	UPDATE orders
+	SET order_status = 'signed', agent_id = $2, updated_at = NOW(), version = version + 1
+	WHERE order_id = $1
+	RETURNING`+orderColumns, orderID, agentID))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == agentSignedIndex {
+		return nil, ErrAgentBusy
+	}
+	if err != nil {
+		p.Logger.Error("failed to assign agent", zap.Error(err))
+		return nil, fmt.Errorf("failed to assign agent: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+	UPDATE order_offers SET status = 'accepted', responded_at = NOW()
+	WHERE order_id = $1 AND agent_id = $2
+	`, orderID, agentID); err != nil {
+		p.Logger.Error("failed to accept offer", zap.Error(err))
+		return nil, fmt.Errorf("failed to accept offer: %w", err)
+	}
+
+	built, err := events(order, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build events: %w", err)
+	}
+	if err := p.saveEvents(ctx, tx, built); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.Logger.Error("failed to commit offer", zap.Error(err))
+		return nil, fmt.Errorf("failed to commit offer: %w", err)
+	}
+
+	return order, nil
+}
+
+// DeclineOffer отмечает, что исполнитель отказался от предложенного заказа,
+// или возвращает ErrNoOffer.
+func (p *PostgresDB) DeclineOffer(ctx context.Context, orderID, agentID uuid.UUID) error {
+	tag, err := p.Db.Exec(ctx, `
+	UPDATE order_offers SET status = 'declined', responded_at = NOW()
+	WHERE order_id = $1 AND agent_id = $2 AND status = 'offered'
+	`, orderID, agentID)
+	if err != nil {
+		p.Logger.Error("failed to decline offer", zap.Error(err))
+		return fmt.Errorf("failed to decline offer: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNoOffer
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"order_service/internal/infra"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// OrderEvents строит события по заказу в новом состоянии. Они записываются
+// в order_events в той же транзакции, что и изменение заказа.
+type OrderEvents func(order *infra.Order) ([]*infra.OrderEvent, error)
+
+// MatchEvents — как OrderEvents, но ещё получает исполнителей, которых
+// отпустили из очереди на заказ.
+type MatchEvents func(order *infra.Order, released []uuid.UUID) ([]*infra.OrderEvent, error)
+
+// OfferEvents строит события по предложению заказа в новом статусе.
+type OfferEvents func(offer *infra.Offer) ([]*infra.OrderEvent, error)
+
+// orderEventColumns — поля события в порядке scanOrderEvent.
+const orderEventColumns = `
+		event_id,
+		order_id,
+		routing_key,
+		event_type,
+		source,
+		content_type,
+		correlation_id,
+		payload,
+		occurred_at`
+
+func scanOrderEvent(row pgx.Row) (*infra.OrderEvent, error) {
+	var event infra.OrderEvent
+	err := row.Scan(&event.EventID,
+		&event.OrderID,
+		&event.RoutingKey,
+		&event.EventType,
+		&event.Source,
+		&event.ContentType,
+		&event.CorrelationID,
+		&event.Payload,
+		&event.OccurredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// saveEvents записывает неопубликованные события в транзакции tx.
+func (p *PostgresDB) saveEvents(ctx context.Context, tx pgx.Tx, events []*infra.OrderEvent) error {
+	for _, event := range events {
+		_, err := tx.Exec(ctx, `INSERT INTO order_events (`+orderEventColumns+`
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		`,
+			event.EventID,
+			event.OrderID,
+			event.RoutingKey,
+			event.EventType,
+			event.Source,
+			event.ContentType,
+			event.CorrelationID,
+			event.Payload,
+			event.OccurredAt,
+		)
+		if err != nil {
+			p.Logger.Error("failed to save order event", zap.Error(err))
+			return fmt.Errorf("failed to save order event: %w", err)
+		}
+	}
+	return nil
+}
+
+// MarkEventsPublished отмечает события опубликованными.
+func (p *PostgresDB) MarkEventsPublished(ctx context.Context, eventIDs []string) error {
+	_, err := p.Db.Exec(ctx, `
+	UPDATE order_events SET published_at = NOW()
+	WHERE event_id = ANY($1) AND published_at IS NULL
+	`, eventIDs)
+	if err != nil {
+		p.Logger.Error("failed to mark order events published", zap.Error(err))
+		return fmt.Errorf("failed to mark order events published: %w", err)
+	}
+	return nil
+}
+
+// PublishPendingEvents публикует до limit событий, которые записаны раньше
+// чем olderThan назад и всё ещё не опубликованы, в порядке их появления.
+// Более свежие события публикует сам сервис сразу после фиксации изменения.
+//
+// События блокируются FOR UPDATE SKIP LOCKED, поэтому реплики публикуют
+// разные события. Если publish вернул ошибку, обработка останавливается, а
+// это и следующие события останутся до следующего запуска. Если не удастся
+// зафиксировать транзакцию, события уйдут ещё раз с тем же event_id, и
+// потребители отбросят дубли. Возвращает число опубликованных событий.
+func (p *PostgresDB) PublishPendingEvents(ctx context.Context, olderThan time.Duration, limit int, publish func(*infra.OrderEvent) error) (int, error) {
+	tx, err := p.Db.Begin(ctx)
+	if err != nil {
+		p.Logger.Error("failed to begin transaction", zap.Error(err))
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `SELECT`+orderEventColumns+`
+	FROM order_events
+	WHERE published_at IS NULL
+	AND occurred_at < NOW() - $1::interval
+	ORDER BY occurred_at
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+	`, olderThan, limit)
+	if err != nil {
+		p.Logger.Error("failed to get pending order events", zap.Error(err))
+		return 0, fmt.Errorf("failed to get pending order events: %w", err)
+	}
+	events, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*infra.OrderEvent, error) {
+		return scanOrderEvent(row)
+	})
+	if err != nil {
+		p.Logger.Error("failed to scan order event", zap.Error(err))
+		return 0, fmt.Errorf("failed to scan order event: %w", err)
+	}
+
+	published := []string{}
+	var publishErr error
+	for _, event := range events {
+		if publishErr = publish(event); publishErr != nil {
+			break
+		}
+		published = append(published, event.EventID)
+	}
+
+	if _, err := tx.Exec(ctx, `
+	UPDATE order_events SET published_at = NOW() WHERE event_id = ANY($1)
+	`, published); err != nil {
+		p.Logger.Error("failed to mark order events published", zap.Error(err))
+		return 0, fmt.Errorf("failed to mark order events published: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.Logger.Error("failed to commit order events", zap.Error(err))
+		return 0, fmt.Errorf("failed to commit order events: %w", err)
+	}
+
+	if publishErr != nil {
+		return len(published), fmt.Errorf("publish: %w", publishErr)
+	}
+	return len(published), nil
+}
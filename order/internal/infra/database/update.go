@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"order_service/internal/infra"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// updatableColumns — поля заказа, которые можно изменить через UpdateOrder.
+var updatableColumns = map[string]func(*infra.Order) any{
+	"order_address":  func(o *infra.Order) any { return o.OrderAddress },
+	"order_location": func(o *infra.Order) any { return o.OrderLocation },
+	"order_date":     func(o *infra.Order) any { return o.OrderDate },
+	"order_time_gap": func(o *infra.Order) any { return o.OrderTimeGap },
+	"notes":          func(o *infra.Order) any { return o.Notes },
+}
+
+// UpdateOrder записывает поля fields из order, если версия заказа в базе всё ещё
+// равна order.Version, и возвращает заказ в новом состоянии. Если заказ успели
+// изменить, возвращается ErrVersionConflict. События, которые строит events,
+// записываются в той же транзакции.
+func (p *PostgresDB) UpdateOrder(ctx context.Context, order *infra.Order, fields []string, events OrderEvents) (*infra.Order, error) {
+	args := []any{order.OrderID, order.Version}
+	set := make([]string, 0, len(fields)+2)
+	for _, field := range fields {
+		value, ok := updatableColumns[field]
+		if !ok {
+			return nil, fmt.Errorf("field %q can't be updated", field)
+		}
+		args = append(args, value(order))
+		set = append(set, fmt.Sprintf("%s = $%d", field, len(args)))
+	}
+	set = append(set, "updated_at = NOW()", "version = version + 1")
+
+	tx, err := p.Db.Begin(ctx)
+	if err != nil {
+		p.Logger.Error("failed to begin transaction", zap.Error(err))
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	updated, err := scanOrder(tx.QueryRow(ctx, `
+	UPDATE orders
+	SET `+strings.Join(set, ", ")+`
+	WHERE order_id = $1 AND version = $2
+	RETURNING`+orderColumns, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrVersionConflict
+	}
+	if err != nil {
+		p.Logger.Error("failed to update order", zap.Error(err))
+		return nil, fmt.Errorf("failed to update order: %w", err)
+	}
+
+	built, err := events(updated)
+	if err != nil {
+		return nil, fmt.Errorf("build events: %w", err)
+	}
+	if err := p.saveEvents(ctx, tx, built); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		p.Logger.Error("failed to commit updated order", zap.Error(err))
+		return nil, fmt.Errorf("failed to commit updated order: %w", err)
+	}
+
+	return updated, nil
+}
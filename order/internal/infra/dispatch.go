@@ -0,0 +1,104 @@
+package infra
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы предложения заказа исполнителю.
+const (
+	OfferOffered  = "offered"
+	OfferAccepted = "accepted"
+	OfferDeclined = "declined"
+	// OfferExpired — исполнитель не ответил до ExpiresAt
+	OfferExpired = "expired"
+	// OfferWithdrawn — заказ отменили или назначили иначе, пока предложение ждало ответа
+	OfferWithdrawn = "withdrawn"
+)
+
+// Offer — предложение заказа исполнителю при автоматическом назначении.
+type Offer struct {
+	OrderID   uuid.UUID `json:"order_id"`
+	AgentID   uuid.UUID `json:"agent_id"`
+	Status    string    `json:"status"`
+	Score     float64   `json:"score"`
+	OfferedAt time.Time `json:"offered_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Order — заказ в состоянии на момент предложения
+	Order *Order `json:"order,omitempty"`
+}
+
+// AgentCandidate — свободный исполнитель на линии, которому можно предложить заказ.
+type AgentCandidate struct {
+	AgentID   uuid.UUID
+	Latitude  *float64
+	Longitude *float64
+	// Rating — доля доведённых до конца заказов от 0 до 1
+	Rating float64
+	// Load — сколько заказов исполнитель выполнил недавно и в скольких очередях стоит
+	Load int
+	// RadiusKm — радиус, который исполнитель задал при поиске; nil — без ограничения
+	RadiusKm *float64
+	// Score заполняет RankAgents: чем меньше, тем лучше
+	Score float64
+}
+
+// DispatchWeights задают, как RankAgents сравнивает исполнителей. Всё
+// сводится к километрам: рейтинг ниже на 1 весит как RatingKm лишнего пути,
+// каждый заказ нагрузки — как LoadKm.
+type DispatchWeights struct {
+	// MaxDistanceKm — дальше исполнителям заказ не предлагается. Если координаты
+	// заказа или исполнителя неизвестны, расстояние считается равным ему
+	MaxDistanceKm float64
+	RatingKm      float64
+	LoadKm        float64
+}
+
+// RankAgents оценивает исполнителей для заказа и возвращает подходящих от
+// лучшего к худшему.
+func RankAgents(order *Order, agents []*AgentCandidate, w DispatchWeights) []*AgentCandidate {
+	ranked := make([]*AgentCandidate, 0, len(agents))
+	for _, a := range agents {
+		maxDistance := w.MaxDistanceKm
+		if a.RadiusKm != nil && *a.RadiusKm < maxDistance {
+			maxDistance = *a.RadiusKm
+		}
+		distance := w.MaxDistanceKm
+		if order.Latitude != nil && a.Latitude != nil {
+			distance = DistanceKm(*order.Latitude, *order.Longitude, *a.Latitude, *a.Longitude)
+			if distance > maxDistance {
+				continue
+			}
+		}
+		a.Score = distance + (1-a.Rating)*w.RatingKm + float64(a.Load)*w.LoadKm
+		ranked = append(ranked, a)
+	}
+
+	slices.SortStableFunc(ranked, func(a, b *AgentCandidate) int {
+		switch {
+		case a.Score < b.Score:
+			return -1
+		case a.Score > b.Score:
+			return 1
+		}
+		return 0
+	})
+	return ranked
+}
+
+// earthRadiusKm — средний радиус Земли.
+const earthRadiusKm = 6371
+
+// DistanceKm возвращает расстояние между двумя точками по поверхности Земли.
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,146 @@
+package infra
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// kmNorth — на сколько градусов широты сдвинуться, чтобы пройти km на север.
+func kmNorth(km float64) float64 {
+	return km / (earthRadiusKm * math.Pi / 180)
+}
+
+// agentAt — исполнитель в km к северу от заказа в (55, 37).
+func agentAt(id byte, km float64) *AgentCandidate {
+	return &AgentCandidate{
+		AgentID:   uuid.UUID{id},
+		Latitude:  ptr(55 + kmNorth(km)),
+		Longitude: ptr(37.0),
+		Rating:    1,
+	}
+}
+
+func agentIDs(agents []*AgentCandidate) []byte {
+	ids := make([]byte, len(agents))
+	for i, a := range agents {
+		ids[i] = a.AgentID[0]
+	}
+	return ids
+}
+
+func TestRankAgents(t *testing.T) {
+	weights := DispatchWeights{MaxDistanceKm: 20, RatingKm: 5, LoadKm: 2}
+
+	tests := []struct {
+		name   string
+		order  *Order
+		agents func() []*AgentCandidate
+		want   []byte
+	}{
+		{
+			"closest first",
+			&Order{Latitude: ptr(55.0), Longitude: ptr(37.0)},
+			func() []*AgentCandidate { return []*AgentCandidate{agentAt(1, 10), agentAt(2, 3), agentAt(3, 7)} },
+			[]byte{2, 3, 1},
+		},
+		{
+			"beyond max distance",
+			&Order{Latitude: ptr(55.0), Longitude: ptr(37.0)},
+			func() []*AgentCandidate { return []*AgentCandidate{agentAt(1, 25), agentAt(2, 19)} },
+			[]byte{2},
+		},
+		{
+			"beyond agent radius",
+			&Order{Latitude: ptr(55.0), Longitude: ptr(37.0)},
+			func() []*AgentCandidate {
+				near, far := agentAt(1, 4), agentAt(2, 6)
+				near.RadiusKm, far.RadiusKm = ptr(5.0), ptr(5.0)
+				return []*AgentCandidate{near, far}
+			},
+			[]byte{1},
+		},
+		{
+			"agent radius doesn't extend max distance",
+			&Order{Latitude: ptr(55.0), Longitude: ptr(37.0)},
+			func() []*AgentCandidate {
+				a := agentAt(1, 25)
+				a.RadiusKm = ptr(50.0)
+				return []*AgentCandidate{a}
+			},
+			[]byte{},
+		},
+		{
+			"rating outweighs a little distance",
+			&Order{Latitude: ptr(55.0), Longitude: ptr(37.0)},
+			func() []*AgentCandidate {
+				// 0.5 рейтинга — 2.5 км пути: 3 + 2.5 > 4
+				unreliable, reliable := agentAt(1, 3), agentAt(2, 4)
+				unreliable.Rating = 0.5
+				return []*AgentCandidate{unreliable, reliable}
+			},
+			[]byte{2, 1},
+		},
+		{
+			"load outweighs a little distance",
+			&Order{Latitude: ptr(55.0), Longitude: ptr(37.0)},
+			func() []*AgentCandidate {
+				// 2 заказа — 4 км пути: 3 + 4 > 6
+				busy, free := agentAt(1, 3), agentAt(2, 6)
+				busy.Load = 2
+				return []*AgentCandidate{busy, free}
+			},
+			[]byte{2, 1},
+		},
+		{
+			"unknown coordinates count as max distance",
+			&Order{Latitude: ptr(55.0), Longitude: ptr(37.0)},
+			func() []*AgentCandidate {
+				unknown := &AgentCandidate{AgentID: uuid.UUID{1}, Rating: 1}
+				return []*AgentCandidate{unknown, agentAt(2, 19)}
+			},
+			[]byte{2, 1},
+		},
+		{
+			"order without a point keeps every agent",
+			&Order{},
+			func() []*AgentCandidate { return []*AgentCandidate{agentAt(1, 100), agentAt(2, 1)} },
+			[]byte{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := agentIDs(RankAgents(tt.order, tt.agents(), weights))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ranked = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankAgentsScore(t *testing.T) {
+	order := &Order{Latitude: ptr(55.0), Longitude: ptr(37.0)}
+	a := agentAt(1, 10)
+	a.Rating = 0.8
+	a.Load = 3
+
+	RankAgents(order, []*AgentCandidate{a}, DispatchWeights{MaxDistanceKm: 20, RatingKm: 5, LoadKm: 2})
+
+	// 10 км + 0.2 * 5 + 3 * 2
+	if want := 17.0; math.Abs(a.Score-want) > 1e-6 {
+		t.Errorf("score = %v, want %v", a.Score, want)
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	// Москва — Санкт-Петербург, около 634 км
+	if d := DistanceKm(55.7558, 37.6173, 59.9343, 30.3351); math.Abs(d-634) > 5 {
+		t.Errorf("distance = %v km, want about 634", d)
+	}
+	if d := DistanceKm(55, 37, 55, 37); d != 0 {
+		t.Errorf("distance to itself = %v, want 0", d)
+	}
+}
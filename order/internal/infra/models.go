@@ -1,6 +1,7 @@
 package infra
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,4 +16,118 @@ type Order struct {
 	OrderDate     time.Time     `json:"order_date"`
 	OrderTimeGap  time.Duration `json:"order_time_gap"`
 	OrderStatus   string        `json:"order_status"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	Notes         string        `json:"notes"`
+	// Version растёт при каждом изменении заказа (оптимистичная блокировка в UpdateOrder)
+	Version int64 `json:"version"`
+	// IdempotencyKey — ключ клиента, с которым заказ создан; уникален в рамках пользователя
+	IdempotencyKey string `json:"-"`
+	// Cancellation заполнена у отменённого заказа
+	Cancellation *Cancellation `json:"cancellation,omitempty"`
+	// MatchingDeadline — до какого времени исполнители могут встать в очередь
+	// на заказ; заполнен, пока заказ в статусе matching
+	MatchingDeadline *time.Time `json:"matching_deadline,omitempty"`
+	// Latitude и Longitude — координаты места заказа, по ним исполнители
+	// ранжируются по расстоянию. Заданы обе или ни одной
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+}
+
+// ETag возвращает версию заказа в формате HTTP ETag.
+func (o *Order) ETag() string {
+	return strconv.Quote(strconv.FormatInt(o.Version, 10))
+}
+
+// Active сообщает, что заказ ещё не завершён и не отменён.
+func (o *Order) Active() bool {
+	switch o.OrderStatus {
+	case "pending", "matching", "signed":
+		return true
+	}
+	return false
+}
+
+// Кто отменил заказ.
+const (
+	CancelledByClient  = "client"
+	CancelledByAgent   = "agent"
+	CancelledByManager = "manager"
+	CancelledBySystem  = "system"
+)
+
+// Последствия отмены по политике.
+const (
+	PenaltyNone   = "none"
+	PenaltyFee    = "fee"
+	PenaltyStrike = "strike"
+)
+
+// CancellationReasons — допустимые коды причин отмены.
+var CancellationReasons = []string{
+	"changed_plans",
+	"found_alternative",
+	"agent_late",
+	"agent_unresponsive",
+	"client_unresponsive",
+	"wrong_details",
+	"expired",
+	CancellationReasonOther,
+}
+
+// CancellationReasonOther требует пояснения в Comment.
+const CancellationReasonOther = "other"
+
+// CancellationTerms — последствия отмены заказа в его текущем состоянии.
+type CancellationTerms struct {
+	Free        bool   `json:"free"`
+	Penalty     string `json:"penalty"`
+	FeeAmount   int64  `json:"fee_amount"`
+	FeeCurrency string `json:"fee_currency"`
+}
+
+// Cancellation — запись об отмене заказа: причина, кто отменил и с какими последствиями.
+type Cancellation struct {
+	OrderID     uuid.UUID `json:"order_id"`
+	Reason      string    `json:"reason"`
+	Comment     string    `json:"comment"`
+	CancelledBy string    `json:"cancelled_by"`
+	// ActorID — пользователь, отменивший заказ; uuid.Nil для системы
+	ActorID uuid.UUID `json:"actor_id"`
+	CancellationTerms
+	CancelledAt time.Time `json:"cancelled_at"`
+}
+
+// Статусы исполнителя в очереди на заказ.
+const (
+	CandidateWaiting  = "waiting"
+	CandidateSelected = "selected"
+	// CandidateReleased — выбрали другого исполнителя или заказ больше не ждёт исполнителя
+	CandidateReleased = "released"
+	CandidateLeft     = "left"
+)
+
+// Candidate — исполнитель в очереди на заказ.
+type Candidate struct {
+	OrderID uuid.UUID `json:"order_id"`
+	AgentID uuid.UUID `json:"agent_id"`
+	// Comment — что исполнитель хочет сказать клиенту
+	Comment  string    `json:"comment"`
+	Status   string    `json:"status"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// OrderEvent — событие заказа в order_events. Оно записывается в одной
+// транзакции с изменением заказа и публикуется из таблицы; по ней же
+// orderqctl переиздаёт события в order.events.
+type OrderEvent struct {
+	EventID       string
+	OrderID       uuid.UUID
+	RoutingKey    string
+	EventType     string
+	Source        string
+	ContentType   string
+	CorrelationID string
+	Payload       []byte
+	OccurredAt    time.Time
 }
@@ -0,0 +1,41 @@
+package infra
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Состояния исполнителя. Хранятся offline, online и searching; busy
+// вычисляется при чтении, пока у исполнителя на линии есть заказ в работе.
+const (
+	// AgentOffline — исполнитель закрыл сеанс или перестал присылать heartbeat
+	AgentOffline = "offline"
+	// AgentOnline — исполнитель на линии, но заказы не ищет
+	AgentOnline = "online"
+	// AgentSearching — исполнитель ищет заказы, и ему их предлагают
+	AgentSearching = "searching"
+	// AgentBusy — исполнитель на линии и выполняет заказ
+	AgentBusy = "busy"
+)
+
+// AgentStatuses — допустимые значения AgentSession.Status.
+var AgentStatuses = []string{AgentOffline, AgentOnline, AgentSearching, AgentBusy}
+
+// AgentSession — состояние исполнителя и его пожелания на время поиска.
+type AgentSession struct {
+	AgentID   uuid.UUID `json:"agent_id"`
+	Status    string    `json:"status"`
+	Latitude  *float64  `json:"latitude,omitempty"`
+	Longitude *float64  `json:"longitude,omitempty"`
+	// RadiusKm — дальше заказы исполнителю не предлагаются; nil — DISPATCH_MAX_DISTANCE_KM
+	RadiusKm *float64 `json:"radius_km,omitempty"`
+	// AvailableFrom и AvailableUntil ограничивают order_date предлагаемых заказов
+	AvailableFrom  *time.Time `json:"available_from,omitempty"`
+	AvailableUntil *time.Time `json:"available_until,omitempty"`
+	// StartedAt — когда исполнитель начал искать заказы
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	// CurrentOrderID — заказ, который исполнитель выполняет, если такой есть
+	CurrentOrderID *uuid.UUID `json:"current_order_id,omitempty"`
+}
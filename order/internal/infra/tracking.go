@@ -0,0 +1,56 @@
+package infra
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MinTrackingSpeedKmh — при меньшей скорости исполнитель считается стоящим, и
+// ETA считается по средней скорости.
+const MinTrackingSpeedKmh = 5
+
+// AgentLocation — последнее место, которое прислал исполнитель.
+type AgentLocation struct {
+	AgentID   uuid.UUID `json:"agent_id"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	// AccuracyM — точность в метрах; nil — неизвестна
+	AccuracyM *float64 `json:"accuracy_m,omitempty"`
+	// SpeedKmh — скорость по данным устройства; nil — неизвестна
+	SpeedKmh   *float64  `json:"speed_kmh,omitempty"`
+	RecordedAt time.Time `json:"recorded_at"`
+	// ExpiresAt — после этого место считается неизвестным
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// LocationUpdate — место исполнителя заказа в работе и когда он доберётся.
+type LocationUpdate struct {
+	Order    *Order
+	Location *AgentLocation
+	// DistanceKm и ETA — до места заказа; nil, если его координаты неизвестны
+	DistanceKm *float64
+	ETA        *time.Duration
+}
+
+// EstimateArrival считает расстояние от исполнителя до места заказа и время в
+// пути: по скорости исполнителя, если он движется, иначе по averageSpeedKmh.
+func EstimateArrival(order *Order, location *AgentLocation, averageSpeedKmh float64) *LocationUpdate {
+	update := &LocationUpdate{Order: order, Location: location}
+	if order.Latitude == nil || order.Longitude == nil {
+		return update
+	}
+
+	distance := DistanceKm(location.Latitude, location.Longitude, *order.Latitude, *order.Longitude)
+	update.DistanceKm = &distance
+
+	speed := averageSpeedKmh
+	if location.SpeedKmh != nil && *location.SpeedKmh >= MinTrackingSpeedKmh {
+		speed = *location.SpeedKmh
+	}
+	if speed > 0 {
+		eta := time.Duration(distance / speed * float64(time.Hour)).Round(time.Second)
+		update.ETA = &eta
+	}
+	return update
+}
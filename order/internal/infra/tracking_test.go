@@ -0,0 +1,57 @@
+package infra
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEstimateArrival(t *testing.T) {
+	order := &Order{Latitude: ptr(55.0), Longitude: ptr(37.0)}
+	// Исполнитель в 10 км к северу от заказа
+	at := func(speedKmh *float64) *AgentLocation {
+		return &AgentLocation{Latitude: 55 + kmNorth(10), Longitude: 37, SpeedKmh: speedKmh}
+	}
+
+	tests := []struct {
+		name     string
+		order    *Order
+		location *AgentLocation
+		average  float64
+		distance *float64
+		eta      *time.Duration
+	}{
+		{"moving agent", order, at(ptr(60.0)), 30, ptr(10.0), ptr(10 * time.Minute)},
+		{"unknown speed", order, at(nil), 30, ptr(10.0), ptr(20 * time.Minute)},
+		{"standing agent", order, at(ptr(MinTrackingSpeedKmh - 1.0)), 30, ptr(10.0), ptr(20 * time.Minute)},
+		{"slowest moving speed", order, at(ptr(float64(MinTrackingSpeedKmh))), 30, ptr(10.0), ptr(2 * time.Hour)},
+		{"no average speed", order, at(nil), 0, ptr(10.0), nil},
+		{"order without a point", &Order{}, at(ptr(60.0)), 30, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update := EstimateArrival(tt.order, tt.location, tt.average)
+
+			if update.Order != tt.order || update.Location != tt.location {
+				t.Errorf("update doesn't carry the order and the location")
+			}
+			switch {
+			case tt.distance == nil && update.DistanceKm != nil:
+				t.Errorf("distance = %v, want none", *update.DistanceKm)
+			case tt.distance != nil && update.DistanceKm == nil:
+				t.Errorf("distance missing, want %v", *tt.distance)
+			case tt.distance != nil && math.Abs(*update.DistanceKm-*tt.distance) > 1e-6:
+				t.Errorf("distance = %v, want %v", *update.DistanceKm, *tt.distance)
+			}
+			switch {
+			case tt.eta == nil && update.ETA != nil:
+				t.Errorf("eta = %v, want none", *update.ETA)
+			case tt.eta != nil && update.ETA == nil:
+				t.Errorf("eta missing, want %v", *tt.eta)
+			case tt.eta != nil && *update.ETA != *tt.eta:
+				t.Errorf("eta = %v, want %v", *update.ETA, *tt.eta)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package infra
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Ограничения на поля заказа и его отмены.
+const (
+	MaxOrderAddressLength        = 500
+	MaxOrderLocationLength       = 255
+	MaxOrderNotesLength          = 1000
+	MinOrderTimeGap              = time.Minute
+	MaxOrderTimeGap              = 24 * time.Hour
+	MaxCancellationCommentLength = 500
+	MaxCandidateCommentLength    = 500
+	// MaxAgentRadiusKm — самый большой радиус поиска, который может задать исполнитель
+	MaxAgentRadiusKm = 200
+	// MaxAgentSpeedKmh — скорость выше этой считается ошибкой устройства
+	MaxAgentSpeedKmh = 300
+	// OrderDateClockSkew — насколько order_date может быть в прошлом из-за
+	// расхождения часов клиента и сервера.
+	OrderDateClockSkew = time.Minute
+)
+
+// FieldViolation описывает ошибку в одном поле запроса.
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError перечисляет все ошибки в полях заказа, а не только первую,
+// чтобы форма могла показать их сразу.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Field + ": " + v.Description
+	}
+	return "invalid order: " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) add(field, format string, args ...any) {
+	e.Violations = append(e.Violations, FieldViolation{Field: field, Description: fmt.Sprintf(format, args...)})
+}
+
+// addPoint проверяет необязательные координаты: заданы обе или ни одной
+// и в допустимых пределах.
+func (e *ValidationError) addPoint(field string, latitude, longitude *float64) {
+	switch {
+	case latitude == nil && longitude == nil:
+	case latitude == nil || longitude == nil:
+		e.add(field, "latitude and longitude must be given together")
+	case *latitude < -90 || *latitude > 90:
+		e.add(field, "latitude must be between -90 and 90")
+	case *longitude < -180 || *longitude > 180:
+		e.add(field, "longitude must be between -180 and 180")
+	}
+}
+
+// Validate проверяет поля нового заказа; now — текущее время для проверки order_date.
+// Возвращает *ValidationError.
+func (o *Order) Validate(now time.Time) error {
+	verr := &ValidationError{}
+
+	switch address := strings.TrimSpace(o.OrderAddress); {
+	case address == "":
+		verr.add("order_address", "address is required")
+	case utf8.RuneCountInString(address) > MaxOrderAddressLength:
+		verr.add("order_address", "address must be at most %d characters", MaxOrderAddressLength)
+	}
+
+	switch location := strings.TrimSpace(o.OrderLocation); {
+	case location == "":
+		verr.add("order_location", "location is required")
+	case utf8.RuneCountInString(location) > MaxOrderLocationLength:
+		verr.add("order_location", "location must be at most %d characters", MaxOrderLocationLength)
+	}
+
+	switch {
+	case o.OrderDate.IsZero():
+		verr.add("order_date", "date is required")
+	case o.OrderDate.Before(now.Add(-OrderDateClockSkew)):
+		verr.add("order_date", "date must not be in the past")
+	}
+
+	switch {
+	case o.OrderTimeGap < MinOrderTimeGap:
+		verr.add("order_time_gap", "time needed must be at least 1 minute")
+	case o.OrderTimeGap > MaxOrderTimeGap:
+		verr.add("order_time_gap", "time needed must be at most 24 hours")
+	}
+
+	if utf8.RuneCountInString(o.Notes) > MaxOrderNotesLength {
+		verr.add("notes", "notes must be at most %d characters", MaxOrderNotesLength)
+	}
+
+	verr.addPoint("point", o.Latitude, o.Longitude)
+
+	if len(verr.Violations) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// ValidateFields проверяет только поля fields — при частичном обновлении
+// остальные поля не менялись и, например, order_date уже может быть в прошлом.
+func (o *Order) ValidateFields(now time.Time, fields []string) error {
+	var verr *ValidationError
+	if !errors.As(o.Validate(now), &verr) {
+		return nil
+	}
+
+	filtered := &ValidationError{}
+	for _, v := range verr.Violations {
+		if slices.Contains(fields, v.Field) {
+			filtered.Violations = append(filtered.Violations, v)
+		}
+	}
+	if len(filtered.Violations) > 0 {
+		return filtered
+	}
+	return nil
+}
+
+// Validate проверяет причину отмены и кто её запросил. Возвращает *ValidationError.
+func (c *Cancellation) Validate() error {
+	verr := &ValidationError{}
+
+	if c.Reason == "" {
+		verr.add("reason", "reason is required")
+	} else if !slices.Contains(CancellationReasons, c.Reason) {
+		verr.add("reason", "unknown reason %q", c.Reason)
+	}
+
+	comment := strings.TrimSpace(c.Comment)
+	switch {
+	case c.Reason == CancellationReasonOther && comment == "":
+		verr.add("comment", "please describe the reason")
+	case utf8.RuneCountInString(comment) > MaxCancellationCommentLength:
+		verr.add("comment", "comment must be at most %d characters", MaxCancellationCommentLength)
+	}
+
+	switch c.CancelledBy {
+	case CancelledByClient, CancelledByAgent, CancelledByManager, CancelledBySystem:
+	default:
+		verr.add("cancelled_by", "unknown canceller %q", c.CancelledBy)
+	}
+
+	if len(verr.Violations) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// Validate проверяет заявку исполнителя в очередь на заказ. Возвращает *ValidationError.
+func (c *Candidate) Validate() error {
+	verr := &ValidationError{}
+
+	if utf8.RuneCountInString(strings.TrimSpace(c.Comment)) > MaxCandidateCommentLength {
+		verr.add("comment", "comment must be at most %d characters", MaxCandidateCommentLength)
+	}
+
+	if len(verr.Violations) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// Validate проверяет координаты и пожелания исполнителя, который начинает
+// поиск; now — текущее время для проверки available_until. Возвращает *ValidationError.
+func (a *AgentSession) Validate(now time.Time) error {
+	verr := &ValidationError{}
+
+	verr.addPoint("point", a.Latitude, a.Longitude)
+
+	if a.RadiusKm != nil && (*a.RadiusKm <= 0 || *a.RadiusKm > MaxAgentRadiusKm) {
+		verr.add("radius_km", "radius must be between 0 and %g km", float64(MaxAgentRadiusKm))
+	}
+
+	switch {
+	case a.AvailableUntil == nil:
+	case !a.AvailableUntil.After(now):
+		verr.add("available_until", "must be in the future")
+	case a.AvailableFrom != nil && !a.AvailableUntil.After(*a.AvailableFrom):
+		verr.add("available_until", "must be after available_from")
+	}
+
+	if len(verr.Violations) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// Validate проверяет место, которое прислал исполнитель; now — текущее время
+// для проверки recorded_at. Возвращает *ValidationError.
+func (l *AgentLocation) Validate(now time.Time) error {
+	verr := &ValidationError{}
+
+	verr.addPoint("point", &l.Latitude, &l.Longitude)
+
+	if l.AccuracyM != nil && *l.AccuracyM < 0 {
+		verr.add("accuracy_m", "accuracy must not be negative")
+	}
+	if l.SpeedKmh != nil && (*l.SpeedKmh < 0 || *l.SpeedKmh > MaxAgentSpeedKmh) {
+		verr.add("speed_kmh", "speed must be between 0 and %g km/h", float64(MaxAgentSpeedKmh))
+	}
+	if l.RecordedAt.After(now.Add(OrderDateClockSkew)) {
+		verr.add("recorded_at", "must not be in the future")
+	}
+
+	if len(verr.Violations) > 0 {
+		return verr
+	}
+	return nil
+}
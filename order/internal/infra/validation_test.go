@@ -0,0 +1,95 @@
+package infra
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func validOrder(now time.Time) *Order {
+	return &Order{
+		OrderAddress:  "Тверская, 1",
+		OrderLocation: "Москва",
+		OrderDate:     now.Add(time.Hour),
+		OrderTimeGap:  30 * time.Minute,
+	}
+}
+
+// violatedFields возвращает поля из *ValidationError или nil, если ошибки нет.
+func violatedFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *ValidationError, got %T: %v", err, err)
+	}
+	fields := make([]string, len(verr.Violations))
+	for i, v := range verr.Violations {
+		fields[i] = v.Field
+	}
+	return fields
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestOrderValidate(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		change func(o *Order)
+		fields []string
+	}{
+		{"valid", func(o *Order) {}, nil},
+		{"blank address", func(o *Order) { o.OrderAddress = "   " }, []string{"order_address"}},
+		{"long address", func(o *Order) { o.OrderAddress = strings.Repeat("д", MaxOrderAddressLength+1) }, []string{"order_address"}},
+		{"address at limit", func(o *Order) { o.OrderAddress = strings.Repeat("д", MaxOrderAddressLength) }, nil},
+		{"blank location", func(o *Order) { o.OrderLocation = "" }, []string{"order_location"}},
+		{"long location", func(o *Order) { o.OrderLocation = strings.Repeat("x", MaxOrderLocationLength+1) }, []string{"order_location"}},
+		{"no date", func(o *Order) { o.OrderDate = time.Time{} }, []string{"order_date"}},
+		{"date in the past", func(o *Order) { o.OrderDate = now.Add(-time.Hour) }, []string{"order_date"}},
+		{"date within clock skew", func(o *Order) { o.OrderDate = now.Add(-OrderDateClockSkew / 2) }, nil},
+		{"short time gap", func(o *Order) { o.OrderTimeGap = MinOrderTimeGap - time.Second }, []string{"order_time_gap"}},
+		{"long time gap", func(o *Order) { o.OrderTimeGap = MaxOrderTimeGap + time.Second }, []string{"order_time_gap"}},
+		{"long notes", func(o *Order) { o.Notes = strings.Repeat("x", MaxOrderNotesLength+1) }, []string{"notes"}},
+		{"point", func(o *Order) { o.Latitude, o.Longitude = ptr(55.75), ptr(37.62) }, nil},
+		{"half a point", func(o *Order) { o.Latitude = ptr(55.75) }, []string{"point"}},
+		{"latitude out of range", func(o *Order) { o.Latitude, o.Longitude = ptr(91.0), ptr(37.62) }, []string{"point"}},
+		{"longitude out of range", func(o *Order) { o.Latitude, o.Longitude = ptr(55.75), ptr(-181.0) }, []string{"point"}},
+		{"every field at once", func(o *Order) { *o = Order{} }, []string{"order_address", "order_location", "order_date", "order_time_gap"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := validOrder(now)
+			tt.change(order)
+
+			fields := violatedFields(t, order.Validate(now))
+			if !slices.Equal(fields, tt.fields) {
+				t.Errorf("violated fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestOrderValidateFields(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	// Заказ создан давно: order_date уже в прошлом, но правят только заметки
+	order := validOrder(now)
+	order.OrderDate = now.Add(-24 * time.Hour)
+
+	if err := order.ValidateFields(now, []string{"notes"}); err != nil {
+		t.Errorf("notes only: unexpected error %v", err)
+	}
+
+	order.Notes = strings.Repeat("x", MaxOrderNotesLength+1)
+	fields := violatedFields(t, order.ValidateFields(now, []string{"notes"}))
+	if !slices.Equal(fields, []string{"notes"}) {
+		t.Errorf("violated fields = %v, want [notes]", fields)
+	}
+}
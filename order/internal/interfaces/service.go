@@ -3,6 +3,7 @@ package interfaces
 import (
 	"context"
 	"order_service/internal/infra"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -10,8 +11,31 @@ import (
 type Service interface {
 	CreateOrder(ctx context.Context, order *infra.Order) error
 	GetUserOrders(ctx context.Context, userID uuid.UUID) ([]*infra.Order, error)
+	GetCurrentOrder(ctx context.Context, userID uuid.UUID) (*infra.Order, error)
 	GetAvailableOrders(ctx context.Context) ([]*infra.Order, error)
 	GetOrderById(ctx context.Context, orderID uuid.UUID) (*infra.Order, error)
-	CancelOrder(ctx context.Context, orderID uuid.UUID) error
-	CompleteOrder(ctx context.Context, orderID uuid.UUID) error
+	UpdateOrder(ctx context.Context, userID uuid.UUID, patch *infra.Order, fields []string) (*infra.Order, error)
+	CancellationTerms(ctx context.Context, orderID uuid.UUID, cancelledBy string, actorID uuid.UUID) (*infra.CancellationTerms, error)
+	CancelOrder(ctx context.Context, c *infra.Cancellation, acceptedPenalty string, version int64) error
+	CompleteOrder(ctx context.Context, orderID uuid.UUID, version int64) error
+	ExpireOverdueOrders(ctx context.Context, limit int) (int, error)
+	JoinOrderQueue(ctx context.Context, c *infra.Candidate) (*infra.Order, error)
+	LeaveOrderQueue(ctx context.Context, orderID, agentID uuid.UUID) (*infra.Order, error)
+	ListCandidates(ctx context.Context, orderID, userID uuid.UUID) (*infra.Order, []*infra.Candidate, error)
+	SelectAgent(ctx context.Context, orderID, userID, agentID uuid.UUID, version int64) (*infra.Order, error)
+	CloseOverdueMatching(ctx context.Context, limit int) (int, error)
+	GetCurrentOffer(ctx context.Context, agentID uuid.UUID) (*infra.Offer, error)
+	AcceptOffer(ctx context.Context, orderID, agentID uuid.UUID) (*infra.Order, error)
+	DeclineOffer(ctx context.Context, orderID, agentID uuid.UUID) error
+	CloseStaleOffers(ctx context.Context, limit int) (int, error)
+	DispatchOrders(ctx context.Context, limit int) (int, error)
+	StartSearch(ctx context.Context, session *infra.AgentSession) (*infra.AgentSession, error)
+	StopSearch(ctx context.Context, agentID uuid.UUID) (*infra.AgentSession, error)
+	Heartbeat(ctx context.Context, agentID uuid.UUID, latitude, longitude *float64) (*infra.AgentSession, error)
+	ListAgentSessions(ctx context.Context, status string) ([]*infra.AgentSession, error)
+	CloseIdleSessions(ctx context.Context, limit int) (int, error)
+	ReportLocation(ctx context.Context, location *infra.AgentLocation) error
+	GetAgentLocation(ctx context.Context, orderID, userID uuid.UUID) (*infra.LocationUpdate, error)
+	DeleteExpiredLocations(ctx context.Context, limit int) (int, error)
+	PublishPendingEvents(ctx context.Context, olderThan time.Duration, limit int) (int, error)
 }
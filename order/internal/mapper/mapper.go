@@ -1,6 +1,8 @@
 package mapper
 
 import (
+	"time"
+
 	"order_service/internal/infra"
 	pb "order_service/proto/order_service"
 
@@ -10,7 +12,7 @@ import (
 )
 
 func ToPbOrder(order *infra.Order) *pb.Order {
-	return &pb.Order{
+	pbOrder := &pb.Order{
 		OrderId:       order.OrderID.String(),
 		UserId:        order.UserID.String(),
 		AgentId:       order.AgentID.String(),
@@ -19,10 +21,143 @@ func ToPbOrder(order *infra.Order) *pb.Order {
 		OrderDate:     timestamppb.New(order.OrderDate),
 		OrderTimeGap:  durationpb.New(order.OrderTimeGap),
 		OrderStatus:   order.OrderStatus,
+		CreatedAt:     timestamppb.New(order.CreatedAt),
+		UpdatedAt:     timestamppb.New(order.UpdatedAt),
+		Cancellation:  ToPbCancellation(order.Cancellation),
+		Notes:         order.Notes,
+		Version:       order.Version,
+		Etag:          order.ETag(),
+	}
+	if order.MatchingDeadline != nil {
+		pbOrder.MatchingDeadline = timestamppb.New(*order.MatchingDeadline)
+	}
+	pbOrder.Point = ToPbPoint(order.Latitude, order.Longitude)
+	return pbOrder
+}
+
+// ToPbPoint возвращает nil, если координаты не заданы.
+func ToPbPoint(latitude, longitude *float64) *pb.GeoPoint {
+	if latitude == nil || longitude == nil {
+		return nil
+	}
+	return &pb.GeoPoint{Latitude: *latitude, Longitude: *longitude}
+}
+
+// FromPbPoint возвращает nil-координаты, если точка не задана.
+func FromPbPoint(point *pb.GeoPoint) (latitude, longitude *float64) {
+	if point == nil {
+		return nil, nil
+	}
+	lat, lon := point.GetLatitude(), point.GetLongitude()
+	return &lat, &lon
+}
+
+func ToPbOffer(offer *infra.Offer) *pb.Offer {
+	pbOffer := &pb.Offer{
+		OrderId:   offer.OrderID.String(),
+		AgentId:   offer.AgentID.String(),
+		Status:    offer.Status,
+		OfferedAt: timestamppb.New(offer.OfferedAt),
+		ExpiresAt: timestamppb.New(offer.ExpiresAt),
+	}
+	if offer.Order != nil {
+		pbOffer.Order = ToPbOrder(offer.Order)
+	}
+	return pbOffer
+}
+
+func ToPbAgentSession(session *infra.AgentSession) *pb.AgentSession {
+	pbSession := &pb.AgentSession{
+		AgentId:        session.AgentID.String(),
+		Status:         session.Status,
+		Point:          ToPbPoint(session.Latitude, session.Longitude),
+		RadiusKm:       session.RadiusKm,
+		AvailableFrom:  ToPbTimestamp(session.AvailableFrom),
+		AvailableUntil: ToPbTimestamp(session.AvailableUntil),
+		StartedAt:      ToPbTimestamp(session.StartedAt),
+	}
+	if !session.LastSeenAt.IsZero() {
+		pbSession.LastSeenAt = timestamppb.New(session.LastSeenAt)
+	}
+	if session.CurrentOrderID != nil {
+		pbSession.CurrentOrderId = session.CurrentOrderID.String()
+	}
+	return pbSession
+}
+
+func ToPbAgentSessions(sessions []*infra.AgentSession) []*pb.AgentSession {
+	pbSessions := make([]*pb.AgentSession, len(sessions))
+	for i, session := range sessions {
+		pbSessions[i] = ToPbAgentSession(session)
+	}
+	return pbSessions
+}
+
+func ToPbAgentLocation(location *infra.AgentLocation) *pb.AgentLocation {
+	return &pb.AgentLocation{
+		AgentId:    location.AgentID.String(),
+		Point:      &pb.GeoPoint{Latitude: location.Latitude, Longitude: location.Longitude},
+		AccuracyM:  location.AccuracyM,
+		SpeedKmh:   location.SpeedKmh,
+		RecordedAt: timestamppb.New(location.RecordedAt),
+	}
+}
+
+// ToPbTimestamp возвращает nil для незаданного времени.
+func ToPbTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// FromPbTimestamp возвращает nil для незаданного времени.
+func FromPbTimestamp(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+func ToPbCandidate(c *infra.Candidate) *pb.Candidate {
+	return &pb.Candidate{
+		OrderId:  c.OrderID.String(),
+		AgentId:  c.AgentID.String(),
+		Comment:  c.Comment,
+		Status:   c.Status,
+		JoinedAt: timestamppb.New(c.JoinedAt),
+	}
+}
+
+func ToPbCancellationTerms(terms infra.CancellationTerms) *pb.CancellationTerms {
+	return &pb.CancellationTerms{
+		Free:        terms.Free,
+		Penalty:     terms.Penalty,
+		FeeAmount:   terms.FeeAmount,
+		FeeCurrency: terms.FeeCurrency,
+	}
+}
+
+func ToPbCancellation(c *infra.Cancellation) *pb.Cancellation {
+	if c == nil {
+		return nil
+	}
+	pbCancellation := &pb.Cancellation{
+		Reason:      c.Reason,
+		Comment:     c.Comment,
+		CancelledBy: c.CancelledBy,
+		Terms:       ToPbCancellationTerms(c.CancellationTerms),
+		CancelledAt: timestamppb.New(c.CancelledAt),
+	}
+	if c.ActorID != uuid.Nil {
+		pbCancellation.ActorId = c.ActorID.String()
 	}
+	return pbCancellation
 }
 
 func ToInfraOrder(order *pb.Order) *infra.Order {
+	latitude, longitude := FromPbPoint(order.Point)
 	return &infra.Order{
 		OrderID:       uuid.MustParse(order.OrderId), //FIXME: change to parse
 		UserID:        uuid.MustParse(order.UserId),  //FIXME: change to parse
@@ -32,6 +167,12 @@ func ToInfraOrder(order *pb.Order) *infra.Order {
 		OrderDate:     order.OrderDate.AsTime(),
 		OrderTimeGap:  order.OrderTimeGap.AsDuration(),
 		OrderStatus:   order.OrderStatus,
+		CreatedAt:     order.CreatedAt.AsTime(),
+		UpdatedAt:     order.UpdatedAt.AsTime(),
+		Notes:         order.Notes,
+		Version:       order.Version,
+		Latitude:      latitude,
+		Longitude:     longitude,
 	}
 }
 
@@ -0,0 +1,22 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied
+
+CREATE TABLE IF NOT EXISTS order_events (
+    event_id TEXT PRIMARY KEY,
+    order_id UUID NOT NULL,
+    routing_key TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    source TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    correlation_id TEXT NOT NULL DEFAULT '',
+    payload BYTEA NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_order_events_order_id ON order_events(order_id);
+CREATE INDEX idx_order_events_occurred_at ON order_events(occurred_at);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back
+
+DROP TABLE IF EXISTS order_events;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied
+
+ALTER TABLE orders ADD COLUMN idempotency_key TEXT;
+
+CREATE UNIQUE INDEX idx_orders_user_idempotency_key ON orders(user_id, idempotency_key)
+    WHERE idempotency_key IS NOT NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back
+
+DROP INDEX IF EXISTS idx_orders_user_idempotency_key;
+ALTER TABLE orders DROP COLUMN IF EXISTS idempotency_key;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied
+
+-- У клиента не больше одного активного заказа. Индекс держит правило и при
+-- параллельных CreateOrder; если в таблице уже есть нарушения, миграция упадёт
+-- и их нужно разобрать вручную.
+CREATE UNIQUE INDEX idx_orders_user_active ON orders(user_id)
+    WHERE order_status IN ('pending', 'matching', 'signed');
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back
+
+DROP INDEX IF EXISTS idx_orders_user_active;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied
+
+CREATE TABLE IF NOT EXISTS order_cancellations (
+    order_id UUID PRIMARY KEY REFERENCES orders(order_id) ON DELETE CASCADE,
+    reason VARCHAR(50) NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    cancelled_by VARCHAR(20) NOT NULL,
+    -- NULL, если заказ отменила система
+    actor_id UUID,
+    penalty VARCHAR(20) NOT NULL DEFAULT 'none',
+    fee_amount BIGINT NOT NULL DEFAULT 0,
+    fee_currency VARCHAR(3) NOT NULL DEFAULT '',
+    cancelled_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Штрафы и страйки участника считаются по его отменам
+CREATE INDEX idx_order_cancellations_actor_id ON order_cancellations(actor_id) WHERE penalty <> 'none';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back
+
+DROP TABLE IF EXISTS order_cancellations;
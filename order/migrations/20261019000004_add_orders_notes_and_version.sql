@@ -0,0 +1,14 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied
+
+ALTER TABLE orders ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+
+-- version растёт при каждом изменении заказа; UpdateOrder меняет заказ, только
+-- если клиент видел его последнюю версию
+ALTER TABLE orders ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back
+
+ALTER TABLE orders DROP COLUMN IF EXISTS version;
+ALTER TABLE orders DROP COLUMN IF EXISTS notes;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied
+
+-- Пока заказ в статусе matching, исполнители встают в очередь на него до matching_deadline
+ALTER TABLE orders ADD COLUMN matching_deadline TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS order_candidates (
+    order_id UUID NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    agent_id UUID NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    -- waiting, selected, released, left
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting',
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (order_id, agent_id)
+);
+
+CREATE INDEX idx_order_candidates_agent_id ON order_candidates(agent_id) WHERE status = 'waiting';
+CREATE INDEX idx_orders_matching_deadline ON orders(matching_deadline) WHERE order_status = 'matching';
+
+-- Исполнитель выполняет не больше одного заказа за раз
+CREATE UNIQUE INDEX idx_orders_agent_signed ON orders(agent_id) WHERE order_status = 'signed';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back
+
+DROP INDEX IF EXISTS idx_orders_agent_signed;
+DROP INDEX IF EXISTS idx_orders_matching_deadline;
+DROP TABLE IF EXISTS order_candidates;
+ALTER TABLE orders DROP COLUMN IF EXISTS matching_deadline;
@@ -0,0 +1,46 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied
+
+-- Координаты места заказа для автоматического назначения; заданы обе или ни одной
+ALTER TABLE orders
+    ADD COLUMN latitude DOUBLE PRECISION,
+    ADD COLUMN longitude DOUBLE PRECISION,
+    ADD CONSTRAINT orders_point_check CHECK ((latitude IS NULL) = (longitude IS NULL));
+
+-- Исполнители и их последнее место; last_seen_at — время последнего heartbeat.
+-- На линии ли исполнитель, задаёт статус сеанса (см. add_agent_sessions)
+CREATE TABLE IF NOT EXISTS agents (
+    agent_id UUID PRIMARY KEY,
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT agents_point_check CHECK ((latitude IS NULL) = (longitude IS NULL))
+);
+
+CREATE TABLE IF NOT EXISTS order_offers (
+    order_id UUID NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    agent_id UUID NOT NULL,
+    -- offered, accepted, declined, expired, withdrawn
+    status VARCHAR(20) NOT NULL DEFAULT 'offered',
+    -- Чем меньше, тем лучше исполнитель подходил заказу
+    score DOUBLE PRECISION NOT NULL,
+    offered_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    responded_at TIMESTAMPTZ,
+    PRIMARY KEY (order_id, agent_id)
+);
+
+-- У заказа и у исполнителя не больше одного открытого предложения
+CREATE UNIQUE INDEX idx_order_offers_open_order ON order_offers(order_id) WHERE status = 'offered';
+CREATE UNIQUE INDEX idx_order_offers_open_agent ON order_offers(agent_id) WHERE status = 'offered';
+CREATE INDEX idx_order_offers_expires_at ON order_offers(expires_at) WHERE status = 'offered';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back
+
+DROP TABLE IF EXISTS order_offers;
+DROP TABLE IF EXISTS agents;
+ALTER TABLE orders
+    DROP CONSTRAINT IF EXISTS orders_point_check,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS latitude;
@@ -0,0 +1,31 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied
+
+-- Сеанс исполнителя: offline, online (на линии, но заказы не ищет) или searching.
+-- busy не хранится: исполнитель занят, пока у него есть заказ в статусе signed
+ALTER TABLE agents
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'offline',
+    -- Пожелания на время поиска: радиус и на какое время брать заказы (по order_date)
+    ADD COLUMN radius_km DOUBLE PRECISION,
+    ADD COLUMN available_from TIMESTAMPTZ,
+    ADD COLUMN available_until TIMESTAMPTZ,
+    -- Когда исполнитель начал искать заказы; только в статусе searching
+    ADD COLUMN started_at TIMESTAMPTZ,
+    ADD CONSTRAINT agents_radius_check CHECK (radius_km IS NULL OR radius_km > 0),
+    ADD CONSTRAINT agents_window_check CHECK (available_until IS NULL OR available_from IS NULL OR available_until > available_from);
+
+-- Исполнители на линии, которые давно не присылали heartbeat
+CREATE INDEX idx_agents_last_seen_at ON agents(last_seen_at) WHERE status <> 'offline';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back
+
+DROP INDEX IF EXISTS idx_agents_last_seen_at;
+ALTER TABLE agents
+    DROP CONSTRAINT IF EXISTS agents_window_check,
+    DROP CONSTRAINT IF EXISTS agents_radius_check,
+    DROP COLUMN IF EXISTS started_at,
+    DROP COLUMN IF EXISTS available_until,
+    DROP COLUMN IF EXISTS available_from,
+    DROP COLUMN IF EXISTS radius_km,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied
+
+-- Последнее известное место исполнителя; после expires_at считается неизвестным
+CREATE TABLE IF NOT EXISTS agent_locations (
+    agent_id UUID PRIMARY KEY,
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    accuracy_m DOUBLE PRECISION,
+    speed_kmh DOUBLE PRECISION,
+    recorded_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    -- Когда место последний раз ушло владельцу заказа
+    pushed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_agent_locations_expires_at ON agent_locations(expires_at);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back
+
+DROP TABLE IF EXISTS agent_locations;
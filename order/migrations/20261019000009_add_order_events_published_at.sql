@@ -0,0 +1,17 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied
+
+-- События пишутся в order_events вместе с изменением заказа и публикуются
+-- из таблицы; published_at IS NULL — событие ещё не дошло до брокера
+ALTER TABLE order_events ADD COLUMN published_at TIMESTAMPTZ;
+
+-- До этой миграции в таблицу попадали только уже опубликованные события
+UPDATE order_events SET published_at = occurred_at;
+
+CREATE INDEX idx_order_events_unpublished ON order_events(occurred_at) WHERE published_at IS NULL;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back
+
+DROP INDEX IF EXISTS idx_order_events_unpublished;
+ALTER TABLE order_events DROP COLUMN IF EXISTS published_at;
@@ -10,6 +10,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -34,8 +35,17 @@ type Order struct {
 	OrderDate     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=order_date,json=orderDate,proto3" json:"order_date,omitempty"`
 	OrderTimeGap  *durationpb.Duration   `protobuf:"bytes,7,opt,name=order_time_gap,json=orderTimeGap,proto3" json:"order_time_gap,omitempty"`
 	OrderStatus   string                 `protobuf:"bytes,8,opt,name=order_status,json=orderStatus,proto3" json:"order_status,omitempty"` // "pending", "active", "finished", "cancelled"
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Cancellation  *Cancellation          `protobuf:"bytes,11,opt,name=cancellation,proto3" json:"cancellation,omitempty"` // только у отменённого заказа
+	Notes         string                 `protobuf:"bytes,12,opt,name=notes,proto3" json:"notes,omitempty"`
+	Version       int64                  `protobuf:"varint,13,opt,name=version,proto3" json:"version,omitempty"` // растёт при каждом изменении заказа
+	Etag          string                 `protobuf:"bytes,14,opt,name=etag,proto3" json:"etag,omitempty"`        // version в формате HTTP ETag, например "3"
+	// До какого времени исполнители могут встать в очередь; только в статусе matching
+	MatchingDeadline *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=matching_deadline,json=matchingDeadline,proto3" json:"matching_deadline,omitempty"`
+	Point            *GeoPoint              `protobuf:"bytes,16,opt,name=point,proto3" json:"point,omitempty"` // координаты места, если клиент их указал
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *Order) Reset() {
@@ -124,6 +134,344 @@ func (x *Order) GetOrderStatus() string {
 	return ""
 }
 
+func (x *Order) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetCancellation() *Cancellation {
+	if x != nil {
+		return x.Cancellation
+	}
+	return nil
+}
+
+func (x *Order) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *Order) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Order) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+func (x *Order) GetMatchingDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MatchingDeadline
+	}
+	return nil
+}
+
+func (x *Order) GetPoint() *GeoPoint {
+	if x != nil {
+		return x.Point
+	}
+	return nil
+}
+
+type GeoPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeoPoint) Reset() {
+	*x = GeoPoint{}
+	mi := &file_order_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeoPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoPoint) ProtoMessage() {}
+
+func (x *GeoPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoPoint.ProtoReflect.Descriptor instead.
+func (*GeoPoint) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GeoPoint) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *GeoPoint) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+// Исполнитель в очереди на заказ
+type Candidate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Comment       string                 `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"` // "waiting", "selected", "released", "left"
+	JoinedAt      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=joined_at,json=joinedAt,proto3" json:"joined_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Candidate) Reset() {
+	*x = Candidate{}
+	mi := &file_order_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Candidate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candidate) ProtoMessage() {}
+
+func (x *Candidate) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candidate.ProtoReflect.Descriptor instead.
+func (*Candidate) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Candidate) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Candidate) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *Candidate) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *Candidate) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Candidate) GetJoinedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.JoinedAt
+	}
+	return nil
+}
+
+// Последствия отмены заказа по политике
+type CancellationTerms struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Free          bool                   `protobuf:"varint,1,opt,name=free,proto3" json:"free,omitempty"`
+	Penalty       string                 `protobuf:"bytes,2,opt,name=penalty,proto3" json:"penalty,omitempty"`                       // "none", "fee", "strike"
+	FeeAmount     int64                  `protobuf:"varint,3,opt,name=fee_amount,json=feeAmount,proto3" json:"fee_amount,omitempty"` // в минимальных единицах валюты
+	FeeCurrency   string                 `protobuf:"bytes,4,opt,name=fee_currency,json=feeCurrency,proto3" json:"fee_currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancellationTerms) Reset() {
+	*x = CancellationTerms{}
+	mi := &file_order_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancellationTerms) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancellationTerms) ProtoMessage() {}
+
+func (x *CancellationTerms) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancellationTerms.ProtoReflect.Descriptor instead.
+func (*CancellationTerms) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CancellationTerms) GetFree() bool {
+	if x != nil {
+		return x.Free
+	}
+	return false
+}
+
+func (x *CancellationTerms) GetPenalty() string {
+	if x != nil {
+		return x.Penalty
+	}
+	return ""
+}
+
+func (x *CancellationTerms) GetFeeAmount() int64 {
+	if x != nil {
+		return x.FeeAmount
+	}
+	return 0
+}
+
+func (x *CancellationTerms) GetFeeCurrency() string {
+	if x != nil {
+		return x.FeeCurrency
+	}
+	return ""
+}
+
+type Cancellation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"` // "changed_plans", "found_alternative", "agent_late", "agent_unresponsive", "client_unresponsive", "wrong_details", "expired", "other"
+	Comment       string                 `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
+	CancelledBy   string                 `protobuf:"bytes,3,opt,name=cancelled_by,json=cancelledBy,proto3" json:"cancelled_by,omitempty"` // "client", "agent", "manager", "system"
+	ActorId       string                 `protobuf:"bytes,4,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	Terms         *CancellationTerms     `protobuf:"bytes,5,opt,name=terms,proto3" json:"terms,omitempty"`
+	CancelledAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cancellation) Reset() {
+	*x = Cancellation{}
+	mi := &file_order_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cancellation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cancellation) ProtoMessage() {}
+
+func (x *Cancellation) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cancellation.ProtoReflect.Descriptor instead.
+func (*Cancellation) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Cancellation) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Cancellation) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *Cancellation) GetCancelledBy() string {
+	if x != nil {
+		return x.CancelledBy
+	}
+	return ""
+}
+
+func (x *Cancellation) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *Cancellation) GetTerms() *CancellationTerms {
+	if x != nil {
+		return x.Terms
+	}
+	return nil
+}
+
+func (x *Cancellation) GetCancelledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return nil
+}
+
 type CreateOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -131,13 +479,19 @@ type CreateOrderRequest struct {
 	OrderLocation string                 `protobuf:"bytes,3,opt,name=order_location,json=orderLocation,proto3" json:"order_location,omitempty"` //место или заведение
 	OrderDate     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=order_date,json=orderDate,proto3" json:"order_date,omitempty"`
 	OrderTimeGap  *durationpb.Duration   `protobuf:"bytes,5,opt,name=order_time_gap,json=orderTimeGap,proto3" json:"order_time_gap,omitempty"` //time gap to go to the location
+	//TODO: add payment things
+	// Повторный запрос с тем же ключом возвращает уже созданный заказ
+	IdempotencyKey string `protobuf:"bytes,6,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	Notes          string `protobuf:"bytes,7,opt,name=notes,proto3" json:"notes,omitempty"`
+	// Координаты места; по ним заказ предлагается ближайшим исполнителям
+	Point         *GeoPoint `protobuf:"bytes,8,opt,name=point,proto3" json:"point,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateOrderRequest) Reset() {
 	*x = CreateOrderRequest{}
-	mi := &file_order_proto_msgTypes[1]
+	mi := &file_order_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -149,7 +503,7 @@ func (x *CreateOrderRequest) String() string {
 func (*CreateOrderRequest) ProtoMessage() {}
 
 func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[1]
+	mi := &file_order_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -162,7 +516,7 @@ func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
 func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{1}
+	return file_order_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *CreateOrderRequest) GetUserId() string {
@@ -183,45 +537,1961 @@ func (x *CreateOrderRequest) GetOrderLocation() string {
 	if x != nil {
 		return x.OrderLocation
 	}
-	return ""
+	return ""
+}
+
+func (x *CreateOrderRequest) GetOrderDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OrderDate
+	}
+	return nil
+}
+
+func (x *CreateOrderRequest) GetOrderTimeGap() *durationpb.Duration {
+	if x != nil {
+		return x.OrderTimeGap
+	}
+	return nil
+}
+
+func (x *CreateOrderRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *CreateOrderRequest) GetNotes() string {
+	if x != nil {
+		return x.Notes
+	}
+	return ""
+}
+
+func (x *CreateOrderRequest) GetPoint() *GeoPoint {
+	if x != nil {
+		return x.Point
+	}
+	return nil
+}
+
+type CreateOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Order         *Order                 `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"` // созданный заказ (или уже существующий при повторе с тем же ключом)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrderResponse) Reset() {
+	*x = CreateOrderResponse{}
+	mi := &file_order_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderResponse) ProtoMessage() {}
+
+func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrderResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateOrderResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CreateOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type GetUserOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserOrdersRequest) Reset() {
+	*x = GetUserOrdersRequest{}
+	mi := &file_order_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserOrdersRequest) ProtoMessage() {}
+
+func (x *GetUserOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserOrdersRequest.ProtoReflect.Descriptor instead.
+func (*GetUserOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetUserOrdersRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetUserOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserOrdersResponse) Reset() {
+	*x = GetUserOrdersResponse{}
+	mi := &file_order_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserOrdersResponse) ProtoMessage() {}
+
+func (x *GetUserOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserOrdersResponse.ProtoReflect.Descriptor instead.
+func (*GetUserOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetUserOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+// Активный (pending, matching, signed) заказ клиента; NOT_FOUND, если его нет
+type GetCurrentOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCurrentOrderRequest) Reset() {
+	*x = GetCurrentOrderRequest{}
+	mi := &file_order_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCurrentOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCurrentOrderRequest) ProtoMessage() {}
+
+func (x *GetCurrentOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCurrentOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetCurrentOrderRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetCurrentOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetCurrentOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCurrentOrderResponse) Reset() {
+	*x = GetCurrentOrderResponse{}
+	mi := &file_order_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCurrentOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCurrentOrderResponse) ProtoMessage() {}
+
+func (x *GetCurrentOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCurrentOrderResponse.ProtoReflect.Descriptor instead.
+func (*GetCurrentOrderResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetCurrentOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type GetAvailableOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAvailableOrdersRequest) Reset() {
+	*x = GetAvailableOrdersRequest{}
+	mi := &file_order_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAvailableOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAvailableOrdersRequest) ProtoMessage() {}
+
+func (x *GetAvailableOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAvailableOrdersRequest.ProtoReflect.Descriptor instead.
+func (*GetAvailableOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetAvailableOrdersRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type GetAvailableOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAvailableOrdersResponse) Reset() {
+	*x = GetAvailableOrdersResponse{}
+	mi := &file_order_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAvailableOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAvailableOrdersResponse) ProtoMessage() {}
+
+func (x *GetAvailableOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAvailableOrdersResponse.ProtoReflect.Descriptor instead.
+func (*GetAvailableOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetAvailableOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+type GetOrderByIdRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderByIdRequest) Reset() {
+	*x = GetOrderByIdRequest{}
+	mi := &file_order_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderByIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderByIdRequest) ProtoMessage() {}
+
+func (x *GetOrderByIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderByIdRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderByIdRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetOrderByIdRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type GetOrderByIdResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderByIdResponse) Reset() {
+	*x = GetOrderByIdResponse{}
+	mi := &file_order_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderByIdResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderByIdResponse) ProtoMessage() {}
+
+func (x *GetOrderByIdResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderByIdResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderByIdResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetOrderByIdResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+// Меняет поля заказа из update_mask: order_address, order_location, order_date,
+// order_time_gap, notes. Если version не совпадает с текущей, возвращается ABORTED.
+type UpdateOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Order         *Order                 `protobuf:"bytes,3,opt,name=order,proto3" json:"order,omitempty"` // новые значения полей из update_mask
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	Version       int64                  `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrderRequest) Reset() {
+	*x = UpdateOrderRequest{}
+	mi := &file_order_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderRequest) ProtoMessage() {}
+
+func (x *UpdateOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrderRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdateOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *UpdateOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateOrderRequest) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *UpdateOrderRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateOrderRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type UpdateOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrderResponse) Reset() {
+	*x = UpdateOrderResponse{}
+	mi := &file_order_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderResponse) ProtoMessage() {}
+
+func (x *UpdateOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderResponse.ProtoReflect.Descriptor instead.
+func (*UpdateOrderResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UpdateOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type GetCancellationTermsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	CancelledBy   string                 `protobuf:"bytes,2,opt,name=cancelled_by,json=cancelledBy,proto3" json:"cancelled_by,omitempty"`
+	ActorId       string                 `protobuf:"bytes,3,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCancellationTermsRequest) Reset() {
+	*x = GetCancellationTermsRequest{}
+	mi := &file_order_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCancellationTermsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCancellationTermsRequest) ProtoMessage() {}
+
+func (x *GetCancellationTermsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCancellationTermsRequest.ProtoReflect.Descriptor instead.
+func (*GetCancellationTermsRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetCancellationTermsRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *GetCancellationTermsRequest) GetCancelledBy() string {
+	if x != nil {
+		return x.CancelledBy
+	}
+	return ""
+}
+
+func (x *GetCancellationTermsRequest) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+type GetCancellationTermsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Terms         *CancellationTerms     `protobuf:"bytes,1,opt,name=terms,proto3" json:"terms,omitempty"`
+	Reasons       []string               `protobuf:"bytes,2,rep,name=reasons,proto3" json:"reasons,omitempty"` // допустимые коды причин
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCancellationTermsResponse) Reset() {
+	*x = GetCancellationTermsResponse{}
+	mi := &file_order_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCancellationTermsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCancellationTermsResponse) ProtoMessage() {}
+
+func (x *GetCancellationTermsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCancellationTermsResponse.ProtoReflect.Descriptor instead.
+func (*GetCancellationTermsResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetCancellationTermsResponse) GetTerms() *CancellationTerms {
+	if x != nil {
+		return x.Terms
+	}
+	return nil
+}
+
+func (x *GetCancellationTermsResponse) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+type CancelOrderRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	OrderId     string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Reason      string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Comment     string                 `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"` // обязателен для reason = "other"
+	CancelledBy string                 `protobuf:"bytes,4,opt,name=cancelled_by,json=cancelledBy,proto3" json:"cancelled_by,omitempty"`
+	ActorId     string                 `protobuf:"bytes,5,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	// Штраф, который видел пользователь в GetCancellationTerms; если он изменился, заказ не отменяется
+	AcceptedPenalty string `protobuf:"bytes,6,opt,name=accepted_penalty,json=acceptedPenalty,proto3" json:"accepted_penalty,omitempty"`
+	// Версия заказа, которую видел пользователь; 0 — не проверять
+	Version       int64 `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_order_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CancelOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetCancelledBy() string {
+	if x != nil {
+		return x.CancelledBy
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetAcceptedPenalty() string {
+	if x != nil {
+		return x.AcceptedPenalty
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type CancelOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Cancellation  *Cancellation          `protobuf:"bytes,2,opt,name=cancellation,proto3" json:"cancellation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_order_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
+
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CancelOrderResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CancelOrderResponse) GetCancellation() *Cancellation {
+	if x != nil {
+		return x.Cancellation
+	}
+	return nil
+}
+
+type CompleteOrderRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// Версия заказа, которую видел пользователь; 0 — не проверять
+	Version       int64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteOrderRequest) Reset() {
+	*x = CompleteOrderRequest{}
+	mi := &file_order_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteOrderRequest) ProtoMessage() {}
+
+func (x *CompleteOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteOrderRequest.ProtoReflect.Descriptor instead.
+func (*CompleteOrderRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CompleteOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CompleteOrderRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type CompleteOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteOrderResponse) Reset() {
+	*x = CompleteOrderResponse{}
+	mi := &file_order_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteOrderResponse) ProtoMessage() {}
+
+func (x *CompleteOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteOrderResponse.ProtoReflect.Descriptor instead.
+func (*CompleteOrderResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CompleteOrderResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type JoinOrderQueueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Comment       string                 `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"` // что исполнитель хочет сказать клиенту
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinOrderQueueRequest) Reset() {
+	*x = JoinOrderQueueRequest{}
+	mi := &file_order_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinOrderQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinOrderQueueRequest) ProtoMessage() {}
+
+func (x *JoinOrderQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinOrderQueueRequest.ProtoReflect.Descriptor instead.
+func (*JoinOrderQueueRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *JoinOrderQueueRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *JoinOrderQueueRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *JoinOrderQueueRequest) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+type JoinOrderQueueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Candidate     *Candidate             `protobuf:"bytes,1,opt,name=candidate,proto3" json:"candidate,omitempty"`
+	Order         *Order                 `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinOrderQueueResponse) Reset() {
+	*x = JoinOrderQueueResponse{}
+	mi := &file_order_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinOrderQueueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinOrderQueueResponse) ProtoMessage() {}
+
+func (x *JoinOrderQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinOrderQueueResponse.ProtoReflect.Descriptor instead.
+func (*JoinOrderQueueResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *JoinOrderQueueResponse) GetCandidate() *Candidate {
+	if x != nil {
+		return x.Candidate
+	}
+	return nil
+}
+
+func (x *JoinOrderQueueResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type LeaveOrderQueueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveOrderQueueRequest) Reset() {
+	*x = LeaveOrderQueueRequest{}
+	mi := &file_order_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveOrderQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveOrderQueueRequest) ProtoMessage() {}
+
+func (x *LeaveOrderQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveOrderQueueRequest.ProtoReflect.Descriptor instead.
+func (*LeaveOrderQueueRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *LeaveOrderQueueRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *LeaveOrderQueueRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+type LeaveOrderQueueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveOrderQueueResponse) Reset() {
+	*x = LeaveOrderQueueResponse{}
+	mi := &file_order_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveOrderQueueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveOrderQueueResponse) ProtoMessage() {}
+
+func (x *LeaveOrderQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveOrderQueueResponse.ProtoReflect.Descriptor instead.
+func (*LeaveOrderQueueResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *LeaveOrderQueueResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type ListCandidatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCandidatesRequest) Reset() {
+	*x = ListCandidatesRequest{}
+	mi := &file_order_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCandidatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCandidatesRequest) ProtoMessage() {}
+
+func (x *ListCandidatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCandidatesRequest.ProtoReflect.Descriptor instead.
+func (*ListCandidatesRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListCandidatesRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *ListCandidatesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListCandidatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Candidates    []*Candidate           `protobuf:"bytes,1,rep,name=candidates,proto3" json:"candidates,omitempty"`
+	Order         *Order                 `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCandidatesResponse) Reset() {
+	*x = ListCandidatesResponse{}
+	mi := &file_order_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCandidatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCandidatesResponse) ProtoMessage() {}
+
+func (x *ListCandidatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCandidatesResponse.ProtoReflect.Descriptor instead.
+func (*ListCandidatesResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListCandidatesResponse) GetCandidates() []*Candidate {
+	if x != nil {
+		return x.Candidates
+	}
+	return nil
+}
+
+func (x *ListCandidatesResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type SelectAgentRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId  string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AgentId string                 `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Версия заказа, которую видел клиент; 0 — не проверять
+	Version       int64 `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelectAgentRequest) Reset() {
+	*x = SelectAgentRequest{}
+	mi := &file_order_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelectAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectAgentRequest) ProtoMessage() {}
+
+func (x *SelectAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectAgentRequest.ProtoReflect.Descriptor instead.
+func (*SelectAgentRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SelectAgentRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *SelectAgentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SelectAgentRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *SelectAgentRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type SelectAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SelectAgentResponse) Reset() {
+	*x = SelectAgentResponse{}
+	mi := &file_order_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SelectAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SelectAgentResponse) ProtoMessage() {}
+
+func (x *SelectAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SelectAgentResponse.ProtoReflect.Descriptor instead.
+func (*SelectAgentResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SelectAgentResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+// Предложение заказа исполнителю
+type Offer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // "offered", "accepted", "declined", "expired", "withdrawn"
+	OfferedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=offered_at,json=offeredAt,proto3" json:"offered_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // до какого времени нужно ответить
+	Order         *Order                 `protobuf:"bytes,6,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Offer) Reset() {
+	*x = Offer{}
+	mi := &file_order_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Offer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Offer) ProtoMessage() {}
+
+func (x *Offer) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Offer.ProtoReflect.Descriptor instead.
+func (*Offer) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *Offer) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Offer) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *Offer) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Offer) GetOfferedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OfferedAt
+	}
+	return nil
+}
+
+func (x *Offer) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Offer) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+// Открытое предложение исполнителю; NOT_FOUND, если его нет
+type GetCurrentOfferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCurrentOfferRequest) Reset() {
+	*x = GetCurrentOfferRequest{}
+	mi := &file_order_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCurrentOfferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCurrentOfferRequest) ProtoMessage() {}
+
+func (x *GetCurrentOfferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCurrentOfferRequest.ProtoReflect.Descriptor instead.
+func (*GetCurrentOfferRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *GetCurrentOfferRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+type GetCurrentOfferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Offer         *Offer                 `protobuf:"bytes,1,opt,name=offer,proto3" json:"offer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCurrentOfferResponse) Reset() {
+	*x = GetCurrentOfferResponse{}
+	mi := &file_order_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCurrentOfferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCurrentOfferResponse) ProtoMessage() {}
+
+func (x *GetCurrentOfferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCurrentOfferResponse.ProtoReflect.Descriptor instead.
+func (*GetCurrentOfferResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetCurrentOfferResponse) GetOffer() *Offer {
+	if x != nil {
+		return x.Offer
+	}
+	return nil
+}
+
+type AcceptOfferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptOfferRequest) Reset() {
+	*x = AcceptOfferRequest{}
+	mi := &file_order_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptOfferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptOfferRequest) ProtoMessage() {}
+
+func (x *AcceptOfferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptOfferRequest.ProtoReflect.Descriptor instead.
+func (*AcceptOfferRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *AcceptOfferRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *AcceptOfferRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+type AcceptOfferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptOfferResponse) Reset() {
+	*x = AcceptOfferResponse{}
+	mi := &file_order_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptOfferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptOfferResponse) ProtoMessage() {}
+
+func (x *AcceptOfferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptOfferResponse.ProtoReflect.Descriptor instead.
+func (*AcceptOfferResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *AcceptOfferResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type DeclineOfferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeclineOfferRequest) Reset() {
+	*x = DeclineOfferRequest{}
+	mi := &file_order_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeclineOfferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeclineOfferRequest) ProtoMessage() {}
+
+func (x *DeclineOfferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeclineOfferRequest.ProtoReflect.Descriptor instead.
+func (*DeclineOfferRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *DeclineOfferRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *DeclineOfferRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+type DeclineOfferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeclineOfferResponse) Reset() {
+	*x = DeclineOfferResponse{}
+	mi := &file_order_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeclineOfferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeclineOfferResponse) ProtoMessage() {}
+
+func (x *DeclineOfferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeclineOfferResponse.ProtoReflect.Descriptor instead.
+func (*DeclineOfferResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{37}
+}
+
+// Состояние исполнителя и его пожелания на время поиска
+type AgentSession struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	AgentId  string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Status   string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`                             // "offline", "online", "searching", "busy"
+	Point    *GeoPoint              `protobuf:"bytes,3,opt,name=point,proto3" json:"point,omitempty"`                               // последнее известное место
+	RadiusKm *float64               `protobuf:"fixed64,4,opt,name=radius_km,json=radiusKm,proto3,oneof" json:"radius_km,omitempty"` // без него — DISPATCH_MAX_DISTANCE_KM
+	// На какое время исполнитель готов брать заказы (по order_date)
+	AvailableFrom  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=available_from,json=availableFrom,proto3" json:"available_from,omitempty"`
+	AvailableUntil *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=available_until,json=availableUntil,proto3" json:"available_until,omitempty"`
+	StartedAt      *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"` // когда начат поиск
+	LastSeenAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_seen_at,json=lastSeenAt,proto3" json:"last_seen_at,omitempty"`
+	CurrentOrderId string                 `protobuf:"bytes,9,opt,name=current_order_id,json=currentOrderId,proto3" json:"current_order_id,omitempty"` // заказ в работе, если есть
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AgentSession) Reset() {
+	*x = AgentSession{}
+	mi := &file_order_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentSession) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentSession) ProtoMessage() {}
+
+func (x *AgentSession) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentSession.ProtoReflect.Descriptor instead.
+func (*AgentSession) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *AgentSession) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AgentSession) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AgentSession) GetPoint() *GeoPoint {
+	if x != nil {
+		return x.Point
+	}
+	return nil
+}
+
+func (x *AgentSession) GetRadiusKm() float64 {
+	if x != nil && x.RadiusKm != nil {
+		return *x.RadiusKm
+	}
+	return 0
+}
+
+func (x *AgentSession) GetAvailableFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AvailableFrom
+	}
+	return nil
+}
+
+func (x *AgentSession) GetAvailableUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AvailableUntil
+	}
+	return nil
+}
+
+func (x *AgentSession) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *AgentSession) GetLastSeenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return nil
+}
+
+func (x *AgentSession) GetCurrentOrderId() string {
+	if x != nil {
+		return x.CurrentOrderId
+	}
+	return ""
+}
+
+type StartSearchRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AgentId        string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Point          *GeoPoint              `protobuf:"bytes,2,opt,name=point,proto3" json:"point,omitempty"`
+	RadiusKm       *float64               `protobuf:"fixed64,3,opt,name=radius_km,json=radiusKm,proto3,oneof" json:"radius_km,omitempty"`
+	AvailableFrom  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=available_from,json=availableFrom,proto3" json:"available_from,omitempty"`
+	AvailableUntil *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=available_until,json=availableUntil,proto3" json:"available_until,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *StartSearchRequest) Reset() {
+	*x = StartSearchRequest{}
+	mi := &file_order_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartSearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartSearchRequest) ProtoMessage() {}
+
+func (x *StartSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartSearchRequest.ProtoReflect.Descriptor instead.
+func (*StartSearchRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *StartSearchRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *StartSearchRequest) GetPoint() *GeoPoint {
+	if x != nil {
+		return x.Point
+	}
+	return nil
+}
+
+func (x *StartSearchRequest) GetRadiusKm() float64 {
+	if x != nil && x.RadiusKm != nil {
+		return *x.RadiusKm
+	}
+	return 0
+}
+
+func (x *StartSearchRequest) GetAvailableFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AvailableFrom
+	}
+	return nil
+}
+
+func (x *StartSearchRequest) GetAvailableUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AvailableUntil
+	}
+	return nil
+}
+
+type StartSearchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Session       *AgentSession          `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartSearchResponse) Reset() {
+	*x = StartSearchResponse{}
+	mi := &file_order_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartSearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartSearchResponse) ProtoMessage() {}
+
+func (x *StartSearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (x *CreateOrderRequest) GetOrderDate() *timestamppb.Timestamp {
-	if x != nil {
-		return x.OrderDate
-	}
-	return nil
+// Deprecated: Use StartSearchResponse.ProtoReflect.Descriptor instead.
+func (*StartSearchResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *CreateOrderRequest) GetOrderTimeGap() *durationpb.Duration {
+func (x *StartSearchResponse) GetSession() *AgentSession {
 	if x != nil {
-		return x.OrderTimeGap
+		return x.Session
 	}
 	return nil
 }
 
-type CreateOrderResponse struct {
+type StopSearchRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateOrderResponse) Reset() {
-	*x = CreateOrderResponse{}
-	mi := &file_order_proto_msgTypes[2]
+func (x *StopSearchRequest) Reset() {
+	*x = StopSearchRequest{}
+	mi := &file_order_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrderResponse) String() string {
+func (x *StopSearchRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrderResponse) ProtoMessage() {}
+func (*StopSearchRequest) ProtoMessage() {}
 
-func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[2]
+func (x *StopSearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -232,40 +2502,40 @@ func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrderResponse.ProtoReflect.Descriptor instead.
-func (*CreateOrderResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use StopSearchRequest.ProtoReflect.Descriptor instead.
+func (*StopSearchRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *CreateOrderResponse) GetSuccess() bool {
+func (x *StopSearchRequest) GetAgentId() string {
 	if x != nil {
-		return x.Success
+		return x.AgentId
 	}
-	return false
+	return ""
 }
 
-type GetUserOrdersRequest struct {
+type StopSearchResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Session       *AgentSession          `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserOrdersRequest) Reset() {
-	*x = GetUserOrdersRequest{}
-	mi := &file_order_proto_msgTypes[3]
+func (x *StopSearchResponse) Reset() {
+	*x = StopSearchResponse{}
+	mi := &file_order_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserOrdersRequest) String() string {
+func (x *StopSearchResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserOrdersRequest) ProtoMessage() {}
+func (*StopSearchResponse) ProtoMessage() {}
 
-func (x *GetUserOrdersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[3]
+func (x *StopSearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -276,40 +2546,42 @@ func (x *GetUserOrdersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserOrdersRequest.ProtoReflect.Descriptor instead.
-func (*GetUserOrdersRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use StopSearchResponse.ProtoReflect.Descriptor instead.
+func (*StopSearchResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *GetUserOrdersRequest) GetUserId() string {
+func (x *StopSearchResponse) GetSession() *AgentSession {
 	if x != nil {
-		return x.UserId
+		return x.Session
 	}
-	return ""
+	return nil
 }
 
-type GetUserOrdersResponse struct {
+// Исполнитель на линии, пока присылает heartbeat чаще AGENT_SESSION_HEARTBEAT_TIMEOUT
+type HeartbeatRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Point         *GeoPoint              `protobuf:"bytes,2,opt,name=point,proto3" json:"point,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserOrdersResponse) Reset() {
-	*x = GetUserOrdersResponse{}
-	mi := &file_order_proto_msgTypes[4]
+func (x *HeartbeatRequest) Reset() {
+	*x = HeartbeatRequest{}
+	mi := &file_order_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserOrdersResponse) String() string {
+func (x *HeartbeatRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserOrdersResponse) ProtoMessage() {}
+func (*HeartbeatRequest) ProtoMessage() {}
 
-func (x *GetUserOrdersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[4]
+func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -320,40 +2592,47 @@ func (x *GetUserOrdersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserOrdersResponse.ProtoReflect.Descriptor instead.
-func (*GetUserOrdersResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *GetUserOrdersResponse) GetOrders() []*Order {
+func (x *HeartbeatRequest) GetAgentId() string {
 	if x != nil {
-		return x.Orders
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *HeartbeatRequest) GetPoint() *GeoPoint {
+	if x != nil {
+		return x.Point
 	}
 	return nil
 }
 
-type GetAvailableOrdersRequest struct {
+type HeartbeatResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Session       *AgentSession          `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAvailableOrdersRequest) Reset() {
-	*x = GetAvailableOrdersRequest{}
-	mi := &file_order_proto_msgTypes[5]
+func (x *HeartbeatResponse) Reset() {
+	*x = HeartbeatResponse{}
+	mi := &file_order_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAvailableOrdersRequest) String() string {
+func (x *HeartbeatResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAvailableOrdersRequest) ProtoMessage() {}
+func (*HeartbeatResponse) ProtoMessage() {}
 
-func (x *GetAvailableOrdersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[5]
+func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -364,40 +2643,40 @@ func (x *GetAvailableOrdersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAvailableOrdersRequest.ProtoReflect.Descriptor instead.
-func (*GetAvailableOrdersRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{44}
 }
 
-func (x *GetAvailableOrdersRequest) GetStatus() string {
+func (x *HeartbeatResponse) GetSession() *AgentSession {
 	if x != nil {
-		return x.Status
+		return x.Session
 	}
-	return ""
+	return nil
 }
 
-type GetAvailableOrdersResponse struct {
+type ListAgentSessionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // необязательный фильтр
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAvailableOrdersResponse) Reset() {
-	*x = GetAvailableOrdersResponse{}
-	mi := &file_order_proto_msgTypes[6]
+func (x *ListAgentSessionsRequest) Reset() {
+	*x = ListAgentSessionsRequest{}
+	mi := &file_order_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAvailableOrdersResponse) String() string {
+func (x *ListAgentSessionsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAvailableOrdersResponse) ProtoMessage() {}
+func (*ListAgentSessionsRequest) ProtoMessage() {}
 
-func (x *GetAvailableOrdersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[6]
+func (x *ListAgentSessionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -408,40 +2687,40 @@ func (x *GetAvailableOrdersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAvailableOrdersResponse.ProtoReflect.Descriptor instead.
-func (*GetAvailableOrdersResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ListAgentSessionsRequest.ProtoReflect.Descriptor instead.
+func (*ListAgentSessionsRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{45}
 }
 
-func (x *GetAvailableOrdersResponse) GetOrders() []*Order {
+func (x *ListAgentSessionsRequest) GetStatus() string {
 	if x != nil {
-		return x.Orders
+		return x.Status
 	}
-	return nil
+	return ""
 }
 
-type GetOrderByIdRequest struct {
+type ListAgentSessionsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Sessions      []*AgentSession        `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrderByIdRequest) Reset() {
-	*x = GetOrderByIdRequest{}
-	mi := &file_order_proto_msgTypes[7]
+func (x *ListAgentSessionsResponse) Reset() {
+	*x = ListAgentSessionsResponse{}
+	mi := &file_order_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrderByIdRequest) String() string {
+func (x *ListAgentSessionsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrderByIdRequest) ProtoMessage() {}
+func (*ListAgentSessionsResponse) ProtoMessage() {}
 
-func (x *GetOrderByIdRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[7]
+func (x *ListAgentSessionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -452,40 +2731,44 @@ func (x *GetOrderByIdRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrderByIdRequest.ProtoReflect.Descriptor instead.
-func (*GetOrderByIdRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use ListAgentSessionsResponse.ProtoReflect.Descriptor instead.
+func (*ListAgentSessionsResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *GetOrderByIdRequest) GetOrderId() string {
+func (x *ListAgentSessionsResponse) GetSessions() []*AgentSession {
 	if x != nil {
-		return x.OrderId
+		return x.Sessions
 	}
-	return ""
+	return nil
 }
 
-type GetOrderByIdResponse struct {
+type AgentLocation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Point         *GeoPoint              `protobuf:"bytes,2,opt,name=point,proto3" json:"point,omitempty"`
+	AccuracyM     *float64               `protobuf:"fixed64,3,opt,name=accuracy_m,json=accuracyM,proto3,oneof" json:"accuracy_m,omitempty"`
+	SpeedKmh      *float64               `protobuf:"fixed64,4,opt,name=speed_kmh,json=speedKmh,proto3,oneof" json:"speed_kmh,omitempty"`
+	RecordedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=recorded_at,json=recordedAt,proto3" json:"recorded_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrderByIdResponse) Reset() {
-	*x = GetOrderByIdResponse{}
-	mi := &file_order_proto_msgTypes[8]
+func (x *AgentLocation) Reset() {
+	*x = AgentLocation{}
+	mi := &file_order_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrderByIdResponse) String() string {
+func (x *AgentLocation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrderByIdResponse) ProtoMessage() {}
+func (*AgentLocation) ProtoMessage() {}
 
-func (x *GetOrderByIdResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[8]
+func (x *AgentLocation) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -496,40 +2779,73 @@ func (x *GetOrderByIdResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrderByIdResponse.ProtoReflect.Descriptor instead.
-func (*GetOrderByIdResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use AgentLocation.ProtoReflect.Descriptor instead.
+func (*AgentLocation) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{47}
 }
 
-func (x *GetOrderByIdResponse) GetOrder() *Order {
+func (x *AgentLocation) GetAgentId() string {
 	if x != nil {
-		return x.Order
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AgentLocation) GetPoint() *GeoPoint {
+	if x != nil {
+		return x.Point
 	}
 	return nil
 }
 
-type CancelOrderRequest struct {
+func (x *AgentLocation) GetAccuracyM() float64 {
+	if x != nil && x.AccuracyM != nil {
+		return *x.AccuracyM
+	}
+	return 0
+}
+
+func (x *AgentLocation) GetSpeedKmh() float64 {
+	if x != nil && x.SpeedKmh != nil {
+		return *x.SpeedKmh
+	}
+	return 0
+}
+
+func (x *AgentLocation) GetRecordedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RecordedAt
+	}
+	return nil
+}
+
+// Одна точка из потока исполнителя; без recorded_at — время получения
+type ReportLocationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"` // string reason = 2; // Optional reason for cancellation
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Point         *GeoPoint              `protobuf:"bytes,2,opt,name=point,proto3" json:"point,omitempty"`
+	AccuracyM     *float64               `protobuf:"fixed64,3,opt,name=accuracy_m,json=accuracyM,proto3,oneof" json:"accuracy_m,omitempty"`
+	SpeedKmh      *float64               `protobuf:"fixed64,4,opt,name=speed_kmh,json=speedKmh,proto3,oneof" json:"speed_kmh,omitempty"`
+	RecordedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=recorded_at,json=recordedAt,proto3" json:"recorded_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CancelOrderRequest) Reset() {
-	*x = CancelOrderRequest{}
-	mi := &file_order_proto_msgTypes[9]
+func (x *ReportLocationRequest) Reset() {
+	*x = ReportLocationRequest{}
+	mi := &file_order_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CancelOrderRequest) String() string {
+func (x *ReportLocationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelOrderRequest) ProtoMessage() {}
+func (*ReportLocationRequest) ProtoMessage() {}
 
-func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[9]
+func (x *ReportLocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -540,40 +2856,69 @@ func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
-func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use ReportLocationRequest.ProtoReflect.Descriptor instead.
+func (*ReportLocationRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{48}
 }
 
-func (x *CancelOrderRequest) GetOrderId() string {
+func (x *ReportLocationRequest) GetAgentId() string {
 	if x != nil {
-		return x.OrderId
+		return x.AgentId
 	}
 	return ""
 }
 
-type CancelOrderResponse struct {
+func (x *ReportLocationRequest) GetPoint() *GeoPoint {
+	if x != nil {
+		return x.Point
+	}
+	return nil
+}
+
+func (x *ReportLocationRequest) GetAccuracyM() float64 {
+	if x != nil && x.AccuracyM != nil {
+		return *x.AccuracyM
+	}
+	return 0
+}
+
+func (x *ReportLocationRequest) GetSpeedKmh() float64 {
+	if x != nil && x.SpeedKmh != nil {
+		return *x.SpeedKmh
+	}
+	return 0
+}
+
+func (x *ReportLocationRequest) GetRecordedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RecordedAt
+	}
+	return nil
+}
+
+type ReportLocationResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Accepted      int32                  `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Rejected      int32                  `protobuf:"varint,2,opt,name=rejected,proto3" json:"rejected,omitempty"` // точки, не прошедшие проверку
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CancelOrderResponse) Reset() {
-	*x = CancelOrderResponse{}
-	mi := &file_order_proto_msgTypes[10]
+func (x *ReportLocationResponse) Reset() {
+	*x = ReportLocationResponse{}
+	mi := &file_order_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CancelOrderResponse) String() string {
+func (x *ReportLocationResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelOrderResponse) ProtoMessage() {}
+func (*ReportLocationResponse) ProtoMessage() {}
 
-func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[10]
+func (x *ReportLocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -584,40 +2929,48 @@ func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
-func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use ReportLocationResponse.ProtoReflect.Descriptor instead.
+func (*ReportLocationResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *CancelOrderResponse) GetSuccess() bool {
+func (x *ReportLocationResponse) GetAccepted() int32 {
 	if x != nil {
-		return x.Success
+		return x.Accepted
 	}
-	return false
+	return 0
 }
 
-type CompleteOrderRequest struct {
+func (x *ReportLocationResponse) GetRejected() int32 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+type GetAgentLocationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // владелец заказа
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CompleteOrderRequest) Reset() {
-	*x = CompleteOrderRequest{}
-	mi := &file_order_proto_msgTypes[11]
+func (x *GetAgentLocationRequest) Reset() {
+	*x = GetAgentLocationRequest{}
+	mi := &file_order_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CompleteOrderRequest) String() string {
+func (x *GetAgentLocationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CompleteOrderRequest) ProtoMessage() {}
+func (*GetAgentLocationRequest) ProtoMessage() {}
 
-func (x *CompleteOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[11]
+func (x *GetAgentLocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -628,40 +2981,49 @@ func (x *CompleteOrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CompleteOrderRequest.ProtoReflect.Descriptor instead.
-func (*CompleteOrderRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use GetAgentLocationRequest.ProtoReflect.Descriptor instead.
+func (*GetAgentLocationRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{50}
 }
 
-func (x *CompleteOrderRequest) GetOrderId() string {
+func (x *GetAgentLocationRequest) GetOrderId() string {
 	if x != nil {
 		return x.OrderId
 	}
 	return ""
 }
 
-type CompleteOrderResponse struct {
+func (x *GetAgentLocationRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetAgentLocationResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Location      *AgentLocation         `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	DistanceKm    *float64               `protobuf:"fixed64,2,opt,name=distance_km,json=distanceKm,proto3,oneof" json:"distance_km,omitempty"` // если известно место заказа
+	Eta           *durationpb.Duration   `protobuf:"bytes,3,opt,name=eta,proto3" json:"eta,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CompleteOrderResponse) Reset() {
-	*x = CompleteOrderResponse{}
-	mi := &file_order_proto_msgTypes[12]
+func (x *GetAgentLocationResponse) Reset() {
+	*x = GetAgentLocationResponse{}
+	mi := &file_order_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CompleteOrderResponse) String() string {
+func (x *GetAgentLocationResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CompleteOrderResponse) ProtoMessage() {}
+func (*GetAgentLocationResponse) ProtoMessage() {}
 
-func (x *CompleteOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[12]
+func (x *GetAgentLocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -672,23 +3034,37 @@ func (x *CompleteOrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CompleteOrderResponse.ProtoReflect.Descriptor instead.
-func (*CompleteOrderResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use GetAgentLocationResponse.ProtoReflect.Descriptor instead.
+func (*GetAgentLocationResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{51}
 }
 
-func (x *CompleteOrderResponse) GetSuccess() bool {
+func (x *GetAgentLocationResponse) GetLocation() *AgentLocation {
 	if x != nil {
-		return x.Success
+		return x.Location
 	}
-	return false
+	return nil
+}
+
+func (x *GetAgentLocationResponse) GetDistanceKm() float64 {
+	if x != nil && x.DistanceKm != nil {
+		return *x.DistanceKm
+	}
+	return 0
+}
+
+func (x *GetAgentLocationResponse) GetEta() *durationpb.Duration {
+	if x != nil {
+		return x.Eta
+	}
+	return nil
 }
 
 var File_order_proto protoreflect.FileDescriptor
 
 const file_order_proto_rawDesc = "" +
 	"\n" +
-	"\vorder.proto\x12\rorder_service\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/duration.proto\"\xc1\x02\n" +
+	"\vorder.proto\x12\rorder_service\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/duration.proto\x1a google/protobuf/field_mask.proto\"\xb4\x05\n" +
 	"\x05Order\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x19\n" +
@@ -698,43 +3074,245 @@ const file_order_proto_rawDesc = "" +
 	"\n" +
 	"order_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\torderDate\x12?\n" +
 	"\x0eorder_time_gap\x18\a \x01(\v2\x19.google.protobuf.DurationR\forderTimeGap\x12!\n" +
-	"\forder_status\x18\b \x01(\tR\vorderStatus\"\xf5\x01\n" +
+	"\forder_status\x18\b \x01(\tR\vorderStatus\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12?\n" +
+	"\fcancellation\x18\v \x01(\v2\x1b.order_service.CancellationR\fcancellation\x12\x14\n" +
+	"\x05notes\x18\f \x01(\tR\x05notes\x12\x18\n" +
+	"\aversion\x18\r \x01(\x03R\aversion\x12\x12\n" +
+	"\x04etag\x18\x0e \x01(\tR\x04etag\x12G\n" +
+	"\x11matching_deadline\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\x10matchingDeadline\x12-\n" +
+	"\x05point\x18\x10 \x01(\v2\x17.order_service.GeoPointR\x05point\"D\n" +
+	"\bGeoPoint\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\"\xac\x01\n" +
+	"\tCandidate\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x18\n" +
+	"\acomment\x18\x03 \x01(\tR\acomment\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x127\n" +
+	"\tjoined_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\bjoinedAt\"\x83\x01\n" +
+	"\x11CancellationTerms\x12\x12\n" +
+	"\x04free\x18\x01 \x01(\bR\x04free\x12\x18\n" +
+	"\apenalty\x18\x02 \x01(\tR\apenalty\x12\x1d\n" +
+	"\n" +
+	"fee_amount\x18\x03 \x01(\x03R\tfeeAmount\x12!\n" +
+	"\ffee_currency\x18\x04 \x01(\tR\vfeeCurrency\"\xf5\x01\n" +
+	"\fCancellation\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\x12\x18\n" +
+	"\acomment\x18\x02 \x01(\tR\acomment\x12!\n" +
+	"\fcancelled_by\x18\x03 \x01(\tR\vcancelledBy\x12\x19\n" +
+	"\bactor_id\x18\x04 \x01(\tR\aactorId\x126\n" +
+	"\x05terms\x18\x05 \x01(\v2 .order_service.CancellationTermsR\x05terms\x12=\n" +
+	"\fcancelled_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vcancelledAt\"\xe3\x02\n" +
 	"\x12CreateOrderRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12#\n" +
 	"\rorder_address\x18\x02 \x01(\tR\forderAddress\x12%\n" +
 	"\x0eorder_location\x18\x03 \x01(\tR\rorderLocation\x129\n" +
 	"\n" +
 	"order_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\torderDate\x12?\n" +
-	"\x0eorder_time_gap\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\forderTimeGap\"/\n" +
+	"\x0eorder_time_gap\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\forderTimeGap\x12'\n" +
+	"\x0fidempotency_key\x18\x06 \x01(\tR\x0eidempotencyKey\x12\x14\n" +
+	"\x05notes\x18\a \x01(\tR\x05notes\x12-\n" +
+	"\x05point\x18\b \x01(\v2\x17.order_service.GeoPointR\x05point\"[\n" +
 	"\x13CreateOrderResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"/\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12*\n" +
+	"\x05order\x18\x02 \x01(\v2\x14.order_service.OrderR\x05order\"/\n" +
 	"\x14GetUserOrdersRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\"E\n" +
 	"\x15GetUserOrdersResponse\x12,\n" +
-	"\x06orders\x18\x01 \x03(\v2\x14.order_service.OrderR\x06orders\"3\n" +
+	"\x06orders\x18\x01 \x03(\v2\x14.order_service.OrderR\x06orders\"1\n" +
+	"\x16GetCurrentOrderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"E\n" +
+	"\x17GetCurrentOrderResponse\x12*\n" +
+	"\x05order\x18\x01 \x01(\v2\x14.order_service.OrderR\x05order\"?\n" +
 	"\x19GetAvailableOrdersRequest\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status\"J\n" +
+	"\x06status\x18\x01 \x01(\tR\x06statusJ\x04\b\x02\x10\x03J\x04\b\x03\x10\x04\"J\n" +
 	"\x1aGetAvailableOrdersResponse\x12,\n" +
 	"\x06orders\x18\x01 \x03(\v2\x14.order_service.OrderR\x06orders\"0\n" +
 	"\x13GetOrderByIdRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\"B\n" +
 	"\x14GetOrderByIdResponse\x12*\n" +
-	"\x05order\x18\x01 \x01(\v2\x14.order_service.OrderR\x05order\"/\n" +
+	"\x05order\x18\x01 \x01(\v2\x14.order_service.OrderR\x05order\"\xcb\x01\n" +
+	"\x12UpdateOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12*\n" +
+	"\x05order\x18\x03 \x01(\v2\x14.order_service.OrderR\x05order\x12;\n" +
+	"\vupdate_mask\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\x03R\aversion\"A\n" +
+	"\x13UpdateOrderResponse\x12*\n" +
+	"\x05order\x18\x01 \x01(\v2\x14.order_service.OrderR\x05order\"v\n" +
+	"\x1bGetCancellationTermsRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12!\n" +
+	"\fcancelled_by\x18\x02 \x01(\tR\vcancelledBy\x12\x19\n" +
+	"\bactor_id\x18\x03 \x01(\tR\aactorId\"p\n" +
+	"\x1cGetCancellationTermsResponse\x126\n" +
+	"\x05terms\x18\x01 \x01(\v2 .order_service.CancellationTermsR\x05terms\x12\x18\n" +
+	"\areasons\x18\x02 \x03(\tR\areasons\"\xe4\x01\n" +
 	"\x12CancelOrderRequest\x12\x19\n" +
-	"\border_id\x18\x01 \x01(\tR\aorderId\"/\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x18\n" +
+	"\acomment\x18\x03 \x01(\tR\acomment\x12!\n" +
+	"\fcancelled_by\x18\x04 \x01(\tR\vcancelledBy\x12\x19\n" +
+	"\bactor_id\x18\x05 \x01(\tR\aactorId\x12)\n" +
+	"\x10accepted_penalty\x18\x06 \x01(\tR\x0facceptedPenalty\x12\x18\n" +
+	"\aversion\x18\a \x01(\x03R\aversion\"p\n" +
 	"\x13CancelOrderResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"1\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12?\n" +
+	"\fcancellation\x18\x02 \x01(\v2\x1b.order_service.CancellationR\fcancellation\"K\n" +
 	"\x14CompleteOrderRequest\x12\x19\n" +
-	"\border_id\x18\x01 \x01(\tR\aorderId\"1\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\"1\n" +
 	"\x15CompleteOrderResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess2\xc2\x04\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"g\n" +
+	"\x15JoinOrderQueueRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x18\n" +
+	"\acomment\x18\x03 \x01(\tR\acomment\"|\n" +
+	"\x16JoinOrderQueueResponse\x126\n" +
+	"\tcandidate\x18\x01 \x01(\v2\x18.order_service.CandidateR\tcandidate\x12*\n" +
+	"\x05order\x18\x02 \x01(\v2\x14.order_service.OrderR\x05order\"N\n" +
+	"\x16LeaveOrderQueueRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\"E\n" +
+	"\x17LeaveOrderQueueResponse\x12*\n" +
+	"\x05order\x18\x01 \x01(\v2\x14.order_service.OrderR\x05order\"K\n" +
+	"\x15ListCandidatesRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"~\n" +
+	"\x16ListCandidatesResponse\x128\n" +
+	"\n" +
+	"candidates\x18\x01 \x03(\v2\x18.order_service.CandidateR\n" +
+	"candidates\x12*\n" +
+	"\x05order\x18\x02 \x01(\v2\x14.order_service.OrderR\x05order\"}\n" +
+	"\x12SelectAgentRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x19\n" +
+	"\bagent_id\x18\x03 \x01(\tR\aagentId\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x03R\aversion\"A\n" +
+	"\x13SelectAgentResponse\x12*\n" +
+	"\x05order\x18\x01 \x01(\v2\x14.order_service.OrderR\x05order\"\xf7\x01\n" +
+	"\x05Offer\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"offered_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tofferedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12*\n" +
+	"\x05order\x18\x06 \x01(\v2\x14.order_service.OrderR\x05order\"3\n" +
+	"\x16GetCurrentOfferRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"E\n" +
+	"\x17GetCurrentOfferResponse\x12*\n" +
+	"\x05offer\x18\x01 \x01(\v2\x14.order_service.OfferR\x05offer\"J\n" +
+	"\x12AcceptOfferRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\"A\n" +
+	"\x13AcceptOfferResponse\x12*\n" +
+	"\x05order\x18\x01 \x01(\v2\x14.order_service.OrderR\x05order\"K\n" +
+	"\x13DeclineOfferRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\"\x16\n" +
+	"\x14DeclineOfferResponse\"\xcb\x03\n" +
+	"\fAgentSession\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12-\n" +
+	"\x05point\x18\x03 \x01(\v2\x17.order_service.GeoPointR\x05point\x12 \n" +
+	"\tradius_km\x18\x04 \x01(\x01H\x00R\bradiusKm\x88\x01\x01\x12A\n" +
+	"\x0eavailable_from\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ravailableFrom\x12C\n" +
+	"\x0favailable_until\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x0eavailableUntil\x129\n" +
+	"\n" +
+	"started_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12<\n" +
+	"\flast_seen_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastSeenAt\x12(\n" +
+	"\x10current_order_id\x18\t \x01(\tR\x0ecurrentOrderIdB\f\n" +
+	"\n" +
+	"_radius_km\"\x96\x02\n" +
+	"\x12StartSearchRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12-\n" +
+	"\x05point\x18\x02 \x01(\v2\x17.order_service.GeoPointR\x05point\x12 \n" +
+	"\tradius_km\x18\x03 \x01(\x01H\x00R\bradiusKm\x88\x01\x01\x12A\n" +
+	"\x0eavailable_from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ravailableFrom\x12C\n" +
+	"\x0favailable_until\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x0eavailableUntilB\f\n" +
+	"\n" +
+	"_radius_km\"L\n" +
+	"\x13StartSearchResponse\x125\n" +
+	"\asession\x18\x01 \x01(\v2\x1b.order_service.AgentSessionR\asession\".\n" +
+	"\x11StopSearchRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"K\n" +
+	"\x12StopSearchResponse\x125\n" +
+	"\asession\x18\x01 \x01(\v2\x1b.order_service.AgentSessionR\asession\"\\\n" +
+	"\x10HeartbeatRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12-\n" +
+	"\x05point\x18\x02 \x01(\v2\x17.order_service.GeoPointR\x05point\"J\n" +
+	"\x11HeartbeatResponse\x125\n" +
+	"\asession\x18\x01 \x01(\v2\x1b.order_service.AgentSessionR\asession\"2\n" +
+	"\x18ListAgentSessionsRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"T\n" +
+	"\x19ListAgentSessionsResponse\x127\n" +
+	"\bsessions\x18\x01 \x03(\v2\x1b.order_service.AgentSessionR\bsessions\"\xf9\x01\n" +
+	"\rAgentLocation\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12-\n" +
+	"\x05point\x18\x02 \x01(\v2\x17.order_service.GeoPointR\x05point\x12\"\n" +
+	"\n" +
+	"accuracy_m\x18\x03 \x01(\x01H\x00R\taccuracyM\x88\x01\x01\x12 \n" +
+	"\tspeed_kmh\x18\x04 \x01(\x01H\x01R\bspeedKmh\x88\x01\x01\x12;\n" +
+	"\vrecorded_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"recordedAtB\r\n" +
+	"\v_accuracy_mB\f\n" +
+	"\n" +
+	"_speed_kmh\"\x81\x02\n" +
+	"\x15ReportLocationRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12-\n" +
+	"\x05point\x18\x02 \x01(\v2\x17.order_service.GeoPointR\x05point\x12\"\n" +
+	"\n" +
+	"accuracy_m\x18\x03 \x01(\x01H\x00R\taccuracyM\x88\x01\x01\x12 \n" +
+	"\tspeed_kmh\x18\x04 \x01(\x01H\x01R\bspeedKmh\x88\x01\x01\x12;\n" +
+	"\vrecorded_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"recordedAtB\r\n" +
+	"\v_accuracy_mB\f\n" +
+	"\n" +
+	"_speed_kmh\"P\n" +
+	"\x16ReportLocationResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\x05R\baccepted\x12\x1a\n" +
+	"\brejected\x18\x02 \x01(\x05R\brejected\"M\n" +
+	"\x17GetAgentLocationRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xb7\x01\n" +
+	"\x18GetAgentLocationResponse\x128\n" +
+	"\blocation\x18\x01 \x01(\v2\x1c.order_service.AgentLocationR\blocation\x12$\n" +
+	"\vdistance_km\x18\x02 \x01(\x01H\x00R\n" +
+	"distanceKm\x88\x01\x01\x12+\n" +
+	"\x03eta\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x03etaB\x0e\n" +
+	"\f_distance_km2\xb9\x10\n" +
 	"\fOrderService\x12V\n" +
 	"\vCreateOrder\x12!.order_service.CreateOrderRequest\x1a\".order_service.CreateOrderResponse\"\x00\x12\\\n" +
-	"\rGetUserOrders\x12#.order_service.GetUserOrdersRequest\x1a$.order_service.GetUserOrdersResponse\"\x00\x12k\n" +
+	"\rGetUserOrders\x12#.order_service.GetUserOrdersRequest\x1a$.order_service.GetUserOrdersResponse\"\x00\x12b\n" +
+	"\x0fGetCurrentOrder\x12%.order_service.GetCurrentOrderRequest\x1a&.order_service.GetCurrentOrderResponse\"\x00\x12k\n" +
 	"\x12GetAvailableOrders\x12(.order_service.GetAvailableOrdersRequest\x1a).order_service.GetAvailableOrdersResponse\"\x00\x12Y\n" +
 	"\fGetOrderById\x12\".order_service.GetOrderByIdRequest\x1a#.order_service.GetOrderByIdResponse\"\x00\x12V\n" +
+	"\vUpdateOrder\x12!.order_service.UpdateOrderRequest\x1a\".order_service.UpdateOrderResponse\"\x00\x12q\n" +
+	"\x14GetCancellationTerms\x12*.order_service.GetCancellationTermsRequest\x1a+.order_service.GetCancellationTermsResponse\"\x00\x12V\n" +
 	"\vCancelOrder\x12!.order_service.CancelOrderRequest\x1a\".order_service.CancelOrderResponse\"\x00\x12\\\n" +
-	"\rCompleteOrder\x12#.order_service.CompleteOrderRequest\x1a$.order_service.CompleteOrderResponse\"\x00B#Z!order_service/proto/order_serviceb\x06proto3"
+	"\rCompleteOrder\x12#.order_service.CompleteOrderRequest\x1a$.order_service.CompleteOrderResponse\"\x00\x12_\n" +
+	"\x0eJoinOrderQueue\x12$.order_service.JoinOrderQueueRequest\x1a%.order_service.JoinOrderQueueResponse\"\x00\x12b\n" +
+	"\x0fLeaveOrderQueue\x12%.order_service.LeaveOrderQueueRequest\x1a&.order_service.LeaveOrderQueueResponse\"\x00\x12_\n" +
+	"\x0eListCandidates\x12$.order_service.ListCandidatesRequest\x1a%.order_service.ListCandidatesResponse\"\x00\x12V\n" +
+	"\vSelectAgent\x12!.order_service.SelectAgentRequest\x1a\".order_service.SelectAgentResponse\"\x00\x12b\n" +
+	"\x0fGetCurrentOffer\x12%.order_service.GetCurrentOfferRequest\x1a&.order_service.GetCurrentOfferResponse\"\x00\x12V\n" +
+	"\vAcceptOffer\x12!.order_service.AcceptOfferRequest\x1a\".order_service.AcceptOfferResponse\"\x00\x12Y\n" +
+	"\fDeclineOffer\x12\".order_service.DeclineOfferRequest\x1a#.order_service.DeclineOfferResponse\"\x00\x12V\n" +
+	"\vStartSearch\x12!.order_service.StartSearchRequest\x1a\".order_service.StartSearchResponse\"\x00\x12S\n" +
+	"\n" +
+	"StopSearch\x12 .order_service.StopSearchRequest\x1a!.order_service.StopSearchResponse\"\x00\x12P\n" +
+	"\tHeartbeat\x12\x1f.order_service.HeartbeatRequest\x1a .order_service.HeartbeatResponse\"\x00\x12h\n" +
+	"\x11ListAgentSessions\x12'.order_service.ListAgentSessionsRequest\x1a(.order_service.ListAgentSessionsResponse\"\x00\x12a\n" +
+	"\x0eReportLocation\x12$.order_service.ReportLocationRequest\x1a%.order_service.ReportLocationResponse\"\x00(\x01\x12e\n" +
+	"\x10GetAgentLocation\x12&.order_service.GetAgentLocationRequest\x1a'.order_service.GetAgentLocationResponse\"\x00B#Z!order_service/proto/order_serviceb\x06proto3"
 
 var (
 	file_order_proto_rawDescOnce sync.Once
@@ -748,49 +3326,167 @@ func file_order_proto_rawDescGZIP() []byte {
 	return file_order_proto_rawDescData
 }
 
-var file_order_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_order_proto_msgTypes = make([]protoimpl.MessageInfo, 52)
 var file_order_proto_goTypes = []any{
-	(*Order)(nil),                      // 0: order_service.Order
-	(*CreateOrderRequest)(nil),         // 1: order_service.CreateOrderRequest
-	(*CreateOrderResponse)(nil),        // 2: order_service.CreateOrderResponse
-	(*GetUserOrdersRequest)(nil),       // 3: order_service.GetUserOrdersRequest
-	(*GetUserOrdersResponse)(nil),      // 4: order_service.GetUserOrdersResponse
-	(*GetAvailableOrdersRequest)(nil),  // 5: order_service.GetAvailableOrdersRequest
-	(*GetAvailableOrdersResponse)(nil), // 6: order_service.GetAvailableOrdersResponse
-	(*GetOrderByIdRequest)(nil),        // 7: order_service.GetOrderByIdRequest
-	(*GetOrderByIdResponse)(nil),       // 8: order_service.GetOrderByIdResponse
-	(*CancelOrderRequest)(nil),         // 9: order_service.CancelOrderRequest
-	(*CancelOrderResponse)(nil),        // 10: order_service.CancelOrderResponse
-	(*CompleteOrderRequest)(nil),       // 11: order_service.CompleteOrderRequest
-	(*CompleteOrderResponse)(nil),      // 12: order_service.CompleteOrderResponse
-	(*timestamppb.Timestamp)(nil),      // 13: google.protobuf.Timestamp
-	(*durationpb.Duration)(nil),        // 14: google.protobuf.Duration
+	(*Order)(nil),                        // 0: order_service.Order
+	(*GeoPoint)(nil),                     // 1: order_service.GeoPoint
+	(*Candidate)(nil),                    // 2: order_service.Candidate
+	(*CancellationTerms)(nil),            // 3: order_service.CancellationTerms
+	(*Cancellation)(nil),                 // 4: order_service.Cancellation
+	(*CreateOrderRequest)(nil),           // 5: order_service.CreateOrderRequest
+	(*CreateOrderResponse)(nil),          // 6: order_service.CreateOrderResponse
+	(*GetUserOrdersRequest)(nil),         // 7: order_service.GetUserOrdersRequest
+	(*GetUserOrdersResponse)(nil),        // 8: order_service.GetUserOrdersResponse
+	(*GetCurrentOrderRequest)(nil),       // 9: order_service.GetCurrentOrderRequest
+	(*GetCurrentOrderResponse)(nil),      // 10: order_service.GetCurrentOrderResponse
+	(*GetAvailableOrdersRequest)(nil),    // 11: order_service.GetAvailableOrdersRequest
+	(*GetAvailableOrdersResponse)(nil),   // 12: order_service.GetAvailableOrdersResponse
+	(*GetOrderByIdRequest)(nil),          // 13: order_service.GetOrderByIdRequest
+	(*GetOrderByIdResponse)(nil),         // 14: order_service.GetOrderByIdResponse
+	(*UpdateOrderRequest)(nil),           // 15: order_service.UpdateOrderRequest
+	(*UpdateOrderResponse)(nil),          // 16: order_service.UpdateOrderResponse
+	(*GetCancellationTermsRequest)(nil),  // 17: order_service.GetCancellationTermsRequest
+	(*GetCancellationTermsResponse)(nil), // 18: order_service.GetCancellationTermsResponse
+	(*CancelOrderRequest)(nil),           // 19: order_service.CancelOrderRequest
+	(*CancelOrderResponse)(nil),          // 20: order_service.CancelOrderResponse
+	(*CompleteOrderRequest)(nil),         // 21: order_service.CompleteOrderRequest
+	(*CompleteOrderResponse)(nil),        // 22: order_service.CompleteOrderResponse
+	(*JoinOrderQueueRequest)(nil),        // 23: order_service.JoinOrderQueueRequest
+	(*JoinOrderQueueResponse)(nil),       // 24: order_service.JoinOrderQueueResponse
+	(*LeaveOrderQueueRequest)(nil),       // 25: order_service.LeaveOrderQueueRequest
+	(*LeaveOrderQueueResponse)(nil),      // 26: order_service.LeaveOrderQueueResponse
+	(*ListCandidatesRequest)(nil),        // 27: order_service.ListCandidatesRequest
+	(*ListCandidatesResponse)(nil),       // 28: order_service.ListCandidatesResponse
+	(*SelectAgentRequest)(nil),           // 29: order_service.SelectAgentRequest
+	(*SelectAgentResponse)(nil),          // 30: order_service.SelectAgentResponse
+	(*Offer)(nil),                        // 31: order_service.Offer
+	(*GetCurrentOfferRequest)(nil),       // 32: order_service.GetCurrentOfferRequest
+	(*GetCurrentOfferResponse)(nil),      // 33: order_service.GetCurrentOfferResponse
+	(*AcceptOfferRequest)(nil),           // 34: order_service.AcceptOfferRequest
+	(*AcceptOfferResponse)(nil),          // 35: order_service.AcceptOfferResponse
+	(*DeclineOfferRequest)(nil),          // 36: order_service.DeclineOfferRequest
+	(*DeclineOfferResponse)(nil),         // 37: order_service.DeclineOfferResponse
+	(*AgentSession)(nil),                 // 38: order_service.AgentSession
+	(*StartSearchRequest)(nil),           // 39: order_service.StartSearchRequest
+	(*StartSearchResponse)(nil),          // 40: order_service.StartSearchResponse
+	(*StopSearchRequest)(nil),            // 41: order_service.StopSearchRequest
+	(*StopSearchResponse)(nil),           // 42: order_service.StopSearchResponse
+	(*HeartbeatRequest)(nil),             // 43: order_service.HeartbeatRequest
+	(*HeartbeatResponse)(nil),            // 44: order_service.HeartbeatResponse
+	(*ListAgentSessionsRequest)(nil),     // 45: order_service.ListAgentSessionsRequest
+	(*ListAgentSessionsResponse)(nil),    // 46: order_service.ListAgentSessionsResponse
+	(*AgentLocation)(nil),                // 47: order_service.AgentLocation
+	(*ReportLocationRequest)(nil),        // 48: order_service.ReportLocationRequest
+	(*ReportLocationResponse)(nil),       // 49: order_service.ReportLocationResponse
+	(*GetAgentLocationRequest)(nil),      // 50: order_service.GetAgentLocationRequest
+	(*GetAgentLocationResponse)(nil),     // 51: order_service.GetAgentLocationResponse
+	(*timestamppb.Timestamp)(nil),        // 52: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),          // 53: google.protobuf.Duration
+	(*fieldmaskpb.FieldMask)(nil),        // 54: google.protobuf.FieldMask
 }
 var file_order_proto_depIdxs = []int32{
-	13, // 0: order_service.Order.order_date:type_name -> google.protobuf.Timestamp
-	14, // 1: order_service.Order.order_time_gap:type_name -> google.protobuf.Duration
-	13, // 2: order_service.CreateOrderRequest.order_date:type_name -> google.protobuf.Timestamp
-	14, // 3: order_service.CreateOrderRequest.order_time_gap:type_name -> google.protobuf.Duration
-	0,  // 4: order_service.GetUserOrdersResponse.orders:type_name -> order_service.Order
-	0,  // 5: order_service.GetAvailableOrdersResponse.orders:type_name -> order_service.Order
-	0,  // 6: order_service.GetOrderByIdResponse.order:type_name -> order_service.Order
-	1,  // 7: order_service.OrderService.CreateOrder:input_type -> order_service.CreateOrderRequest
-	3,  // 8: order_service.OrderService.GetUserOrders:input_type -> order_service.GetUserOrdersRequest
-	5,  // 9: order_service.OrderService.GetAvailableOrders:input_type -> order_service.GetAvailableOrdersRequest
-	7,  // 10: order_service.OrderService.GetOrderById:input_type -> order_service.GetOrderByIdRequest
-	9,  // 11: order_service.OrderService.CancelOrder:input_type -> order_service.CancelOrderRequest
-	11, // 12: order_service.OrderService.CompleteOrder:input_type -> order_service.CompleteOrderRequest
-	2,  // 13: order_service.OrderService.CreateOrder:output_type -> order_service.CreateOrderResponse
-	4,  // 14: order_service.OrderService.GetUserOrders:output_type -> order_service.GetUserOrdersResponse
-	6,  // 15: order_service.OrderService.GetAvailableOrders:output_type -> order_service.GetAvailableOrdersResponse
-	8,  // 16: order_service.OrderService.GetOrderById:output_type -> order_service.GetOrderByIdResponse
-	10, // 17: order_service.OrderService.CancelOrder:output_type -> order_service.CancelOrderResponse
-	12, // 18: order_service.OrderService.CompleteOrder:output_type -> order_service.CompleteOrderResponse
-	13, // [13:19] is the sub-list for method output_type
-	7,  // [7:13] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	52, // 0: order_service.Order.order_date:type_name -> google.protobuf.Timestamp
+	53, // 1: order_service.Order.order_time_gap:type_name -> google.protobuf.Duration
+	52, // 2: order_service.Order.created_at:type_name -> google.protobuf.Timestamp
+	52, // 3: order_service.Order.updated_at:type_name -> google.protobuf.Timestamp
+	4,  // 4: order_service.Order.cancellation:type_name -> order_service.Cancellation
+	52, // 5: order_service.Order.matching_deadline:type_name -> google.protobuf.Timestamp
+	1,  // 6: order_service.Order.point:type_name -> order_service.GeoPoint
+	52, // 7: order_service.Candidate.joined_at:type_name -> google.protobuf.Timestamp
+	3,  // 8: order_service.Cancellation.terms:type_name -> order_service.CancellationTerms
+	52, // 9: order_service.Cancellation.cancelled_at:type_name -> google.protobuf.Timestamp
+	52, // 10: order_service.CreateOrderRequest.order_date:type_name -> google.protobuf.Timestamp
+	53, // 11: order_service.CreateOrderRequest.order_time_gap:type_name -> google.protobuf.Duration
+	1,  // 12: order_service.CreateOrderRequest.point:type_name -> order_service.GeoPoint
+	0,  // 13: order_service.CreateOrderResponse.order:type_name -> order_service.Order
+	0,  // 14: order_service.GetUserOrdersResponse.orders:type_name -> order_service.Order
+	0,  // 15: order_service.GetCurrentOrderResponse.order:type_name -> order_service.Order
+	0,  // 16: order_service.GetAvailableOrdersResponse.orders:type_name -> order_service.Order
+	0,  // 17: order_service.GetOrderByIdResponse.order:type_name -> order_service.Order
+	0,  // 18: order_service.UpdateOrderRequest.order:type_name -> order_service.Order
+	54, // 19: order_service.UpdateOrderRequest.update_mask:type_name -> google.protobuf.FieldMask
+	0,  // 20: order_service.UpdateOrderResponse.order:type_name -> order_service.Order
+	3,  // 21: order_service.GetCancellationTermsResponse.terms:type_name -> order_service.CancellationTerms
+	4,  // 22: order_service.CancelOrderResponse.cancellation:type_name -> order_service.Cancellation
+	2,  // 23: order_service.JoinOrderQueueResponse.candidate:type_name -> order_service.Candidate
+	0,  // 24: order_service.JoinOrderQueueResponse.order:type_name -> order_service.Order
+	0,  // 25: order_service.LeaveOrderQueueResponse.order:type_name -> order_service.Order
+	2,  // 26: order_service.ListCandidatesResponse.candidates:type_name -> order_service.Candidate
+	0,  // 27: order_service.ListCandidatesResponse.order:type_name -> order_service.Order
+	0,  // 28: order_service.SelectAgentResponse.order:type_name -> order_service.Order
+	52, // 29: order_service.Offer.offered_at:type_name -> google.protobuf.Timestamp
+	52, // 30: order_service.Offer.expires_at:type_name -> google.protobuf.Timestamp
+	0,  // 31: order_service.Offer.order:type_name -> order_service.Order
+	31, // 32: order_service.GetCurrentOfferResponse.offer:type_name -> order_service.Offer
+	0,  // 33: order_service.AcceptOfferResponse.order:type_name -> order_service.Order
+	1,  // 34: order_service.AgentSession.point:type_name -> order_service.GeoPoint
+	52, // 35: order_service.AgentSession.available_from:type_name -> google.protobuf.Timestamp
+	52, // 36: order_service.AgentSession.available_until:type_name -> google.protobuf.Timestamp
+	52, // 37: order_service.AgentSession.started_at:type_name -> google.protobuf.Timestamp
+	52, // 38: order_service.AgentSession.last_seen_at:type_name -> google.protobuf.Timestamp
+	1,  // 39: order_service.StartSearchRequest.point:type_name -> order_service.GeoPoint
+	52, // 40: order_service.StartSearchRequest.available_from:type_name -> google.protobuf.Timestamp
+	52, // 41: order_service.StartSearchRequest.available_until:type_name -> google.protobuf.Timestamp
+	38, // 42: order_service.StartSearchResponse.session:type_name -> order_service.AgentSession
+	38, // 43: order_service.StopSearchResponse.session:type_name -> order_service.AgentSession
+	1,  // 44: order_service.HeartbeatRequest.point:type_name -> order_service.GeoPoint
+	38, // 45: order_service.HeartbeatResponse.session:type_name -> order_service.AgentSession
+	38, // 46: order_service.ListAgentSessionsResponse.sessions:type_name -> order_service.AgentSession
+	1,  // 47: order_service.AgentLocation.point:type_name -> order_service.GeoPoint
+	52, // 48: order_service.AgentLocation.recorded_at:type_name -> google.protobuf.Timestamp
+	1,  // 49: order_service.ReportLocationRequest.point:type_name -> order_service.GeoPoint
+	52, // 50: order_service.ReportLocationRequest.recorded_at:type_name -> google.protobuf.Timestamp
+	47, // 51: order_service.GetAgentLocationResponse.location:type_name -> order_service.AgentLocation
+	53, // 52: order_service.GetAgentLocationResponse.eta:type_name -> google.protobuf.Duration
+	5,  // 53: order_service.OrderService.CreateOrder:input_type -> order_service.CreateOrderRequest
+	7,  // 54: order_service.OrderService.GetUserOrders:input_type -> order_service.GetUserOrdersRequest
+	9,  // 55: order_service.OrderService.GetCurrentOrder:input_type -> order_service.GetCurrentOrderRequest
+	11, // 56: order_service.OrderService.GetAvailableOrders:input_type -> order_service.GetAvailableOrdersRequest
+	13, // 57: order_service.OrderService.GetOrderById:input_type -> order_service.GetOrderByIdRequest
+	15, // 58: order_service.OrderService.UpdateOrder:input_type -> order_service.UpdateOrderRequest
+	17, // 59: order_service.OrderService.GetCancellationTerms:input_type -> order_service.GetCancellationTermsRequest
+	19, // 60: order_service.OrderService.CancelOrder:input_type -> order_service.CancelOrderRequest
+	21, // 61: order_service.OrderService.CompleteOrder:input_type -> order_service.CompleteOrderRequest
+	23, // 62: order_service.OrderService.JoinOrderQueue:input_type -> order_service.JoinOrderQueueRequest
+	25, // 63: order_service.OrderService.LeaveOrderQueue:input_type -> order_service.LeaveOrderQueueRequest
+	27, // 64: order_service.OrderService.ListCandidates:input_type -> order_service.ListCandidatesRequest
+	29, // 65: order_service.OrderService.SelectAgent:input_type -> order_service.SelectAgentRequest
+	32, // 66: order_service.OrderService.GetCurrentOffer:input_type -> order_service.GetCurrentOfferRequest
+	34, // 67: order_service.OrderService.AcceptOffer:input_type -> order_service.AcceptOfferRequest
+	36, // 68: order_service.OrderService.DeclineOffer:input_type -> order_service.DeclineOfferRequest
+	39, // 69: order_service.OrderService.StartSearch:input_type -> order_service.StartSearchRequest
+	41, // 70: order_service.OrderService.StopSearch:input_type -> order_service.StopSearchRequest
+	43, // 71: order_service.OrderService.Heartbeat:input_type -> order_service.HeartbeatRequest
+	45, // 72: order_service.OrderService.ListAgentSessions:input_type -> order_service.ListAgentSessionsRequest
+	48, // 73: order_service.OrderService.ReportLocation:input_type -> order_service.ReportLocationRequest
+	50, // 74: order_service.OrderService.GetAgentLocation:input_type -> order_service.GetAgentLocationRequest
+	6,  // 75: order_service.OrderService.CreateOrder:output_type -> order_service.CreateOrderResponse
+	8,  // 76: order_service.OrderService.GetUserOrders:output_type -> order_service.GetUserOrdersResponse
+	10, // 77: order_service.OrderService.GetCurrentOrder:output_type -> order_service.GetCurrentOrderResponse
+	12, // 78: order_service.OrderService.GetAvailableOrders:output_type -> order_service.GetAvailableOrdersResponse
+	14, // 79: order_service.OrderService.GetOrderById:output_type -> order_service.GetOrderByIdResponse
+	16, // 80: order_service.OrderService.UpdateOrder:output_type -> order_service.UpdateOrderResponse
+	18, // 81: order_service.OrderService.GetCancellationTerms:output_type -> order_service.GetCancellationTermsResponse
+	20, // 82: order_service.OrderService.CancelOrder:output_type -> order_service.CancelOrderResponse
+	22, // 83: order_service.OrderService.CompleteOrder:output_type -> order_service.CompleteOrderResponse
+	24, // 84: order_service.OrderService.JoinOrderQueue:output_type -> order_service.JoinOrderQueueResponse
+	26, // 85: order_service.OrderService.LeaveOrderQueue:output_type -> order_service.LeaveOrderQueueResponse
+	28, // 86: order_service.OrderService.ListCandidates:output_type -> order_service.ListCandidatesResponse
+	30, // 87: order_service.OrderService.SelectAgent:output_type -> order_service.SelectAgentResponse
+	33, // 88: order_service.OrderService.GetCurrentOffer:output_type -> order_service.GetCurrentOfferResponse
+	35, // 89: order_service.OrderService.AcceptOffer:output_type -> order_service.AcceptOfferResponse
+	37, // 90: order_service.OrderService.DeclineOffer:output_type -> order_service.DeclineOfferResponse
+	40, // 91: order_service.OrderService.StartSearch:output_type -> order_service.StartSearchResponse
+	42, // 92: order_service.OrderService.StopSearch:output_type -> order_service.StopSearchResponse
+	44, // 93: order_service.OrderService.Heartbeat:output_type -> order_service.HeartbeatResponse
+	46, // 94: order_service.OrderService.ListAgentSessions:output_type -> order_service.ListAgentSessionsResponse
+	49, // 95: order_service.OrderService.ReportLocation:output_type -> order_service.ReportLocationResponse
+	51, // 96: order_service.OrderService.GetAgentLocation:output_type -> order_service.GetAgentLocationResponse
+	75, // [75:97] is the sub-list for method output_type
+	53, // [53:75] is the sub-list for method input_type
+	53, // [53:53] is the sub-list for extension type_name
+	53, // [53:53] is the sub-list for extension extendee
+	0,  // [0:53] is the sub-list for field type_name
 }
 
 func init() { file_order_proto_init() }
@@ -798,13 +3494,18 @@ func file_order_proto_init() {
 	if File_order_proto != nil {
 		return
 	}
+	file_order_proto_msgTypes[38].OneofWrappers = []any{}
+	file_order_proto_msgTypes[39].OneofWrappers = []any{}
+	file_order_proto_msgTypes[47].OneofWrappers = []any{}
+	file_order_proto_msgTypes[48].OneofWrappers = []any{}
+	file_order_proto_msgTypes[51].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_order_proto_rawDesc), len(file_order_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   52,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,12 +19,28 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	OrderService_CreateOrder_FullMethodName        = "/order_service.OrderService/CreateOrder"
-	OrderService_GetUserOrders_FullMethodName      = "/order_service.OrderService/GetUserOrders"
-	OrderService_GetAvailableOrders_FullMethodName = "/order_service.OrderService/GetAvailableOrders"
-	OrderService_GetOrderById_FullMethodName       = "/order_service.OrderService/GetOrderById"
-	OrderService_CancelOrder_FullMethodName        = "/order_service.OrderService/CancelOrder"
-	OrderService_CompleteOrder_FullMethodName      = "/order_service.OrderService/CompleteOrder"
+	OrderService_CreateOrder_FullMethodName          = "/order_service.OrderService/CreateOrder"
+	OrderService_GetUserOrders_FullMethodName        = "/order_service.OrderService/GetUserOrders"
+	OrderService_GetCurrentOrder_FullMethodName      = "/order_service.OrderService/GetCurrentOrder"
+	OrderService_GetAvailableOrders_FullMethodName   = "/order_service.OrderService/GetAvailableOrders"
+	OrderService_GetOrderById_FullMethodName         = "/order_service.OrderService/GetOrderById"
+	OrderService_UpdateOrder_FullMethodName          = "/order_service.OrderService/UpdateOrder"
+	OrderService_GetCancellationTerms_FullMethodName = "/order_service.OrderService/GetCancellationTerms"
+	OrderService_CancelOrder_FullMethodName          = "/order_service.OrderService/CancelOrder"
+	OrderService_CompleteOrder_FullMethodName        = "/order_service.OrderService/CompleteOrder"
+	OrderService_JoinOrderQueue_FullMethodName       = "/order_service.OrderService/JoinOrderQueue"
+	OrderService_LeaveOrderQueue_FullMethodName      = "/order_service.OrderService/LeaveOrderQueue"
+	OrderService_ListCandidates_FullMethodName       = "/order_service.OrderService/ListCandidates"
+	OrderService_SelectAgent_FullMethodName          = "/order_service.OrderService/SelectAgent"
+	OrderService_GetCurrentOffer_FullMethodName      = "/order_service.OrderService/GetCurrentOffer"
+	OrderService_AcceptOffer_FullMethodName          = "/order_service.OrderService/AcceptOffer"
+	OrderService_DeclineOffer_FullMethodName         = "/order_service.OrderService/DeclineOffer"
+	OrderService_StartSearch_FullMethodName          = "/order_service.OrderService/StartSearch"
+	OrderService_StopSearch_FullMethodName           = "/order_service.OrderService/StopSearch"
+	OrderService_Heartbeat_FullMethodName            = "/order_service.OrderService/Heartbeat"
+	OrderService_ListAgentSessions_FullMethodName    = "/order_service.OrderService/ListAgentSessions"
+	OrderService_ReportLocation_FullMethodName       = "/order_service.OrderService/ReportLocation"
+	OrderService_GetAgentLocation_FullMethodName     = "/order_service.OrderService/GetAgentLocation"
 )
 
 // OrderServiceClient is the client API for OrderService service.
@@ -33,10 +49,29 @@ const (
 type OrderServiceClient interface {
 	CreateOrder(ctx context.Context, in *CreateOrderRequest, opts ...grpc.CallOption) (*CreateOrderResponse, error)
 	GetUserOrders(ctx context.Context, in *GetUserOrdersRequest, opts ...grpc.CallOption) (*GetUserOrdersResponse, error)
+	GetCurrentOrder(ctx context.Context, in *GetCurrentOrderRequest, opts ...grpc.CallOption) (*GetCurrentOrderResponse, error)
 	GetAvailableOrders(ctx context.Context, in *GetAvailableOrdersRequest, opts ...grpc.CallOption) (*GetAvailableOrdersResponse, error)
 	GetOrderById(ctx context.Context, in *GetOrderByIdRequest, opts ...grpc.CallOption) (*GetOrderByIdResponse, error)
+	UpdateOrder(ctx context.Context, in *UpdateOrderRequest, opts ...grpc.CallOption) (*UpdateOrderResponse, error)
+	GetCancellationTerms(ctx context.Context, in *GetCancellationTermsRequest, opts ...grpc.CallOption) (*GetCancellationTermsResponse, error)
 	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error)
 	CompleteOrder(ctx context.Context, in *CompleteOrderRequest, opts ...grpc.CallOption) (*CompleteOrderResponse, error)
+	// Очередь исполнителей на заказ: исполнители встают в неё, клиент выбирает одного
+	JoinOrderQueue(ctx context.Context, in *JoinOrderQueueRequest, opts ...grpc.CallOption) (*JoinOrderQueueResponse, error)
+	LeaveOrderQueue(ctx context.Context, in *LeaveOrderQueueRequest, opts ...grpc.CallOption) (*LeaveOrderQueueResponse, error)
+	ListCandidates(ctx context.Context, in *ListCandidatesRequest, opts ...grpc.CallOption) (*ListCandidatesResponse, error)
+	SelectAgent(ctx context.Context, in *SelectAgentRequest, opts ...grpc.CallOption) (*SelectAgentResponse, error)
+	// Автоматическое назначение: заказ предлагается исполнителям на линии по одному
+	GetCurrentOffer(ctx context.Context, in *GetCurrentOfferRequest, opts ...grpc.CallOption) (*GetCurrentOfferResponse, error)
+	AcceptOffer(ctx context.Context, in *AcceptOfferRequest, opts ...grpc.CallOption) (*AcceptOfferResponse, error)
+	DeclineOffer(ctx context.Context, in *DeclineOfferRequest, opts ...grpc.CallOption) (*DeclineOfferResponse, error)
+	// Сеансы исполнителей: заказы предлагаются только тем, кто ищет их и присылает heartbeat
+	StartSearch(ctx context.Context, in *StartSearchRequest, opts ...grpc.CallOption) (*StartSearchResponse, error)
+	StopSearch(ctx context.Context, in *StopSearchRequest, opts ...grpc.CallOption) (*StopSearchResponse, error)
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	ListAgentSessions(ctx context.Context, in *ListAgentSessionsRequest, opts ...grpc.CallOption) (*ListAgentSessionsResponse, error)
+	ReportLocation(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ReportLocationRequest, ReportLocationResponse], error)
+	GetAgentLocation(ctx context.Context, in *GetAgentLocationRequest, opts ...grpc.CallOption) (*GetAgentLocationResponse, error)
 }
 
 type orderServiceClient struct {
@@ -67,6 +102,16 @@ func (c *orderServiceClient) GetUserOrders(ctx context.Context, in *GetUserOrder
 	return out, nil
 }
 
+func (c *orderServiceClient) GetCurrentOrder(ctx context.Context, in *GetCurrentOrderRequest, opts ...grpc.CallOption) (*GetCurrentOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCurrentOrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetCurrentOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orderServiceClient) GetAvailableOrders(ctx context.Context, in *GetAvailableOrdersRequest, opts ...grpc.CallOption) (*GetAvailableOrdersResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetAvailableOrdersResponse)
@@ -87,6 +132,26 @@ func (c *orderServiceClient) GetOrderById(ctx context.Context, in *GetOrderByIdR
 	return out, nil
 }
 
+func (c *orderServiceClient) UpdateOrder(ctx context.Context, in *UpdateOrderRequest, opts ...grpc.CallOption) (*UpdateOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateOrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_UpdateOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetCancellationTerms(ctx context.Context, in *GetCancellationTermsRequest, opts ...grpc.CallOption) (*GetCancellationTermsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCancellationTermsResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetCancellationTerms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orderServiceClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CancelOrderResponse)
@@ -107,16 +172,168 @@ func (c *orderServiceClient) CompleteOrder(ctx context.Context, in *CompleteOrde
 	return out, nil
 }
 
+func (c *orderServiceClient) JoinOrderQueue(ctx context.Context, in *JoinOrderQueueRequest, opts ...grpc.CallOption) (*JoinOrderQueueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JoinOrderQueueResponse)
+	err := c.cc.Invoke(ctx, OrderService_JoinOrderQueue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) LeaveOrderQueue(ctx context.Context, in *LeaveOrderQueueRequest, opts ...grpc.CallOption) (*LeaveOrderQueueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LeaveOrderQueueResponse)
+	err := c.cc.Invoke(ctx, OrderService_LeaveOrderQueue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListCandidates(ctx context.Context, in *ListCandidatesRequest, opts ...grpc.CallOption) (*ListCandidatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCandidatesResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListCandidates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) SelectAgent(ctx context.Context, in *SelectAgentRequest, opts ...grpc.CallOption) (*SelectAgentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SelectAgentResponse)
+	err := c.cc.Invoke(ctx, OrderService_SelectAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetCurrentOffer(ctx context.Context, in *GetCurrentOfferRequest, opts ...grpc.CallOption) (*GetCurrentOfferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCurrentOfferResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetCurrentOffer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) AcceptOffer(ctx context.Context, in *AcceptOfferRequest, opts ...grpc.CallOption) (*AcceptOfferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcceptOfferResponse)
+	err := c.cc.Invoke(ctx, OrderService_AcceptOffer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) DeclineOffer(ctx context.Context, in *DeclineOfferRequest, opts ...grpc.CallOption) (*DeclineOfferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeclineOfferResponse)
+	err := c.cc.Invoke(ctx, OrderService_DeclineOffer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) StartSearch(ctx context.Context, in *StartSearchRequest, opts ...grpc.CallOption) (*StartSearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartSearchResponse)
+	err := c.cc.Invoke(ctx, OrderService_StartSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) StopSearch(ctx context.Context, in *StopSearchRequest, opts ...grpc.CallOption) (*StopSearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StopSearchResponse)
+	err := c.cc.Invoke(ctx, OrderService_StopSearch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, OrderService_Heartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ListAgentSessions(ctx context.Context, in *ListAgentSessionsRequest, opts ...grpc.CallOption) (*ListAgentSessionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAgentSessionsResponse)
+	err := c.cc.Invoke(ctx, OrderService_ListAgentSessions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ReportLocation(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ReportLocationRequest, ReportLocationResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[0], OrderService_ReportLocation_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReportLocationRequest, ReportLocationResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_ReportLocationClient = grpc.ClientStreamingClient[ReportLocationRequest, ReportLocationResponse]
+
+func (c *orderServiceClient) GetAgentLocation(ctx context.Context, in *GetAgentLocationRequest, opts ...grpc.CallOption) (*GetAgentLocationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAgentLocationResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetAgentLocation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrderServiceServer is the server API for OrderService service.
 // All implementations should embed UnimplementedOrderServiceServer
 // for forward compatibility.
 type OrderServiceServer interface {
 	CreateOrder(context.Context, *CreateOrderRequest) (*CreateOrderResponse, error)
 	GetUserOrders(context.Context, *GetUserOrdersRequest) (*GetUserOrdersResponse, error)
+	GetCurrentOrder(context.Context, *GetCurrentOrderRequest) (*GetCurrentOrderResponse, error)
 	GetAvailableOrders(context.Context, *GetAvailableOrdersRequest) (*GetAvailableOrdersResponse, error)
 	GetOrderById(context.Context, *GetOrderByIdRequest) (*GetOrderByIdResponse, error)
+	UpdateOrder(context.Context, *UpdateOrderRequest) (*UpdateOrderResponse, error)
+	GetCancellationTerms(context.Context, *GetCancellationTermsRequest) (*GetCancellationTermsResponse, error)
 	CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error)
 	CompleteOrder(context.Context, *CompleteOrderRequest) (*CompleteOrderResponse, error)
+	// Очередь исполнителей на заказ: исполнители встают в неё, клиент выбирает одного
+	JoinOrderQueue(context.Context, *JoinOrderQueueRequest) (*JoinOrderQueueResponse, error)
+	LeaveOrderQueue(context.Context, *LeaveOrderQueueRequest) (*LeaveOrderQueueResponse, error)
+	ListCandidates(context.Context, *ListCandidatesRequest) (*ListCandidatesResponse, error)
+	SelectAgent(context.Context, *SelectAgentRequest) (*SelectAgentResponse, error)
+	// Автоматическое назначение: заказ предлагается исполнителям на линии по одному
+	GetCurrentOffer(context.Context, *GetCurrentOfferRequest) (*GetCurrentOfferResponse, error)
+	AcceptOffer(context.Context, *AcceptOfferRequest) (*AcceptOfferResponse, error)
+	DeclineOffer(context.Context, *DeclineOfferRequest) (*DeclineOfferResponse, error)
+	// Сеансы исполнителей: заказы предлагаются только тем, кто ищет их и присылает heartbeat
+	StartSearch(context.Context, *StartSearchRequest) (*StartSearchResponse, error)
+	StopSearch(context.Context, *StopSearchRequest) (*StopSearchResponse, error)
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
+	ListAgentSessions(context.Context, *ListAgentSessionsRequest) (*ListAgentSessionsResponse, error)
+	ReportLocation(grpc.ClientStreamingServer[ReportLocationRequest, ReportLocationResponse]) error
+	GetAgentLocation(context.Context, *GetAgentLocationRequest) (*GetAgentLocationResponse, error)
 }
 
 // UnimplementedOrderServiceServer should be embedded to have
@@ -132,18 +349,66 @@ func (UnimplementedOrderServiceServer) CreateOrder(context.Context, *CreateOrder
 func (UnimplementedOrderServiceServer) GetUserOrders(context.Context, *GetUserOrdersRequest) (*GetUserOrdersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserOrders not implemented")
 }
+func (UnimplementedOrderServiceServer) GetCurrentOrder(context.Context, *GetCurrentOrderRequest) (*GetCurrentOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCurrentOrder not implemented")
+}
 func (UnimplementedOrderServiceServer) GetAvailableOrders(context.Context, *GetAvailableOrdersRequest) (*GetAvailableOrdersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAvailableOrders not implemented")
 }
 func (UnimplementedOrderServiceServer) GetOrderById(context.Context, *GetOrderByIdRequest) (*GetOrderByIdResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetOrderById not implemented")
 }
+func (UnimplementedOrderServiceServer) UpdateOrder(context.Context, *UpdateOrderRequest) (*UpdateOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateOrder not implemented")
+}
+func (UnimplementedOrderServiceServer) GetCancellationTerms(context.Context, *GetCancellationTermsRequest) (*GetCancellationTermsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCancellationTerms not implemented")
+}
 func (UnimplementedOrderServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CancelOrder not implemented")
 }
 func (UnimplementedOrderServiceServer) CompleteOrder(context.Context, *CompleteOrderRequest) (*CompleteOrderResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CompleteOrder not implemented")
 }
+func (UnimplementedOrderServiceServer) JoinOrderQueue(context.Context, *JoinOrderQueueRequest) (*JoinOrderQueueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JoinOrderQueue not implemented")
+}
+func (UnimplementedOrderServiceServer) LeaveOrderQueue(context.Context, *LeaveOrderQueueRequest) (*LeaveOrderQueueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaveOrderQueue not implemented")
+}
+func (UnimplementedOrderServiceServer) ListCandidates(context.Context, *ListCandidatesRequest) (*ListCandidatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCandidates not implemented")
+}
+func (UnimplementedOrderServiceServer) SelectAgent(context.Context, *SelectAgentRequest) (*SelectAgentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SelectAgent not implemented")
+}
+func (UnimplementedOrderServiceServer) GetCurrentOffer(context.Context, *GetCurrentOfferRequest) (*GetCurrentOfferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCurrentOffer not implemented")
+}
+func (UnimplementedOrderServiceServer) AcceptOffer(context.Context, *AcceptOfferRequest) (*AcceptOfferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptOffer not implemented")
+}
+func (UnimplementedOrderServiceServer) DeclineOffer(context.Context, *DeclineOfferRequest) (*DeclineOfferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeclineOffer not implemented")
+}
+func (UnimplementedOrderServiceServer) StartSearch(context.Context, *StartSearchRequest) (*StartSearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartSearch not implemented")
+}
+func (UnimplementedOrderServiceServer) StopSearch(context.Context, *StopSearchRequest) (*StopSearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopSearch not implemented")
+}
+func (UnimplementedOrderServiceServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedOrderServiceServer) ListAgentSessions(context.Context, *ListAgentSessionsRequest) (*ListAgentSessionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAgentSessions not implemented")
+}
+func (UnimplementedOrderServiceServer) ReportLocation(grpc.ClientStreamingServer[ReportLocationRequest, ReportLocationResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ReportLocation not implemented")
+}
+func (UnimplementedOrderServiceServer) GetAgentLocation(context.Context, *GetAgentLocationRequest) (*GetAgentLocationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAgentLocation not implemented")
+}
 func (UnimplementedOrderServiceServer) testEmbeddedByValue() {}
 
 // UnsafeOrderServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -200,6 +465,24 @@ func _OrderService_GetUserOrders_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_GetCurrentOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCurrentOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetCurrentOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetCurrentOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetCurrentOrder(ctx, req.(*GetCurrentOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _OrderService_GetAvailableOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetAvailableOrdersRequest)
 	if err := dec(in); err != nil {
@@ -236,6 +519,42 @@ func _OrderService_GetOrderById_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_UpdateOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).UpdateOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_UpdateOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).UpdateOrder(ctx, req.(*UpdateOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetCancellationTerms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCancellationTermsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetCancellationTerms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetCancellationTerms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetCancellationTerms(ctx, req.(*GetCancellationTermsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _OrderService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CancelOrderRequest)
 	if err := dec(in); err != nil {
@@ -272,6 +591,229 @@ func _OrderService_CompleteOrder_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_JoinOrderQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinOrderQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).JoinOrderQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_JoinOrderQueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).JoinOrderQueue(ctx, req.(*JoinOrderQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_LeaveOrderQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaveOrderQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).LeaveOrderQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_LeaveOrderQueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).LeaveOrderQueue(ctx, req.(*LeaveOrderQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListCandidates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCandidatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListCandidates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListCandidates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListCandidates(ctx, req.(*ListCandidatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_SelectAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SelectAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).SelectAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_SelectAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).SelectAgent(ctx, req.(*SelectAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetCurrentOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCurrentOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetCurrentOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetCurrentOffer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetCurrentOffer(ctx, req.(*GetCurrentOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_AcceptOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).AcceptOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_AcceptOffer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).AcceptOffer(ctx, req.(*AcceptOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_DeclineOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeclineOfferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).DeclineOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_DeclineOffer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).DeclineOffer(ctx, req.(*DeclineOfferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_StartSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).StartSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_StartSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).StartSearch(ctx, req.(*StartSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_StopSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopSearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).StopSearch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_StopSearch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).StopSearch(ctx, req.(*StopSearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ListAgentSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAgentSessionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ListAgentSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ListAgentSessions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ListAgentSessions(ctx, req.(*ListAgentSessionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ReportLocation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OrderServiceServer).ReportLocation(&grpc.GenericServerStream[ReportLocationRequest, ReportLocationResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_ReportLocationServer = grpc.ClientStreamingServer[ReportLocationRequest, ReportLocationResponse]
+
+func _OrderService_GetAgentLocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAgentLocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetAgentLocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetAgentLocation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetAgentLocation(ctx, req.(*GetAgentLocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -287,6 +829,10 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUserOrders",
 			Handler:    _OrderService_GetUserOrders_Handler,
 		},
+		{
+			MethodName: "GetCurrentOrder",
+			Handler:    _OrderService_GetCurrentOrder_Handler,
+		},
 		{
 			MethodName: "GetAvailableOrders",
 			Handler:    _OrderService_GetAvailableOrders_Handler,
@@ -295,6 +841,14 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetOrderById",
 			Handler:    _OrderService_GetOrderById_Handler,
 		},
+		{
+			MethodName: "UpdateOrder",
+			Handler:    _OrderService_UpdateOrder_Handler,
+		},
+		{
+			MethodName: "GetCancellationTerms",
+			Handler:    _OrderService_GetCancellationTerms_Handler,
+		},
 		{
 			MethodName: "CancelOrder",
 			Handler:    _OrderService_CancelOrder_Handler,
@@ -303,7 +857,61 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CompleteOrder",
 			Handler:    _OrderService_CompleteOrder_Handler,
 		},
+		{
+			MethodName: "JoinOrderQueue",
+			Handler:    _OrderService_JoinOrderQueue_Handler,
+		},
+		{
+			MethodName: "LeaveOrderQueue",
+			Handler:    _OrderService_LeaveOrderQueue_Handler,
+		},
+		{
+			MethodName: "ListCandidates",
+			Handler:    _OrderService_ListCandidates_Handler,
+		},
+		{
+			MethodName: "SelectAgent",
+			Handler:    _OrderService_SelectAgent_Handler,
+		},
+		{
+			MethodName: "GetCurrentOffer",
+			Handler:    _OrderService_GetCurrentOffer_Handler,
+		},
+		{
+			MethodName: "AcceptOffer",
+			Handler:    _OrderService_AcceptOffer_Handler,
+		},
+		{
+			MethodName: "DeclineOffer",
+			Handler:    _OrderService_DeclineOffer_Handler,
+		},
+		{
+			MethodName: "StartSearch",
+			Handler:    _OrderService_StartSearch_Handler,
+		},
+		{
+			MethodName: "StopSearch",
+			Handler:    _OrderService_StopSearch_Handler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _OrderService_Heartbeat_Handler,
+		},
+		{
+			MethodName: "ListAgentSessions",
+			Handler:    _OrderService_ListAgentSessions_Handler,
+		},
+		{
+			MethodName: "GetAgentLocation",
+			Handler:    _OrderService_GetAgentLocation_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReportLocation",
+			Handler:       _OrderService_ReportLocation_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "order.proto",
 }
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"orderqctl/internal/commands"
+)
+
+func main() {
+	if err := commands.Run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "orderqctl:", err)
+		os.Exit(1)
+	}
+}
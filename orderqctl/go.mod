@@ -0,0 +1,20 @@
+module orderqctl
+
+go 1.24.2
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/jackc/pgx/v5 v5.7.4
+	github.com/streadway/amqp v1.1.0
+	google.golang.org/protobuf v1.36.6
+	orderq/pkg/events v0.0.0
+)
+
+require (
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	golang.org/x/crypto v0.31.0 // indirect
+	golang.org/x/text v0.22.0 // indirect
+)
+
+replace orderq/pkg/events => ../pkg/events
@@ -0,0 +1,265 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"orderq/pkg/events"
+
+	"github.com/streadway/amqp"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// defaultListLimit ограничивает число сообщений, которые list забирает из очереди.
+const defaultListLimit = 50
+
+// requeueLimit ограничивает число сообщений, которые requeue просматривает за раз.
+const requeueLimit = 1000
+
+func dlqList(args []string, out io.Writer) error {
+	var conn connection
+	fs := newFlagSet("dlq list")
+	conn.register(fs)
+	queue := fs.String("queue", "", "consumer queue, all notification queues by default")
+	limit := fs.Int("limit", defaultListLimit, "maximum number of messages per queue")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	queues := consumerQueues()
+	if *queue != "" {
+		queues = []string{*queue}
+	}
+
+	ch, closeFn, err := openChannel(conn.amqpURL)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "QUEUE\tMESSAGE ID\tTYPE\tRETRIES\tPUBLISHED\tERROR")
+	for _, q := range queues {
+		msgs, err := fetch(ch, events.DeadLetterQueue(q), *limit)
+		if err != nil {
+			return err
+		}
+		for _, msg := range msgs {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%v\t%s\t%s\n",
+				q,
+				msg.MessageId,
+				schemaOf(msg),
+				msg.Headers[events.HeaderRetryCount],
+				formatTime(msg.Timestamp),
+				msg.Headers[events.HeaderError],
+			)
+		}
+	}
+	return w.Flush()
+}
+
+func dlqInspect(args []string, out io.Writer) error {
+	var conn connection
+	fs := newFlagSet("dlq inspect")
+	conn.register(fs)
+	queue := fs.String("queue", "", "consumer queue")
+	id := fs.String("id", "", "message ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *queue == "" || *id == "" {
+		return errUsage
+	}
+
+	ch, closeFn, err := openChannel(conn.amqpURL)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	msgs, err := fetch(ch, events.DeadLetterQueue(*queue), requeueLimit)
+	if err != nil {
+		return err
+	}
+	for _, msg := range msgs {
+		if msg.MessageId != *id {
+			continue
+		}
+
+		fmt.Fprintf(out, "Message ID:    %s\n", msg.MessageId)
+		fmt.Fprintf(out, "Content type:  %s\n", msg.ContentType)
+		fmt.Fprintf(out, "Published:     %s\n", formatTime(msg.Timestamp))
+		fmt.Fprintf(out, "Correlation:   %s\n", msg.CorrelationId)
+		fmt.Fprintln(out, "Headers:")
+		for k, v := range msg.Headers {
+			fmt.Fprintf(out, "  %s: %v\n", k, v)
+		}
+
+		event, err := events.Decode(eventKey(*queue), schemaOf(msg), msg.ContentType, msg.Body)
+		if err != nil {
+			fmt.Fprintf(out, "Body (cannot decode: %v):\n%s\n", err, msg.Body)
+			return nil
+		}
+		body, err := protojson.MarshalOptions{Multiline: true, UseProtoNames: true}.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("protojson.Marshal: %w", err)
+		}
+		fmt.Fprintf(out, "Event %s:\n%s\n", events.Schema(event), body)
+		return nil
+	}
+
+	return fmt.Errorf("message %s not found in %s", *id, events.DeadLetterQueue(*queue))
+}
+
+func dlqRequeue(args []string, out io.Writer) error {
+	var conn connection
+	fs := newFlagSet("dlq requeue")
+	conn.register(fs)
+	queue := fs.String("queue", "", "consumer queue")
+	ids := fs.String("id", "", "comma-separated message IDs")
+	all := fs.Bool("all", false, "requeue every dead-lettered message of the queue")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *queue == "" || (*ids == "") == !*all {
+		return errUsage
+	}
+
+	selected := make(map[string]bool)
+	for _, id := range strings.Split(*ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			selected[id] = true
+		}
+	}
+
+	ch, closeFn, err := openChannel(conn.amqpURL)
+	if err != nil {
+		return err
+	}
+	defer closeFn()
+
+	if err := ch.Confirm(false); err != nil {
+		return fmt.Errorf("ch.Confirm: %w", err)
+	}
+	confirms := ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+
+	msgs, err := fetch(ch, events.DeadLetterQueue(*queue), requeueLimit)
+	if err != nil {
+		return err
+	}
+
+	requeued := 0
+	for _, msg := range msgs {
+		if !*all && !selected[msg.MessageId] {
+			continue
+		}
+
+		// Счётчик попыток сбрасывается: сообщение снова проходит все повторы
+		headers := amqp.Table{}
+		for k, v := range msg.Headers {
+			if k != events.HeaderRetryCount && k != events.HeaderError {
+				headers[k] = v
+			}
+		}
+
+		// Через default exchange сообщение попадает только в исходную очередь,
+		// остальные потребители order.events его повторно не получат
+		if err := ch.Publish("", *queue, true, false, amqp.Publishing{
+			Headers:       headers,
+			ContentType:   msg.ContentType,
+			DeliveryMode:  amqp.Persistent,
+			MessageId:     msg.MessageId,
+			CorrelationId: msg.CorrelationId,
+			Timestamp:     msg.Timestamp,
+			Type:          msg.Type,
+			AppId:         msg.AppId,
+			Body:          msg.Body,
+		}); err != nil {
+			return fmt.Errorf("ch.Publish: %w", err)
+		}
+		if confirm := <-confirms; !confirm.Ack {
+			return fmt.Errorf("broker rejected message %s", msg.MessageId)
+		}
+		if err := msg.Ack(false); err != nil {
+			return fmt.Errorf("ack %s: %w", msg.MessageId, err)
+		}
+
+		fmt.Fprintf(out, "requeued %s to %s\n", msg.MessageId, *queue)
+		requeued++
+	}
+
+	fmt.Fprintf(out, "%d message(s) requeued\n", requeued)
+	return nil
+}
+
+// openChannel подключается к RabbitMQ. Сообщения, полученные через Get и не
+// подтверждённые, возвращаются в очередь при закрытии канала.
+func openChannel(url string) (*amqp.Channel, func(), error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, nil, fmt.Errorf("amqp.Dial: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("conn.Channel: %w", err)
+	}
+
+	return ch, func() {
+		ch.Close()
+		conn.Close()
+	}, nil
+}
+
+// fetch забирает до limit сообщений из очереди без подтверждения.
+func fetch(ch *amqp.Channel, queue string, limit int) ([]amqp.Delivery, error) {
+	var msgs []amqp.Delivery
+	for len(msgs) < limit {
+		msg, ok, err := ch.Get(queue, false)
+		if err != nil {
+			return nil, fmt.Errorf("ch.Get %s: %w", queue, err)
+		}
+		if !ok {
+			break
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+func consumerQueues() []string {
+	var queues []string
+	for _, q := range events.NotificationConsumer.Queues {
+		queues = append(queues, q.Name)
+	}
+	return queues
+}
+
+// eventKey возвращает routing key событий, которые читает очередь.
+func eventKey(queue string) string {
+	for _, q := range events.NotificationConsumer.Queues {
+		if q.Name == queue && len(q.Bindings) > 0 {
+			return q.Bindings[0].RoutingKey
+		}
+	}
+	return ""
+}
+
+// schemaOf возвращает схему события из ce-type или свойства type.
+func schemaOf(msg amqp.Delivery) string {
+	if ce, ok, err := events.ParseCloudEvent(msg.Headers); ok && err == nil {
+		return ce.Type
+	}
+	return msg.Type
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,158 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"orderq/pkg/events"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/streadway/amqp"
+)
+
+// replayHeader отмечает переизданные события. ID событий сохраняются, поэтому
+// идемпотентные потребители пропустят то, что уже обработали.
+const replayHeader = "x-replay"
+
+func replay(args []string, out io.Writer) error {
+	var conn connection
+	fs := newFlagSet("replay")
+	conn.register(fs)
+	orderID := fs.String("order-id", "", "replay events of this order only")
+	since := fs.String("since", "", "replay events that occurred at or after this time")
+	until := fs.String("until", "", "replay events that occurred before this time")
+	dryRun := fs.Bool("dry-run", false, "print matching events without publishing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var (
+		conditions []string
+		params     []any
+	)
+	if *orderID != "" {
+		id, err := uuid.Parse(*orderID)
+		if err != nil {
+			return fmt.Errorf("invalid -order-id: %w", err)
+		}
+		params = append(params, id)
+		conditions = append(conditions, fmt.Sprintf("order_id = $%d", len(params)))
+	}
+	for _, bound := range []struct {
+		value string
+		name  string
+		op    string
+	}{{*since, "since", ">="}, {*until, "until", "<"}} {
+		if bound.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, bound.value)
+		if err != nil {
+			return fmt.Errorf("invalid -%s: %w", bound.name, err)
+		}
+		params = append(params, t)
+		conditions = append(conditions, fmt.Sprintf("occurred_at %s $%d", bound.op, len(params)))
+	}
+	if len(conditions) == 0 {
+		return fmt.Errorf("specify -order-id, -since or -until: %w", errUsage)
+	}
+
+	ctx := context.Background()
+	db, err := pgx.Connect(ctx, conn.dbURL)
+	if err != nil {
+		return fmt.Errorf("pgx.Connect: %w", err)
+	}
+	defer db.Close(ctx)
+
+	rows, err := db.Query(ctx, `
+	SELECT
+		event_id,
+		order_id,
+		routing_key,
+		event_type,
+		source,
+		content_type,
+		correlation_id,
+		payload,
+		occurred_at
+	FROM order_events
+	WHERE `+strings.Join(conditions, " AND ")+`
+	ORDER BY occurred_at
+	`, params...)
+	if err != nil {
+		return fmt.Errorf("query order_events: %w", err)
+	}
+	defer rows.Close()
+
+	var ch *amqp.Channel
+	var confirms chan amqp.Confirmation
+	if !*dryRun {
+		var closeFn func()
+		ch, closeFn, err = openChannel(conn.amqpURL)
+		if err != nil {
+			return err
+		}
+		defer closeFn()
+
+		if err := ch.Confirm(false); err != nil {
+			return fmt.Errorf("ch.Confirm: %w", err)
+		}
+		confirms = ch.NotifyPublish(make(chan amqp.Confirmation, 1))
+	}
+
+	replayed := 0
+	for rows.Next() {
+		var (
+			ce            events.CloudEvent
+			orderID       uuid.UUID
+			routingKey    string
+			contentType   string
+			correlationID string
+			payload       []byte
+		)
+		if err := rows.Scan(&ce.ID, &orderID, &routingKey, &ce.Type, &ce.Source, &contentType, &correlationID, &payload, &ce.Time); err != nil {
+			return fmt.Errorf("scan order_events: %w", err)
+		}
+		ce.Subject = orderID.String()
+
+		fmt.Fprintf(out, "%s  %s  %s  %s\n", formatTime(ce.Time), ce.ID, routingKey, ce.Subject)
+		if *dryRun {
+			replayed++
+			continue
+		}
+
+		headers := ce.Headers()
+		headers[replayHeader] = true
+		if err := ch.Publish(events.OrderExchange, routingKey, true, false, amqp.Publishing{
+			Headers:       headers,
+			ContentType:   contentType,
+			DeliveryMode:  amqp.Persistent,
+			MessageId:     ce.ID,
+			CorrelationId: correlationID,
+			Timestamp:     ce.Time,
+			Type:          ce.Type,
+			AppId:         "orderqctl",
+			Body:          payload,
+		}); err != nil {
+			return fmt.Errorf("ch.Publish: %w", err)
+		}
+		if confirm := <-confirms; !confirm.Ack {
+			return fmt.Errorf("broker rejected event %s", ce.ID)
+		}
+		replayed++
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read order_events: %w", err)
+	}
+
+	if *dryRun {
+		fmt.Fprintf(out, "%d event(s) would be replayed\n", replayed)
+		return nil
+	}
+	fmt.Fprintf(out, "%d event(s) replayed\n", replayed)
+	return nil
+}
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/streadway/amqp"
+)
+
+// Заголовки CloudEvents 1.0 в binary-режиме: атрибуты события лежат в
+// заголовках AMQP-сообщения, datacontenttype — в свойстве content-type,
+// а тело сообщения — это data без обёртки.
+const (
+	HeaderSpecVersion = "ce-specversion"
+	HeaderID          = "ce-id"
+	HeaderSource      = "ce-source"
+	HeaderType        = "ce-type"
+	HeaderTime        = "ce-time"
+	HeaderSubject     = "ce-subject"
+
+	CloudEventsSpecVersion = "1.0"
+)
+
+var ErrInvalidCloudEvent = errors.New("invalid cloud event")
+
+// CloudEvent — атрибуты события. Type — имя схемы с версией
+// (например orderq.events.v1.OrderCreated), Subject — ID заказа.
+type CloudEvent struct {
+	ID      string
+	Source  string
+	Type    string
+	Time    time.Time
+	Subject string
+}
+
+// Headers возвращает атрибуты события в виде заголовков AMQP.
+func (ce CloudEvent) Headers() amqp.Table {
+	headers := amqp.Table{
+		HeaderSpecVersion: CloudEventsSpecVersion,
+		HeaderID:          ce.ID,
+		HeaderSource:      ce.Source,
+		HeaderType:        ce.Type,
+		HeaderTime:        ce.Time.UTC().Format(time.RFC3339Nano),
+	}
+	if ce.Subject != "" {
+		headers[HeaderSubject] = ce.Subject
+	}
+	return headers
+}
+
+// ParseCloudEvent читает атрибуты события из заголовков. ok равен false, если
+// сообщение опубликовано без CloudEvents — до перехода на этот формат.
+func ParseCloudEvent(headers amqp.Table) (ce CloudEvent, ok bool, err error) {
+	specVersion, present := headers[HeaderSpecVersion]
+	if !present {
+		return CloudEvent{}, false, nil
+	}
+	if specVersion != CloudEventsSpecVersion {
+		return CloudEvent{}, true, fmt.Errorf("%w: unsupported specversion %v", ErrInvalidCloudEvent, specVersion)
+	}
+
+	ce.ID, _ = headers[HeaderID].(string)
+	ce.Source, _ = headers[HeaderSource].(string)
+	ce.Type, _ = headers[HeaderType].(string)
+	ce.Subject, _ = headers[HeaderSubject].(string)
+	if ce.ID == "" || ce.Source == "" || ce.Type == "" {
+		return CloudEvent{}, true, fmt.Errorf("%w: id, source and type are required", ErrInvalidCloudEvent)
+	}
+
+	if raw, _ := headers[HeaderTime].(string); raw != "" {
+		if ce.Time, err = time.Parse(time.RFC3339Nano, raw); err != nil {
+			return CloudEvent{}, true, fmt.Errorf("%w: time: %v", ErrInvalidCloudEvent, err)
+		}
+	}
+
+	return ce, true, nil
+}
@@ -0,0 +1,32 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	eventsv1 "orderq/pkg/events/proto/v1"
+
+	"github.com/google/uuid"
+)
+
+func TestCloudEventHeadersRoundTrip(t *testing.T) {
+	ce := CloudEvent{
+		ID:      uuid.NewString(),
+		Source:  "/orderq/order_service",
+		Type:    Schema(&eventsv1.OrderCreated{}),
+		Time:    time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+		Subject: uuid.NewString(),
+	}
+
+	parsed, ok, err := ParseCloudEvent(ce.Headers())
+	if err != nil || !ok {
+		t.Fatalf("ParseCloudEvent: ok=%v err=%v", ok, err)
+	}
+	if parsed != ce {
+		t.Fatalf("parsed %+v, want %+v", parsed, ce)
+	}
+
+	if _, ok, _ := ParseCloudEvent(nil); ok {
+		t.Fatal("message without ce-specversion must not be treated as a cloud event")
+	}
+}
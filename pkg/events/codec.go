@@ -0,0 +1,192 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	eventsv1 "orderq/pkg/events/proto/v1"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Форматы тела события. Издатель выбирает один из них, потребитель понимает оба.
+const (
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeJSON     = "application/json"
+)
+
+var (
+	ErrUnknownSchema          = errors.New("unknown event schema")
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+)
+
+// OrderEvent — любое событие схемы orderq.events: все они несут состояние заказа.
+type OrderEvent interface {
+	proto.Message
+	GetOrder() *eventsv1.Order
+}
+
+// schemas — сообщение схемы для каждого routing key. По нему разбираются
+// события, опубликованные до перехода на protobuf, — у них нет имени схемы.
+var schemas = map[string]func() OrderEvent{
+	OrderCreated:           func() OrderEvent { return &eventsv1.OrderCreated{} },
+	OrderAssigned:          func() OrderEvent { return &eventsv1.OrderAssigned{} },
+	OrderAccepted:          func() OrderEvent { return &eventsv1.OrderAccepted{} },
+	OrderCancelled:         func() OrderEvent { return &eventsv1.OrderCancelled{} },
+	OrderCompleted:         func() OrderEvent { return &eventsv1.OrderCompleted{} },
+	OrderUpdated:           func() OrderEvent { return &eventsv1.OrderUpdated{} },
+	OrderExpired:           func() OrderEvent { return &eventsv1.OrderExpired{} },
+	OrderCandidateReleased: func() OrderEvent { return &eventsv1.OrderCandidateReleased{} },
+	OrderOffered:           func() OrderEvent { return &eventsv1.OrderOffered{} },
+	OrderOfferExpired:      func() OrderEvent { return &eventsv1.OrderOfferExpired{} },
+	OrderAgentLocation:     func() OrderEvent { return &eventsv1.OrderAgentLocation{} },
+}
+
+var jsonOptions = protojson.MarshalOptions{UseProtoNames: true}
+
+// Schema возвращает полное имя схемы события с версией, например
+// "orderq.events.v1.OrderCreated". Издатель кладёт его в свойство type сообщения.
+func Schema(event OrderEvent) string {
+	return string(proto.MessageName(event))
+}
+
+// Marshal сериализует событие в выбранном формате.
+func Marshal(event OrderEvent, contentType string) ([]byte, error) {
+	switch mediaType(contentType) {
+	case ContentTypeProtobuf:
+		return proto.Marshal(event)
+	case ContentTypeJSON:
+		return jsonOptions.Marshal(event)
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+}
+
+// MarshalJSON сериализует событие в JSON схемы независимо от формата в брокере.
+func MarshalJSON(event OrderEvent) ([]byte, error) {
+	return jsonOptions.Marshal(event)
+}
+
+// Decode разбирает тело события по имени схемы и content type. Сообщения без
+// имени схемы (или с routing key вместо него) — JSON старого формата, тип
+// события берётся из routing key.
+func Decode(routingKey, schema, contentType string, body []byte) (OrderEvent, error) {
+	if schema == "" || schema == routingKey {
+		return decodeLegacy(routingKey, contentType, body)
+	}
+
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(schema))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSchema, schema)
+	}
+	event, ok := mt.New().Interface().(OrderEvent)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is not an order event", ErrUnknownSchema, schema)
+	}
+
+	switch mediaType(contentType) {
+	case ContentTypeProtobuf:
+		err = proto.Unmarshal(body, event)
+	case ContentTypeJSON:
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, event)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal %s: %w", schema, err)
+	}
+
+	return event, nil
+}
+
+func decodeLegacy(routingKey, contentType string, body []byte) (OrderEvent, error) {
+	if mt := mediaType(contentType); mt != "" && mt != ContentTypeJSON {
+		return nil, fmt.Errorf("%w: %q without schema", ErrUnsupportedContentType, contentType)
+	}
+
+	newEvent, ok := schemas[routingKey]
+	if !ok {
+		return nil, fmt.Errorf("%w: routing key %q", ErrUnknownSchema, routingKey)
+	}
+
+	order := Order{}
+	if err := json.Unmarshal(body, &order); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	event := newEvent()
+	// Все сообщения схемы хранят заказ в поле order
+	field := event.ProtoReflect().Descriptor().Fields().ByName("order")
+	event.ProtoReflect().Set(field, protoreflect.ValueOfMessage(OrderToProto(order).ProtoReflect()))
+	return event, nil
+}
+
+func mediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	if mt == "application/protobuf" {
+		return ContentTypeProtobuf
+	}
+	return mt
+}
+
+// OrderToProto переводит заказ в сообщение схемы.
+func OrderToProto(order Order) *eventsv1.Order {
+	return &eventsv1.Order{
+		OrderId:       uuidString(order.OrderID),
+		UserId:        uuidString(order.UserID),
+		AgentId:       uuidString(order.AgentID),
+		OrderAddress:  order.OrderAddress,
+		OrderLocation: order.OrderLocation,
+		OrderDate:     timestamppb.New(order.OrderDate),
+		OrderTimeGap:  durationpb.New(order.OrderTimeGap),
+		OrderStatus:   order.OrderStatus,
+	}
+}
+
+// OrderFromProto переводит сообщение схемы в заказ.
+func OrderFromProto(pb *eventsv1.Order) (Order, error) {
+	order := Order{
+		OrderAddress:  pb.GetOrderAddress(),
+		OrderLocation: pb.GetOrderLocation(),
+		OrderDate:     pb.GetOrderDate().AsTime(),
+		OrderTimeGap:  pb.GetOrderTimeGap().AsDuration(),
+		OrderStatus:   pb.GetOrderStatus(),
+	}
+
+	var err error
+	if order.OrderID, err = parseUUID(pb.GetOrderId()); err != nil {
+		return Order{}, fmt.Errorf("order_id: %w", err)
+	}
+	if order.UserID, err = parseUUID(pb.GetUserId()); err != nil {
+		return Order{}, fmt.Errorf("user_id: %w", err)
+	}
+	if order.AgentID, err = parseUUID(pb.GetAgentId()); err != nil {
+		return Order{}, fmt.Errorf("agent_id: %w", err)
+	}
+	return order, nil
+}
+
+func uuidString(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
+func parseUUID(s string) (uuid.UUID, error) {
+	if s == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(s)
+}
@@ -0,0 +1,68 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	eventsv1 "orderq/pkg/events/proto/v1"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDecodeRoundTrip(t *testing.T) {
+	order := Order{
+		OrderID:      uuid.New(),
+		UserID:       uuid.New(),
+		OrderAddress: "Lenina 1",
+		OrderDate:    time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+		OrderTimeGap: 90 * time.Minute,
+		OrderStatus:  "cancelled",
+	}
+	event := &eventsv1.OrderCancelled{Order: OrderToProto(order), Reason: "changed plans"}
+
+	for _, contentType := range []string{ContentTypeProtobuf, ContentTypeJSON} {
+		body, err := Marshal(event, contentType)
+		if err != nil {
+			t.Fatalf("Marshal %s: %v", contentType, err)
+		}
+
+		decoded, err := Decode(OrderCancelled, Schema(event), contentType, body)
+		if err != nil {
+			t.Fatalf("Decode %s: %v", contentType, err)
+		}
+		if !proto.Equal(decoded, event) {
+			t.Fatalf("%s: decoded %v, want %v", contentType, decoded, event)
+		}
+
+		got, err := OrderFromProto(decoded.GetOrder())
+		if err != nil {
+			t.Fatalf("OrderFromProto: %v", err)
+		}
+		if got != order {
+			t.Fatalf("%s: order %+v, want %+v", contentType, got, order)
+		}
+	}
+}
+
+func TestDecodeLegacyJSON(t *testing.T) {
+	userID := uuid.New()
+	body := []byte(`{"order_id":"` + uuid.NewString() + `","user_id":"` + userID.String() +
+		`","agent_id":"00000000-0000-0000-0000-000000000000","order_time_gap":3600000000000,"order_status":"pending"}`)
+
+	event, err := Decode(OrderCreated, "", "", body)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if _, ok := event.(*eventsv1.OrderCreated); !ok {
+		t.Fatalf("expected OrderCreated, got %T", event)
+	}
+
+	order, err := OrderFromProto(event.GetOrder())
+	if err != nil {
+		t.Fatalf("OrderFromProto: %v", err)
+	}
+	if order.UserID != userID || order.OrderTimeGap != time.Hour || order.AgentID != uuid.Nil {
+		t.Fatalf("unexpected order %+v", order)
+	}
+}
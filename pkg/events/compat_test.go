@@ -0,0 +1,97 @@
+package events
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	eventsv1 "orderq/pkg/events/proto/v1"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata snapshot after a compatible schema change")
+
+const snapshotPath = "testdata/order_events_v1.json"
+
+type fieldSnapshot struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	Cardinality string `json:"cardinality"`
+}
+
+type messageSnapshot struct {
+	Fields map[protoreflect.FieldNumber]fieldSnapshot `json:"fields"`
+}
+
+func snapshotSchema(fd protoreflect.FileDescriptor) map[string]messageSnapshot {
+	messages := make(map[string]messageSnapshot)
+	for i := 0; i < fd.Messages().Len(); i++ {
+		md := fd.Messages().Get(i)
+		fields := make(map[protoreflect.FieldNumber]fieldSnapshot)
+		for j := 0; j < md.Fields().Len(); j++ {
+			f := md.Fields().Get(j)
+			kind := f.Kind().String()
+			if f.Message() != nil {
+				kind = string(f.Message().FullName())
+			}
+			fields[f.Number()] = fieldSnapshot{
+				Name:        string(f.Name()),
+				Kind:        kind,
+				Cardinality: f.Cardinality().String(),
+			}
+		}
+		messages[string(md.FullName())] = messageSnapshot{Fields: fields}
+	}
+	return messages
+}
+
+// TestSchemaCompatibility падает, если схема v1 изменилась несовместимо со снимком:
+// пропало сообщение, у поля сменились имя, тип или cardinality, или поле удалено
+// без reserved. Новые поля и сообщения допустимы; снимок обновляется флагом -update.
+func TestSchemaCompatibility(t *testing.T) {
+	fd := eventsv1.File_order_events_proto
+	current := snapshotSchema(fd)
+
+	raw, err := os.ReadFile(snapshotPath)
+	if err != nil {
+		t.Fatalf("read snapshot: %v", err)
+	}
+	var previous map[string]messageSnapshot
+	if err := json.Unmarshal(raw, &previous); err != nil {
+		t.Fatalf("parse snapshot: %v", err)
+	}
+
+	for name, prev := range previous {
+		md := fd.Messages().ByName(protoreflect.FullName(name).Name())
+		cur, ok := current[name]
+		if !ok || md == nil {
+			t.Errorf("message %s was removed or renamed", name)
+			continue
+		}
+
+		for number, prevField := range prev.Fields {
+			curField, ok := cur.Fields[number]
+			if !ok {
+				if !md.ReservedRanges().Has(number) || !md.ReservedNames().Has(protoreflect.Name(prevField.Name)) {
+					t.Errorf("%s: field %d %q was removed without reserving its number and name", name, number, prevField.Name)
+				}
+				continue
+			}
+			if curField != prevField {
+				t.Errorf("%s: field %d changed from %+v to %+v", name, number, prevField, curField)
+			}
+		}
+	}
+
+	if *update && !t.Failed() {
+		raw, err := json.MarshalIndent(current, "", "  ")
+		if err != nil {
+			t.Fatalf("marshal snapshot: %v", err)
+		}
+		if err := os.WriteFile(snapshotPath, append(raw, '\n'), 0o644); err != nil {
+			t.Fatalf("write snapshot: %v", err)
+		}
+	}
+}
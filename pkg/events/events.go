@@ -0,0 +1,46 @@
+// Package events описывает контракт событий заказов между сервисами:
+// exchange, routing keys, формат сообщений и топологию RabbitMQ.
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderExchange — topic exchange, в который order service публикует события заказов.
+const OrderExchange = "order.events"
+
+// Routing keys событий заказа.
+const (
+	OrderCreated   = "order.created"
+	OrderAssigned  = "order.assigned"
+	OrderAccepted  = "order.accepted"
+	OrderCancelled = "order.cancelled"
+	OrderCompleted = "order.completed"
+	OrderUpdated   = "order.updated"
+	OrderExpired   = "order.expired"
+	// OrderCandidateReleased — исполнитель стоял в очереди на заказ, но выбрали
+	// другого. Уведомление получает исполнитель, а не владелец заказа.
+	OrderCandidateReleased = "order.candidate_released"
+	// OrderOffered и OrderOfferExpired — автоматическое предложение заказа
+	// исполнителю; уведомление тоже получает исполнитель.
+	OrderOffered      = "order.offered"
+	OrderOfferExpired = "order.offer_expired"
+	// OrderAgentLocation — где сейчас исполнитель заказа в работе. Уведомление
+	// получает владелец заказа, только через WebSocket и SSE.
+	OrderAgentLocation = "order.agent_location"
+)
+
+// Order — заказ в событии. В брокер он уходит как eventsv1.Order; в JSON
+// этой структуры публиковались события до перехода на схему orderq.events.v1.
+type Order struct {
+	OrderID       uuid.UUID     `json:"order_id"`
+	UserID        uuid.UUID     `json:"user_id"`
+	AgentID       uuid.UUID     `json:"agent_id"`
+	OrderAddress  string        `json:"order_address"`
+	OrderLocation string        `json:"order_location"`
+	OrderDate     time.Time     `json:"order_date"`
+	OrderTimeGap  time.Duration `json:"order_time_gap"`
+	OrderStatus   string        `json:"order_status"`
+}
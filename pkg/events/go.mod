@@ -0,0 +1,9 @@
+module orderq/pkg/events
+
+go 1.24.2
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/streadway/amqp v1.1.0
+	google.golang.org/protobuf v1.36.6
+)
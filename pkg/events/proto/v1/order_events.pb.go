@@ -0,0 +1,941 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.1
+// source: order_events.proto
+
+// Контракт событий заказов в exchange order.events.
+//
+// Версия схемы входит в имя пакета. Внутри v1 допустимы только обратно
+// совместимые изменения: новые поля и новые сообщения. Номера, имена и типы
+// существующих полей не меняются, удалённые поля помечаются reserved.
+// Несовместимое изменение — это новый пакет orderq.events.v2.
+// TestSchemaCompatibility сверяет схему со снимком testdata/order_events_v1.json.
+
+package eventsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Order — состояние заказа на момент события.
+type Order struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AgentId       string                 `protobuf:"bytes,3,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	OrderAddress  string                 `protobuf:"bytes,4,opt,name=order_address,json=orderAddress,proto3" json:"order_address,omitempty"`
+	OrderLocation string                 `protobuf:"bytes,5,opt,name=order_location,json=orderLocation,proto3" json:"order_location,omitempty"`
+	OrderDate     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=order_date,json=orderDate,proto3" json:"order_date,omitempty"`
+	OrderTimeGap  *durationpb.Duration   `protobuf:"bytes,7,opt,name=order_time_gap,json=orderTimeGap,proto3" json:"order_time_gap,omitempty"`
+	OrderStatus   string                 `protobuf:"bytes,8,opt,name=order_status,json=orderStatus,proto3" json:"order_status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_order_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_order_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_order_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Order) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Order) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Order) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *Order) GetOrderAddress() string {
+	if x != nil {
+		return x.OrderAddress
+	}
+	return ""
+}
+
+func (x *Order) GetOrderLocation() string {
+	if x != nil {
+		return x.OrderLocation
+	}
+	return ""
+}
+
+func (x *Order) GetOrderDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OrderDate
+	}
+	return nil
+}
+
+func (x *Order) GetOrderTimeGap() *durationpb.Duration {
+	if x != nil {
+		return x.OrderTimeGap
+	}
+	return nil
+}
+
+func (x *Order) GetOrderStatus() string {
+	if x != nil {
+		return x.OrderStatus
+	}
+	return ""
+}
+
+// order.created
+type OrderCreated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderCreated) Reset() {
+	*x = OrderCreated{}
+	mi := &file_order_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderCreated) ProtoMessage() {}
+
+func (x *OrderCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_order_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderCreated.ProtoReflect.Descriptor instead.
+func (*OrderCreated) Descriptor() ([]byte, []int) {
+	return file_order_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrderCreated) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+// order.assigned
+type OrderAssigned struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderAssigned) Reset() {
+	*x = OrderAssigned{}
+	mi := &file_order_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderAssigned) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderAssigned) ProtoMessage() {}
+
+func (x *OrderAssigned) ProtoReflect() protoreflect.Message {
+	mi := &file_order_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderAssigned.ProtoReflect.Descriptor instead.
+func (*OrderAssigned) Descriptor() ([]byte, []int) {
+	return file_order_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OrderAssigned) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderAssigned) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// order.accepted
+type OrderAccepted struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderAccepted) Reset() {
+	*x = OrderAccepted{}
+	mi := &file_order_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderAccepted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderAccepted) ProtoMessage() {}
+
+func (x *OrderAccepted) ProtoReflect() protoreflect.Message {
+	mi := &file_order_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderAccepted.ProtoReflect.Descriptor instead.
+func (*OrderAccepted) Descriptor() ([]byte, []int) {
+	return file_order_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OrderAccepted) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderAccepted) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// order.cancelled
+type OrderCancelled struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Order *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	// Код причины: changed_plans, found_alternative, agent_late, agent_unresponsive,
+	// client_unresponsive, wrong_details, expired, other
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Пояснение своими словами; обязательно для other
+	Comment string `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	// Кто отменил: client, agent, manager, system
+	CancelledBy string `protobuf:"bytes,4,opt,name=cancelled_by,json=cancelledBy,proto3" json:"cancelled_by,omitempty"`
+	// Последствия по политике отмены: none, fee, strike
+	Penalty       string `protobuf:"bytes,5,opt,name=penalty,proto3" json:"penalty,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderCancelled) Reset() {
+	*x = OrderCancelled{}
+	mi := &file_order_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderCancelled) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderCancelled) ProtoMessage() {}
+
+func (x *OrderCancelled) ProtoReflect() protoreflect.Message {
+	mi := &file_order_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderCancelled.ProtoReflect.Descriptor instead.
+func (*OrderCancelled) Descriptor() ([]byte, []int) {
+	return file_order_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OrderCancelled) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderCancelled) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *OrderCancelled) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *OrderCancelled) GetCancelledBy() string {
+	if x != nil {
+		return x.CancelledBy
+	}
+	return ""
+}
+
+func (x *OrderCancelled) GetPenalty() string {
+	if x != nil {
+		return x.Penalty
+	}
+	return ""
+}
+
+// order.completed
+type OrderCompleted struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderCompleted) Reset() {
+	*x = OrderCompleted{}
+	mi := &file_order_events_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderCompleted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderCompleted) ProtoMessage() {}
+
+func (x *OrderCompleted) ProtoReflect() protoreflect.Message {
+	mi := &file_order_events_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderCompleted.ProtoReflect.Descriptor instead.
+func (*OrderCompleted) Descriptor() ([]byte, []int) {
+	return file_order_events_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OrderCompleted) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+// order.updated
+type OrderUpdated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	ChangedFields []string               `protobuf:"bytes,2,rep,name=changed_fields,json=changedFields,proto3" json:"changed_fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderUpdated) Reset() {
+	*x = OrderUpdated{}
+	mi := &file_order_events_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderUpdated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderUpdated) ProtoMessage() {}
+
+func (x *OrderUpdated) ProtoReflect() protoreflect.Message {
+	mi := &file_order_events_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderUpdated.ProtoReflect.Descriptor instead.
+func (*OrderUpdated) Descriptor() ([]byte, []int) {
+	return file_order_events_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *OrderUpdated) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderUpdated) GetChangedFields() []string {
+	if x != nil {
+		return x.ChangedFields
+	}
+	return nil
+}
+
+// order.expired: срок заказа (order_date + order_time_gap) прошёл, а исполнитель
+// так и не приступил к нему
+type OrderExpired struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Deadline      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderExpired) Reset() {
+	*x = OrderExpired{}
+	mi := &file_order_events_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderExpired) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderExpired) ProtoMessage() {}
+
+func (x *OrderExpired) ProtoReflect() protoreflect.Message {
+	mi := &file_order_events_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderExpired.ProtoReflect.Descriptor instead.
+func (*OrderExpired) Descriptor() ([]byte, []int) {
+	return file_order_events_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *OrderExpired) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderExpired) GetDeadline() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Deadline
+	}
+	return nil
+}
+
+// order.candidate_released: клиент или система выбрали другого исполнителя из
+// очереди на заказ, и agent_id больше не ждёт этот заказ
+type OrderCandidateReleased struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderCandidateReleased) Reset() {
+	*x = OrderCandidateReleased{}
+	mi := &file_order_events_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderCandidateReleased) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderCandidateReleased) ProtoMessage() {}
+
+func (x *OrderCandidateReleased) ProtoReflect() protoreflect.Message {
+	mi := &file_order_events_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderCandidateReleased.ProtoReflect.Descriptor instead.
+func (*OrderCandidateReleased) Descriptor() ([]byte, []int) {
+	return file_order_events_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *OrderCandidateReleased) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderCandidateReleased) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// order.offered: система автоматически предложила заказ исполнителю agent_id;
+// он должен принять или отклонить предложение до expires_at
+type OrderOffered struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderOffered) Reset() {
+	*x = OrderOffered{}
+	mi := &file_order_events_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderOffered) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderOffered) ProtoMessage() {}
+
+func (x *OrderOffered) ProtoReflect() protoreflect.Message {
+	mi := &file_order_events_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderOffered.ProtoReflect.Descriptor instead.
+func (*OrderOffered) Descriptor() ([]byte, []int) {
+	return file_order_events_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *OrderOffered) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderOffered) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *OrderOffered) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+// order.offer_expired: предложение заказа исполнителю agent_id больше не
+// действует. reason: expired — исполнитель не ответил вовремя, withdrawn —
+// заказ отменили или назначили иначе
+type OrderOfferExpired struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderOfferExpired) Reset() {
+	*x = OrderOfferExpired{}
+	mi := &file_order_events_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderOfferExpired) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderOfferExpired) ProtoMessage() {}
+
+func (x *OrderOfferExpired) ProtoReflect() protoreflect.Message {
+	mi := &file_order_events_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderOfferExpired.ProtoReflect.Descriptor instead.
+func (*OrderOfferExpired) Descriptor() ([]byte, []int) {
+	return file_order_events_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *OrderOfferExpired) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderOfferExpired) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *OrderOfferExpired) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// order.agent_location: исполнитель agent_id выполняет заказ и сейчас в точке
+// latitude, longitude. distance_km и eta — до места заказа, если оно известно;
+// иначе distance_km равно 0, а eta не задано. accuracy_m равно 0, если
+// точность неизвестна
+type OrderAgentLocation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Latitude      float64                `protobuf:"fixed64,3,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,4,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	AccuracyM     float64                `protobuf:"fixed64,5,opt,name=accuracy_m,json=accuracyM,proto3" json:"accuracy_m,omitempty"`
+	RecordedAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=recorded_at,json=recordedAt,proto3" json:"recorded_at,omitempty"`
+	DistanceKm    float64                `protobuf:"fixed64,7,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	Eta           *durationpb.Duration   `protobuf:"bytes,8,opt,name=eta,proto3" json:"eta,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderAgentLocation) Reset() {
+	*x = OrderAgentLocation{}
+	mi := &file_order_events_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderAgentLocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderAgentLocation) ProtoMessage() {}
+
+func (x *OrderAgentLocation) ProtoReflect() protoreflect.Message {
+	mi := &file_order_events_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderAgentLocation.ProtoReflect.Descriptor instead.
+func (*OrderAgentLocation) Descriptor() ([]byte, []int) {
+	return file_order_events_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *OrderAgentLocation) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *OrderAgentLocation) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *OrderAgentLocation) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *OrderAgentLocation) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+func (x *OrderAgentLocation) GetAccuracyM() float64 {
+	if x != nil {
+		return x.AccuracyM
+	}
+	return 0
+}
+
+func (x *OrderAgentLocation) GetRecordedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RecordedAt
+	}
+	return nil
+}
+
+func (x *OrderAgentLocation) GetDistanceKm() float64 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+func (x *OrderAgentLocation) GetEta() *durationpb.Duration {
+	if x != nil {
+		return x.Eta
+	}
+	return nil
+}
+
+var File_order_events_proto protoreflect.FileDescriptor
+
+const file_order_events_proto_rawDesc = "" +
+	"\n" +
+	"\x12order_events.proto\x12\x10orderq.events.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1egoogle/protobuf/duration.proto\"\xc1\x02\n" +
+	"\x05Order\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x19\n" +
+	"\bagent_id\x18\x03 \x01(\tR\aagentId\x12#\n" +
+	"\rorder_address\x18\x04 \x01(\tR\forderAddress\x12%\n" +
+	"\x0eorder_location\x18\x05 \x01(\tR\rorderLocation\x129\n" +
+	"\n" +
+	"order_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\torderDate\x12?\n" +
+	"\x0eorder_time_gap\x18\a \x01(\v2\x19.google.protobuf.DurationR\forderTimeGap\x12!\n" +
+	"\forder_status\x18\b \x01(\tR\vorderStatus\"=\n" +
+	"\fOrderCreated\x12-\n" +
+	"\x05order\x18\x01 \x01(\v2\x17.orderq.events.v1.OrderR\x05order\"Y\n" +
+	"\rOrderAssigned\x12-\n" +
+	"\x05order\x18\x01 \x01(\v2\x17.orderq.events.v1.OrderR\x05order\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\"Y\n" +
+	"\rOrderAccepted\x12-\n" +
+	"\x05order\x18\x01 \x01(\v2\x17.orderq.events.v1.OrderR\x05order\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\"\xae\x01\n" +
+	"\x0eOrderCancelled\x12-\n" +
+	"\x05order\x18\x01 \x01(\v2\x17.orderq.events.v1.OrderR\x05order\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x18\n" +
+	"\acomment\x18\x03 \x01(\tR\acomment\x12!\n" +
+	"\fcancelled_by\x18\x04 \x01(\tR\vcancelledBy\x12\x18\n" +
+	"\apenalty\x18\x05 \x01(\tR\apenalty\"?\n" +
+	"\x0eOrderCompleted\x12-\n" +
+	"\x05order\x18\x01 \x01(\v2\x17.orderq.events.v1.OrderR\x05order\"d\n" +
+	"\fOrderUpdated\x12-\n" +
+	"\x05order\x18\x01 \x01(\v2\x17.orderq.events.v1.OrderR\x05order\x12%\n" +
+	"\x0echanged_fields\x18\x02 \x03(\tR\rchangedFields\"u\n" +
+	"\fOrderExpired\x12-\n" +
+	"\x05order\x18\x01 \x01(\v2\x17.orderq.events.v1.OrderR\x05order\x126\n" +
+	"\bdeadline\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bdeadline\"b\n" +
+	"\x16OrderCandidateReleased\x12-\n" +
+	"\x05order\x18\x01 \x01(\v2\x17.orderq.events.v1.OrderR\x05order\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\"\x93\x01\n" +
+	"\fOrderOffered\x12-\n" +
+	"\x05order\x18\x01 \x01(\v2\x17.orderq.events.v1.OrderR\x05order\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"u\n" +
+	"\x11OrderOfferExpired\x12-\n" +
+	"\x05order\x18\x01 \x01(\v2\x17.orderq.events.v1.OrderR\x05order\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\xc2\x02\n" +
+	"\x12OrderAgentLocation\x12-\n" +
+	"\x05order\x18\x01 \x01(\v2\x17.orderq.events.v1.OrderR\x05order\x12\x19\n" +
+	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x1a\n" +
+	"\blatitude\x18\x03 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x04 \x01(\x01R\tlongitude\x12\x1d\n" +
+	"\n" +
+	"accuracy_m\x18\x05 \x01(\x01R\taccuracyM\x12;\n" +
+	"\vrecorded_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"recordedAt\x12\x1f\n" +
+	"\vdistance_km\x18\a \x01(\x01R\n" +
+	"distanceKm\x12+\n" +
+	"\x03eta\x18\b \x01(\v2\x19.google.protobuf.DurationR\x03etaB%Z#orderq/pkg/events/proto/v1;eventsv1b\x06proto3"
+
+var (
+	file_order_events_proto_rawDescOnce sync.Once
+	file_order_events_proto_rawDescData []byte
+)
+
+func file_order_events_proto_rawDescGZIP() []byte {
+	file_order_events_proto_rawDescOnce.Do(func() {
+		file_order_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_order_events_proto_rawDesc), len(file_order_events_proto_rawDesc)))
+	})
+	return file_order_events_proto_rawDescData
+}
+
+var file_order_events_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_order_events_proto_goTypes = []any{
+	(*Order)(nil),                  // 0: orderq.events.v1.Order
+	(*OrderCreated)(nil),           // 1: orderq.events.v1.OrderCreated
+	(*OrderAssigned)(nil),          // 2: orderq.events.v1.OrderAssigned
+	(*OrderAccepted)(nil),          // 3: orderq.events.v1.OrderAccepted
+	(*OrderCancelled)(nil),         // 4: orderq.events.v1.OrderCancelled
+	(*OrderCompleted)(nil),         // 5: orderq.events.v1.OrderCompleted
+	(*OrderUpdated)(nil),           // 6: orderq.events.v1.OrderUpdated
+	(*OrderExpired)(nil),           // 7: orderq.events.v1.OrderExpired
+	(*OrderCandidateReleased)(nil), // 8: orderq.events.v1.OrderCandidateReleased
+	(*OrderOffered)(nil),           // 9: orderq.events.v1.OrderOffered
+	(*OrderOfferExpired)(nil),      // 10: orderq.events.v1.OrderOfferExpired
+	(*OrderAgentLocation)(nil),     // 11: orderq.events.v1.OrderAgentLocation
+	(*timestamppb.Timestamp)(nil),  // 12: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),    // 13: google.protobuf.Duration
+}
+var file_order_events_proto_depIdxs = []int32{
+	12, // 0: orderq.events.v1.Order.order_date:type_name -> google.protobuf.Timestamp
+	13, // 1: orderq.events.v1.Order.order_time_gap:type_name -> google.protobuf.Duration
+	0,  // 2: orderq.events.v1.OrderCreated.order:type_name -> orderq.events.v1.Order
+	0,  // 3: orderq.events.v1.OrderAssigned.order:type_name -> orderq.events.v1.Order
+	0,  // 4: orderq.events.v1.OrderAccepted.order:type_name -> orderq.events.v1.Order
+	0,  // 5: orderq.events.v1.OrderCancelled.order:type_name -> orderq.events.v1.Order
+	0,  // 6: orderq.events.v1.OrderCompleted.order:type_name -> orderq.events.v1.Order
+	0,  // 7: orderq.events.v1.OrderUpdated.order:type_name -> orderq.events.v1.Order
+	0,  // 8: orderq.events.v1.OrderExpired.order:type_name -> orderq.events.v1.Order
+	12, // 9: orderq.events.v1.OrderExpired.deadline:type_name -> google.protobuf.Timestamp
+	0,  // 10: orderq.events.v1.OrderCandidateReleased.order:type_name -> orderq.events.v1.Order
+	0,  // 11: orderq.events.v1.OrderOffered.order:type_name -> orderq.events.v1.Order
+	12, // 12: orderq.events.v1.OrderOffered.expires_at:type_name -> google.protobuf.Timestamp
+	0,  // 13: orderq.events.v1.OrderOfferExpired.order:type_name -> orderq.events.v1.Order
+	0,  // 14: orderq.events.v1.OrderAgentLocation.order:type_name -> orderq.events.v1.Order
+	12, // 15: orderq.events.v1.OrderAgentLocation.recorded_at:type_name -> google.protobuf.Timestamp
+	13, // 16: orderq.events.v1.OrderAgentLocation.eta:type_name -> google.protobuf.Duration
+	17, // [17:17] is the sub-list for method output_type
+	17, // [17:17] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_order_events_proto_init() }
+func file_order_events_proto_init() {
+	if File_order_events_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_order_events_proto_rawDesc), len(file_order_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_order_events_proto_goTypes,
+		DependencyIndexes: file_order_events_proto_depIdxs,
+		MessageInfos:      file_order_events_proto_msgTypes,
+	}.Build()
+	File_order_events_proto = out.File
+	file_order_events_proto_goTypes = nil
+	file_order_events_proto_depIdxs = nil
+}
@@ -0,0 +1,156 @@
+package events
+
+import (
+	"fmt"
+
+	"github.com/streadway/amqp"
+)
+
+// Exchange — объявление exchange.
+type Exchange struct {
+	Name    string
+	Kind    string
+	Durable bool
+}
+
+// Queue — объявление очереди и её привязок к exchange.
+type Queue struct {
+	Name     string
+	Durable  bool
+	Bindings []Binding
+}
+
+type Binding struct {
+	Exchange   string
+	RoutingKey string
+}
+
+// Topology — то, что сервис объявляет в RabbitMQ при каждом подключении.
+// Издатель объявляет только exchange, очереди и привязки объявляет их потребитель.
+type Topology struct {
+	Exchanges []Exchange
+	Queues    []Queue
+}
+
+// Declarer — часть *amqp.Channel, нужная для объявления топологии.
+type Declarer interface {
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+}
+
+var orderExchange = Exchange{Name: OrderExchange, Kind: "topic", Durable: true}
+
+// OrderPublishes — события, которые публикует order service.
+var OrderPublishes = []string{
+	OrderCreated,
+	OrderAssigned,
+	OrderCancelled,
+	OrderCompleted,
+	OrderExpired,
+	OrderUpdated,
+	OrderCandidateReleased,
+	OrderOffered,
+	OrderOfferExpired,
+	OrderAgentLocation,
+}
+
+// OrderProducer — топология order service: только exchange событий заказов.
+var OrderProducer = Topology{
+	Exchanges: []Exchange{orderExchange},
+}
+
+// DeadLetterExchange принимает сообщения, которые потребитель не смог обработать.
+// Каждая очередь получает свою очередь DeadLetterQueue(queue), привязанную
+// к нему по имени исходной очереди.
+const DeadLetterExchange = "order.events.dlx"
+
+// Заголовки, которые потребитель добавляет к сообщению при повторе и в dead-letter.
+const (
+	HeaderRetryCount = "x-retry-count"
+	HeaderError      = "x-error"
+)
+
+// DeadLetterQueue возвращает имя dead-letter очереди для queue.
+func DeadLetterQueue(queue string) string {
+	return queue + ".dead"
+}
+
+// Очереди notification service.
+const (
+	NotificationOrderCreated   = "queue_order_created"
+	NotificationOrderCancelled = "queue_order_cancelled"
+	NotificationOrderCompleted = "queue_order_completed"
+	NotificationOrderExpired   = "queue_order_expired"
+	NotificationOrderUpdated   = "queue_order_updated"
+	NotificationOrderAssigned  = "queue_order_assigned"
+	// NotificationCandidateReleased — уведомления исполнителям, а не владельцу заказа
+	NotificationCandidateReleased = "queue_order_candidate_released"
+	NotificationOrderOffered      = "queue_order_offered"
+	NotificationOfferExpired      = "queue_order_offer_expired"
+	NotificationAgentLocation     = "queue_order_agent_location"
+)
+
+// NotificationConsumer — очереди notification service, из которых он рассылает
+// уведомления владельцу заказа или исполнителю.
+var NotificationConsumer = Topology{
+	Exchanges: []Exchange{orderExchange},
+	Queues: []Queue{
+		orderQueue(NotificationOrderCreated, OrderCreated),
+		orderQueue(NotificationOrderCancelled, OrderCancelled),
+		orderQueue(NotificationOrderCompleted, OrderCompleted),
+		orderQueue(NotificationOrderExpired, OrderExpired),
+		orderQueue(NotificationOrderUpdated, OrderUpdated),
+		orderQueue(NotificationOrderAssigned, OrderAssigned),
+		orderQueue(NotificationCandidateReleased, OrderCandidateReleased),
+		orderQueue(NotificationOrderOffered, OrderOffered),
+		orderQueue(NotificationOfferExpired, OrderOfferExpired),
+		orderQueue(NotificationAgentLocation, OrderAgentLocation),
+	},
+}
+
+func orderQueue(name, routingKey string) Queue {
+	return Queue{
+		Name:     name,
+		Durable:  true,
+		Bindings: []Binding{{Exchange: OrderExchange, RoutingKey: routingKey}},
+	}
+}
+
+// Declare объявляет exchange, очереди и привязки топологии.
+func (t Topology) Declare(ch Declarer) error {
+	for _, e := range t.Exchanges {
+		if err := ch.ExchangeDeclare(
+			e.Name,
+			e.Kind,
+			e.Durable,
+			false, // auto-delete
+			false,
+			false,
+			nil,
+		); err != nil {
+			return fmt.Errorf("ch.ExchangeDeclare %s: %w", e.Name, err)
+		}
+	}
+
+	for _, q := range t.Queues {
+		if _, err := ch.QueueDeclare(
+			q.Name,
+			q.Durable,
+			false, // auto-delete
+			false,
+			false,
+			nil,
+		); err != nil {
+			return fmt.Errorf("ch.QueueDeclare %s: %w", q.Name, err)
+		}
+
+		for _, b := range q.Bindings {
+			if err := ch.QueueBind(q.Name, b.RoutingKey, b.Exchange, false, nil); err != nil {
+				return fmt.Errorf("ch.QueueBind %s: %w", q.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/streadway/amqp"
+)
+
+type fakeChannel struct {
+	exchanges map[string]string
+	queues    map[string]bool
+	bindings  map[string][]string
+}
+
+func newFakeChannel() *fakeChannel {
+	return &fakeChannel{
+		exchanges: make(map[string]string),
+		queues:    make(map[string]bool),
+		bindings:  make(map[string][]string),
+	}
+}
+
+func (f *fakeChannel) ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error {
+	f.exchanges[name] = kind
+	return nil
+}
+
+func (f *fakeChannel) QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+	f.queues[name] = true
+	return amqp.Queue{Name: name}, nil
+}
+
+func (f *fakeChannel) QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error {
+	f.bindings[exchange+"/"+key] = append(f.bindings[exchange+"/"+key], name)
+	return nil
+}
+
+// route повторяет маршрутизацию topic exchange для ключей без шаблонов.
+func (f *fakeChannel) route(exchange, key string) []string {
+	return f.bindings[exchange+"/"+key]
+}
+
+func TestProducerDeclaresOnlyExchanges(t *testing.T) {
+	ch := newFakeChannel()
+	if err := OrderProducer.Declare(ch); err != nil {
+		t.Fatalf("Declare: %v", err)
+	}
+
+	if kind := ch.exchanges[OrderExchange]; kind != "topic" {
+		t.Fatalf("expected topic exchange %q, got %q", OrderExchange, kind)
+	}
+	if len(ch.queues) != 0 {
+		t.Fatalf("producer must not declare queues, got %v", ch.queues)
+	}
+}
+
+func TestConsumerBindsEveryPublishedEvent(t *testing.T) {
+	ch := newFakeChannel()
+	if err := OrderProducer.Declare(ch); err != nil {
+		t.Fatalf("Declare producer: %v", err)
+	}
+	if err := NotificationConsumer.Declare(ch); err != nil {
+		t.Fatalf("Declare consumer: %v", err)
+	}
+
+	for _, key := range OrderPublishes {
+		if len(ch.route(OrderExchange, key)) == 0 {
+			t.Errorf("event %q published by order service is not bound to any queue", key)
+		}
+	}
+}
+
+func TestConsumerBindsToProducerExchange(t *testing.T) {
+	exchanges := make(map[string]Exchange)
+	for _, e := range OrderProducer.Exchanges {
+		exchanges[e.Name] = e
+	}
+
+	for _, e := range NotificationConsumer.Exchanges {
+		if declared, ok := exchanges[e.Name]; ok && declared != e {
+			t.Errorf("exchange %q is declared differently: producer %+v, consumer %+v", e.Name, declared, e)
+		}
+	}
+
+	for _, q := range NotificationConsumer.Queues {
+		for _, b := range q.Bindings {
+			if _, ok := exchanges[b.Exchange]; !ok {
+				t.Errorf("queue %q is bound to exchange %q the producer does not declare", q.Name, b.Exchange)
+			}
+		}
+	}
+}